@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestLRUGetSetDelete(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRU(10)
+
+	if _, ok, err := store.Get(ctx, "book:1"); ok || err != nil {
+		t.Fatalf("Get on an empty store = %v, %v, want a miss", ok, err)
+	}
+
+	store.Set(ctx, "book:1", []byte("dune"), 0)
+	store.Set(ctx, "book:1", []byte("dune messiah"), 0)
+
+	if value, ok, _ := store.Get(ctx, "book:1"); !ok || string(value) != "dune messiah" {
+		t.Fatalf("Get = %q, %v, want the last value set", value, ok)
+	}
+
+	store.Delete(ctx, "book:1", "book:2")
+
+	if _, ok, _ := store.Get(ctx, "book:1"); ok {
+		t.Error("Get after Delete hit, want a miss")
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := NewLRU(10)
+	store.now = func() time.Time { return now }
+
+	store.Set(ctx, "short", []byte("a"), time.Minute)
+	store.Set(ctx, "forever", []byte("b"), 0)
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := store.Get(ctx, "short"); !ok {
+		t.Error("Get at the expiry missed, want the entry kept until then")
+	}
+
+	now = now.Add(time.Second)
+	if _, ok, _ := store.Get(ctx, "short"); ok {
+		t.Error("Get after the expiry hit, want a miss")
+	}
+
+	if _, ok, _ := store.Get(ctx, "forever"); !ok {
+		t.Error("Get of an entry without ttl missed")
+	}
+
+	if len(store.entries) != 1 || store.order.Len() != 1 {
+		t.Errorf("entries = %d, list = %d, want the expired entry dropped", len(store.entries), store.order.Len())
+	}
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRU(2)
+
+	store.Set(ctx, "a", []byte("a"), 0)
+	store.Set(ctx, "b", []byte("b"), 0)
+	store.Get(ctx, "a")
+	store.Set(ctx, "c", []byte("c"), 0)
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		if _, ok, _ := store.Get(ctx, key); ok != want {
+			t.Errorf("Get(%s) hit = %v, want %v", key, ok, want)
+		}
+	}
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	store := NewRedis(RedisConfig{Addr: server.Addr(), Prefix: "library:"})
+	defer store.Close()
+
+	if err := store.Ping(ctx); err != nil {
+		t.Fatalf("Ping: %v", err)
+	}
+
+	if _, ok, err := store.Get(ctx, "book:1"); ok || err != nil {
+		t.Fatalf("Get on an empty store = %v, %v, want a miss", ok, err)
+	}
+
+	if err := store.Set(ctx, "book:1", []byte("dune"), time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	store.Set(ctx, "book:2", []byte("emma"), 0)
+
+	if value, ok, err := store.Get(ctx, "book:1"); !ok || err != nil || string(value) != "dune" {
+		t.Fatalf("Get = %q, %v, %v, want the value set", value, ok, err)
+	}
+
+	if !server.Exists("library:book:1") || server.Exists("book:1") {
+		t.Error("the key is not stored under the prefix")
+	}
+
+	if ttl := server.TTL("library:book:1"); ttl != time.Minute {
+		t.Errorf("ttl = %v, want 1m", ttl)
+	}
+
+	if ttl := server.TTL("library:book:2"); ttl != 0 {
+		t.Errorf("ttl without expiry = %v, want none", ttl)
+	}
+
+	server.FastForward(time.Minute)
+	if _, ok, _ := store.Get(ctx, "book:1"); ok {
+		t.Error("Get after the ttl hit, want a miss")
+	}
+
+	if err := store.Delete(ctx, "book:2", "book:3"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, ok, _ := store.Get(ctx, "book:2"); ok {
+		t.Error("Get after Delete hit, want a miss")
+	}
+
+	server.Close()
+	if _, _, err := store.Get(ctx, "book:2"); err == nil {
+		t.Error("Get with the server down returned no error")
+	}
+}
@@ -98,7 +98,14 @@ func (h AuthorHandler) GetAuthors(w http.ResponseWriter, r *http.Request) {
 
 	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: name})
 
-	categories, err := h.authorUC.GetAllAuthors(ctx, name)
+	similarity, err := parseSimilarity(r.URL.Query())
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: name, Message: "parseSimilarity got an error on AuthorHandler.GetAuthors"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	categories, err := h.authorUC.GetAllAuthors(ctx, name, similarity)
 	if err != nil {
 		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: name, Message: "h.authorUC.GetAllAuthors got an error on AuthorHandler.GetAuthors"})
 		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
@@ -125,3 +132,62 @@ func (h AuthorHandler) DeleteAuthorByID(w http.ResponseWriter, r *http.Request)
 
 	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to DeleteAuthorByID", Code: http.StatusOK, Success: true})
 }
+
+// BatchAuthors applies a list of create, update and delete operations. An atomic batch that fails
+// is rolled back and answered with 400; a best-effort batch always answers 200 with the result
+// of every operation.
+func (h AuthorHandler) BatchAuthors(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+
+	var input author.AuthorBatchInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on AuthorHandler.BatchAuthors"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: input})
+
+	resp, err := h.authorUC.BatchAuthors(ctx, input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: input, Message: "h.authorUC.BatchAuthors got an error on AuthorHandler.BatchAuthors"})
+		if resp.Results == nil {
+			api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+			return
+		}
+
+		api.APIResponse(w, api.Response{Meta: api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false}, Data: resp})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to BatchAuthors", Code: http.StatusOK, Success: true}, Data: resp})
+}
+
+// PatchAuthor applies a JSON Merge Patch (RFC 7396) to the author: fields left out are kept and
+// null clears a field.
+func (h AuthorHandler) PatchAuthor(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	patch, code, err := readMergePatch(r)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "readMergePatch got an error on AuthorHandler.PatchAuthor"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: code, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: string(patch)})
+
+	err = h.authorUC.PatchAuthor(ctx, int64(idInt), patch)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: string(patch), Message: "h.authorUC.PatchAuthor got an error on AuthorHandler.PatchAuthor"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to PatchAuthor", Code: http.StatusOK, Success: true})
+}
@@ -2,8 +2,12 @@ package delivery
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"time"
 
 	api "github.com/book-library/app/helper"
 	u "github.com/book-library/app/usecase"
@@ -91,16 +95,56 @@ func (h BookHandler) GetBookById(w http.ResponseWriter, r *http.Request) {
 	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to GetBookById", Code: http.StatusOK, Success: true}, Data: catById})
 }
 
+func (h BookHandler) GetSimilarBooks(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: idInt})
+
+	books, err := h.bookUC.GetSimilarBooks(ctx, int64(idInt), limit)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: idInt, Message: "h.bookUC.GetSimilarBooks got an error on BookHandler.GetSimilarBooks"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to GetSimilarBooks", Code: http.StatusOK, Success: true}, Data: books})
+}
+
 func (h BookHandler) GetBooks(w http.ResponseWriter, r *http.Request) {
 	log := log.With().Str("request_id", uuid.New().String()).Logger()
 	ctx := log.WithContext(r.Context())
-	name := r.URL.Query().Get("name")
+	query := r.URL.Query()
 
-	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: name})
+	search, err := parseBookSearch(query)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: query, Message: "parseBookSearch got an error on BookHandler.GetBooks"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: search})
+
+	if query.Get("collapse") == "work" {
+		expand, _ := strconv.ParseBool(query.Get("expand"))
+
+		groups, err := h.bookUC.GetAllBooksByWork(ctx, search, expand)
+		if err != nil {
+			logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: search, Message: "h.bookUC.GetAllBooksByWork got an error on BookHandler.GetBooks"})
+			api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+			return
+		}
+
+		api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to GetBooks", Code: http.StatusOK, Success: true}, Data: groups})
+		return
+	}
 
-	books, err := h.bookUC.GetAllBooks(ctx, name)
+	books, err := h.bookUC.GetAllBooks(ctx, search)
 	if err != nil {
-		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: name, Message: "h.bookUC.GetAllBooks got an error on BookHandler.GetBooks"})
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: search, Message: "h.bookUC.GetAllBooks got an error on BookHandler.GetBooks"})
 		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
 		return
 	}
@@ -108,6 +152,106 @@ func (h BookHandler) GetBooks(w http.ResponseWriter, r *http.Request) {
 	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to GetBooks", Code: http.StatusOK, Success: true}, Data: books})
 }
 
+func (h BookHandler) SearchBooks(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	query := r.URL.Query()
+
+	search, err := parseBookSearch(query)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: query, Message: "parseBookSearch got an error on BookHandler.SearchBooks"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: search})
+
+	result, err := h.bookUC.SearchBooks(ctx, search)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: search, Message: "h.bookUC.SearchBooks got an error on BookHandler.SearchBooks"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to SearchBooks", Code: http.StatusOK, Success: true}, Data: result})
+}
+
+// parseBookSearch reads the book list filters from query. Dates are RFC 3339 timestamps or
+// YYYY-MM-DD days; a day given as an upper bound includes the whole day.
+func parseBookSearch(query url.Values) (search book.BookSearch, err error) {
+	publisherID, _ := strconv.Atoi(query.Get("publisher_id"))
+	authorID, _ := strconv.Atoi(query.Get("author_id"))
+	categoryID, _ := strconv.Atoi(query.Get("category_id"))
+	yearFrom, _ := strconv.Atoi(query.Get("year_from"))
+	yearTo, _ := strconv.Atoi(query.Get("year_to"))
+
+	search = book.BookSearch{
+		Search:              query.Get("name"),
+		PublisherID:         int64(publisherID),
+		PublicationYearFrom: yearFrom,
+		PublicationYearTo:   yearTo,
+		AuthorID:            int64(authorID),
+		CategoryID:          int64(categoryID),
+		Published:           query.Get("published"),
+		ISBNPrefix:          query.Get("isbn_prefix"),
+		TitleContains:       query.Get("title"),
+		Sort:                query.Get("sort"),
+	}
+
+	if search.Similarity, err = parseSimilarity(query); err != nil {
+		return search, err
+	}
+
+	dates := []struct {
+		param string
+		upper bool
+		dst   **time.Time
+	}{
+		{"created_from", false, &search.CreatedFrom},
+		{"created_to", true, &search.CreatedTo},
+		{"updated_from", false, &search.UpdatedFrom},
+		{"updated_to", true, &search.UpdatedTo},
+	}
+
+	for _, date := range dates {
+		value := query.Get(date.param)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			parsed, err = time.Parse(time.DateOnly, value)
+			if err != nil {
+				return search, fmt.Errorf("%s must be an RFC 3339 timestamp or a YYYY-MM-DD date", date.param)
+			}
+
+			if date.upper {
+				parsed = parsed.AddDate(0, 0, 1)
+			}
+		}
+
+		*date.dst = &parsed
+	}
+
+	return search, nil
+}
+
+// parseSimilarity reads the trigram similarity threshold that turns on fuzzy name matching.
+func parseSimilarity(query url.Values) (float64, error) {
+	value := query.Get("similarity")
+	if value == "" {
+		return 0, nil
+	}
+
+	similarity, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, errors.New("similarity must be a number between 0 and 1")
+	}
+
+	return similarity, nil
+}
+
 func (h BookHandler) DeleteBookyByID(w http.ResponseWriter, r *http.Request) {
 	log := log.With().Str("request_id", uuid.New().String()).Logger()
 	ctx := log.WithContext(r.Context())
@@ -125,3 +269,103 @@ func (h BookHandler) DeleteBookyByID(w http.ResponseWriter, r *http.Request) {
 
 	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to DeleteBookyByID", Code: http.StatusOK, Success: true})
 }
+
+// BatchBooks applies a list of create, update and delete operations. An atomic batch that fails
+// is rolled back and answered with 400; a best-effort batch always answers 200 with the result
+// of every operation.
+func (h BookHandler) BatchBooks(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+
+	var input book.BookBatchInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on BookHandler.BatchBooks"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: input})
+
+	resp, err := h.bookUC.BatchBooks(ctx, input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: input, Message: "h.bookUC.BatchBooks got an error on BookHandler.BatchBooks"})
+		if resp.Results == nil {
+			api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+			return
+		}
+
+		api.APIResponse(w, api.Response{Meta: api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false}, Data: resp})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to BatchBooks", Code: http.StatusOK, Success: true}, Data: resp})
+}
+
+// ExportBooks returns every book in the format ImportBooks reads back.
+func (h BookHandler) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx})
+
+	resp, err := h.bookUC.ExportBooks(ctx)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "h.bookUC.ExportBooks got an error on BookHandler.ExportBooks"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to ExportBooks", Code: http.StatusOK, Success: true}, Data: resp})
+}
+
+func (h BookHandler) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+
+	var input book.BookExport
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on BookHandler.ImportBooks"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: len(input.Books)})
+
+	resp, err := h.bookUC.ImportBooks(ctx, input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: len(input.Books), Message: "h.bookUC.ImportBooks got an error on BookHandler.ImportBooks"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to ImportBooks", Code: http.StatusOK, Success: true}, Data: resp})
+}
+
+// PatchBook applies a JSON Merge Patch (RFC 7396) to the book: fields left out are kept and
+// null clears a field.
+func (h BookHandler) PatchBook(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	patch, code, err := readMergePatch(r)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "readMergePatch got an error on BookHandler.PatchBook"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: code, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: string(patch)})
+
+	err = h.bookUC.PatchBook(ctx, int64(idInt), patch)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: string(patch), Message: "h.bookUC.PatchBook got an error on BookHandler.PatchBook"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to PatchBook", Code: http.StatusOK, Success: true})
+}
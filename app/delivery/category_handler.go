@@ -98,7 +98,14 @@ func (h CategoryHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
 
 	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: name})
 
-	categories, err := h.categoryUC.GetAllCategories(ctx, name)
+	similarity, err := parseSimilarity(r.URL.Query())
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: name, Message: "parseSimilarity got an error on CategoryHandler.GetCategories"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	categories, err := h.categoryUC.GetAllCategories(ctx, name, similarity)
 	if err != nil {
 		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: name, Message: "h.categoryUC.GetAllCategories got an error on CategoryHandler.GetCategories"})
 		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
@@ -125,3 +132,62 @@ func (h CategoryHandler) DeleteCategoryByID(w http.ResponseWriter, r *http.Reque
 
 	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to DeleteCategoryByID", Code: http.StatusOK, Success: true})
 }
+
+// BatchCategories applies a list of create, update and delete operations. An atomic batch that fails
+// is rolled back and answered with 400; a best-effort batch always answers 200 with the result
+// of every operation.
+func (h CategoryHandler) BatchCategories(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+
+	var input category.CategoryBatchInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on CategoryHandler.BatchCategories"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: input})
+
+	resp, err := h.categoryUC.BatchCategories(ctx, input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: input, Message: "h.categoryUC.BatchCategories got an error on CategoryHandler.BatchCategories"})
+		if resp.Results == nil {
+			api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+			return
+		}
+
+		api.APIResponse(w, api.Response{Meta: api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false}, Data: resp})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to BatchCategories", Code: http.StatusOK, Success: true}, Data: resp})
+}
+
+// PatchCategory applies a JSON Merge Patch (RFC 7396) to the category: fields left out are kept and
+// null clears a field.
+func (h CategoryHandler) PatchCategory(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	patch, code, err := readMergePatch(r)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "readMergePatch got an error on CategoryHandler.PatchCategory"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: code, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: string(patch)})
+
+	err = h.categoryUC.PatchCategory(ctx, int64(idInt), patch)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: string(patch), Message: "h.categoryUC.PatchCategory got an error on CategoryHandler.PatchCategory"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to PatchCategory", Code: http.StatusOK, Success: true})
+}
@@ -0,0 +1,224 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	api "github.com/book-library/app/helper"
+	u "github.com/book-library/app/usecase"
+	"github.com/book-library/entity/collection"
+	"github.com/book-library/logger"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type CollectionHandler struct {
+	collectionUC u.CollectionServiceI
+}
+
+func NewCollectionHandler(collectionUC u.CollectionServiceI) CollectionHandler {
+	return CollectionHandler{
+		collectionUC: collectionUC,
+	}
+}
+
+func (h CollectionHandler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+
+	var input collection.CollectionInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on CollectionHandler.CreateCollection"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: input})
+
+	err = h.collectionUC.CreateCollection(ctx, input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: input, Message: "h.collectionUC.CreateCollection got an error on CollectionHandler.CreateCollection"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to CreateCollection", Code: http.StatusOK, Success: true})
+}
+
+func (h CollectionHandler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	var input collection.CollectionInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on CollectionHandler.UpdateCollection"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: input})
+
+	err = h.collectionUC.UpdateCollection(ctx, int64(idInt), input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: input, Message: "h.collectionUC.UpdateCollection got an error on CollectionHandler.UpdateCollection"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to UpdateCollection", Code: http.StatusOK, Success: true})
+}
+
+func (h CollectionHandler) GetCollectionById(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	memberID, _ := strconv.Atoi(r.Header.Get(collection.MemberHeader))
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: idInt})
+
+	collectionById, err := h.collectionUC.GetCollectionByID(ctx, int64(idInt), int64(memberID))
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: idInt, Message: "h.collectionUC.GetCollectionById got an error on CollectionHandler.GetCollectionById"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to GetCollectionById", Code: http.StatusOK, Success: true}, Data: collectionById})
+}
+
+func (h CollectionHandler) GetCollections(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	query := r.URL.Query()
+	ownerID, _ := strconv.Atoi(query.Get("owner_id"))
+	memberID, _ := strconv.Atoi(r.Header.Get(collection.MemberHeader))
+
+	search := collection.CollectionSearch{
+		Name:       query.Get("name"),
+		OwnerID:    int64(ownerID),
+		Visibility: query.Get("visibility"),
+		MemberID:   int64(memberID),
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: search})
+
+	collections, err := h.collectionUC.GetAllCollections(ctx, search)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: search, Message: "h.collectionUC.GetAllCollections got an error on CollectionHandler.GetCollections"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to GetCollections", Code: http.StatusOK, Success: true}, Data: collections})
+}
+
+func (h CollectionHandler) GetFeaturedCollections(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx})
+
+	collections, err := h.collectionUC.GetFeaturedCollections(ctx)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "h.collectionUC.GetFeaturedCollections got an error on CollectionHandler.GetFeaturedCollections"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to GetFeaturedCollections", Code: http.StatusOK, Success: true}, Data: collections})
+}
+
+func (h CollectionHandler) DeleteCollectionByID(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: idInt})
+
+	err := h.collectionUC.DeleteCollectionByID(ctx, int64(idInt))
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: idInt, Message: "h.collectionUC.DeleteCollectionByID got an error on CollectionHandler.DeleteCollectionByID"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to DeleteCollectionByID", Code: http.StatusOK, Success: true})
+}
+
+func (h CollectionHandler) AddCollectionBook(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	var input collection.CollectionEntryInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on CollectionHandler.AddCollectionBook"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: input})
+
+	err = h.collectionUC.AddCollectionBook(ctx, int64(idInt), input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: input, Message: "h.collectionUC.AddCollectionBook got an error on CollectionHandler.AddCollectionBook"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to AddCollectionBook", Code: http.StatusOK, Success: true})
+}
+
+func (h CollectionHandler) UpdateCollectionBook(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	idInt, _ := strconv.Atoi(r.PathValue("id"))
+	bookIDInt, _ := strconv.Atoi(r.PathValue("book_id"))
+
+	var input collection.CollectionEntryInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on CollectionHandler.UpdateCollectionBook"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: input})
+
+	err = h.collectionUC.UpdateCollectionBook(ctx, int64(idInt), int64(bookIDInt), input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: input, Message: "h.collectionUC.UpdateCollectionBook got an error on CollectionHandler.UpdateCollectionBook"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to UpdateCollectionBook", Code: http.StatusOK, Success: true})
+}
+
+func (h CollectionHandler) RemoveCollectionBook(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	idInt, _ := strconv.Atoi(r.PathValue("id"))
+	bookIDInt, _ := strconv.Atoi(r.PathValue("book_id"))
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: bookIDInt})
+
+	err := h.collectionUC.RemoveCollectionBook(ctx, int64(idInt), int64(bookIDInt))
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: bookIDInt, Message: "h.collectionUC.RemoveCollectionBook got an error on CollectionHandler.RemoveCollectionBook"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to RemoveCollectionBook", Code: http.StatusOK, Success: true})
+}
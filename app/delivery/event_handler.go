@@ -0,0 +1,106 @@
+package delivery
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/book-library/app/helper"
+	u "github.com/book-library/app/usecase"
+	"github.com/book-library/entity/outbox"
+	"github.com/book-library/logger"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const eventStreamKeepAlive = 15 * time.Second
+
+type EventHandler struct {
+	eventStreamUC u.EventStreamServiceI
+}
+
+func NewEventHandler(eventStreamUC u.EventStreamServiceI) EventHandler {
+	return EventHandler{
+		eventStreamUC: eventStreamUC,
+	}
+}
+
+// StreamEvents serves catalog changes as Server-Sent Events. The optional type query takes a
+// comma separated list of entity types; Last-Event-ID (or last_event_id) resumes a stream.
+func (h EventHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+
+	var aggregateTypes []string
+	if types := r.URL.Query().Get("type"); types != "" {
+		aggregateTypes = strings.Split(types, ",")
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	lastEventIDInt, _ := strconv.ParseInt(lastEventID, 10, 64)
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: aggregateTypes})
+
+	events, err := h.eventStreamUC.Stream(ctx, lastEventIDInt, aggregateTypes)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: aggregateTypes, Message: "h.eventStreamUC.Stream got an error on EventHandler.StreamEvents"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	rc.Flush()
+
+	keepAlive := time.NewTicker(eventStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			err = writeStreamEvent(w, event)
+		}
+
+		if err == nil {
+			err = rc.Flush()
+		}
+
+		if err != nil {
+			logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "writing the stream got an error on EventHandler.StreamEvents"})
+			return
+		}
+	}
+}
+
+func writeStreamEvent(w http.ResponseWriter, event outbox.OutboxEvent) error {
+	data, err := json.Marshal(outbox.StreamEvent{
+		ID:            event.ID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		OccurredAt:    event.CreatedAt.UTC(),
+		Data:          json.RawMessage(event.Payload),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.PublishedSeq, event.EventType, data)
+
+	return err
+}
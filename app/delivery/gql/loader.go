@@ -0,0 +1,129 @@
+package gql
+
+import (
+	"context"
+	"sync"
+
+	u "github.com/book-library/app/usecase"
+	"github.com/book-library/entity/author"
+	"github.com/book-library/entity/category"
+	"github.com/book-library/entity/review"
+)
+
+// batchLoader collects the keys asked for while a query level resolves and fetches them
+// with one call the first time any of their thunks is run.
+type batchLoader[K comparable, V any] struct {
+	mu      sync.Mutex
+	fetch   func(ctx context.Context, keys []K) (map[K]V, error)
+	pending []K
+	results map[K]V
+	errs    map[K]error
+}
+
+func newBatchLoader[K comparable, V any](fetch func(ctx context.Context, keys []K) (map[K]V, error)) *batchLoader[K, V] {
+	return &batchLoader[K, V]{
+		fetch:   fetch,
+		results: map[K]V{},
+		errs:    map[K]error{},
+	}
+}
+
+// Load queues key and returns a thunk that graphql-go runs after the rest of the level resolved.
+func (l *batchLoader[K, V]) Load(ctx context.Context, key K) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, ok := l.results[key]; !ok {
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			keys := l.pending
+			l.pending = nil
+
+			values, err := l.fetch(ctx, keys)
+			for _, k := range keys {
+				if err != nil {
+					l.errs[k] = err
+					continue
+				}
+				l.results[k] = values[k]
+			}
+		}
+
+		if err := l.errs[key]; err != nil {
+			return nil, err
+		}
+
+		return l.results[key], nil
+	}
+}
+
+type loaders struct {
+	authors    *batchLoader[int64, *author.AuthorResponse]
+	categories *batchLoader[int64, *category.CategoryResponse]
+	reviews    *batchLoader[int64, []review.ReviewResponse]
+}
+
+type loadersKey struct{}
+
+func newLoaders(authorUC u.AuthorServiceI, categoryUC u.CategoryServiceI, reviewUC u.ReviewServiceI) *loaders {
+	return &loaders{
+		authors: newBatchLoader(func(ctx context.Context, ids []int64) (map[int64]*author.AuthorResponse, error) {
+			authors, err := authorUC.GetAuthorsByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			resp := map[int64]*author.AuthorResponse{}
+			for i := range authors {
+				resp[authors[i].ID] = &authors[i]
+			}
+
+			return resp, nil
+		}),
+		categories: newBatchLoader(func(ctx context.Context, ids []int64) (map[int64]*category.CategoryResponse, error) {
+			categories, err := categoryUC.GetCategoriesByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			resp := map[int64]*category.CategoryResponse{}
+			for i := range categories {
+				resp[categories[i].ID] = &categories[i]
+			}
+
+			return resp, nil
+		}),
+		reviews: newBatchLoader(func(ctx context.Context, bookIDs []int64) (map[int64][]review.ReviewResponse, error) {
+			reviews, err := reviewUC.GetAllReviews(ctx, review.ReviewSearch{BookIDs: bookIDs, Status: review.StatusApproved})
+			if err != nil {
+				return nil, err
+			}
+
+			resp := map[int64][]review.ReviewResponse{}
+			for _, bookID := range bookIDs {
+				resp[bookID] = []review.ReviewResponse{}
+			}
+
+			for _, r := range reviews {
+				resp[r.BookID] = append(resp[r.BookID], r)
+			}
+
+			return resp, nil
+		}),
+	}
+}
+
+// WithLoaders gives every request its own loaders so batches and cached rows never leak
+// between requests.
+func WithLoaders(ctx context.Context, authorUC u.AuthorServiceI, categoryUC u.CategoryServiceI, reviewUC u.ReviewServiceI) context.Context {
+	return context.WithValue(ctx, loadersKey{}, newLoaders(authorUC, categoryUC, reviewUC))
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
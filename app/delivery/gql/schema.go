@@ -0,0 +1,501 @@
+// Package gql exposes the catalog over GraphQL on top of the same usecases as the REST API.
+package gql
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	u "github.com/book-library/app/usecase"
+	"github.com/book-library/entity/author"
+	"github.com/book-library/entity/book"
+	"github.com/book-library/entity/category"
+	"github.com/book-library/entity/review"
+	"github.com/graphql-go/graphql"
+)
+
+// field builds a field whose value is read from the parent object of type T.
+func field[T any](typ graphql.Output, get func(T) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: typ,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			switch source := p.Source.(type) {
+			case T:
+				return get(source), nil
+			case *T:
+				if source == nil {
+					return nil, nil
+				}
+				return get(*source), nil
+			}
+
+			return nil, nil
+		},
+	}
+}
+
+func idOf(id int64) interface{} {
+	return strconv.FormatInt(id, 10)
+}
+
+func timeOf(t time.Time) interface{} {
+	return t
+}
+
+func optionalTimeOf(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+
+	return *t
+}
+
+func idArg(args map[string]interface{}, name string) (id int64, err error) {
+	value, ok := args[name].(string)
+	if !ok {
+		return 0, nil
+	}
+
+	id, err = strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.New(name + " must be a numeric id")
+	}
+
+	return id, nil
+}
+
+func optionalIDArg(args map[string]interface{}, name string) (id *int64, err error) {
+	if _, ok := args[name]; !ok {
+		return nil, nil
+	}
+
+	value, err := idArg(args, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
+
+func stringArg(args map[string]interface{}, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+func intArg(args map[string]interface{}, name string) int {
+	value, _ := args[name].(int)
+	return value
+}
+
+func floatArg(args map[string]interface{}, name string) float64 {
+	value, _ := args[name].(float64)
+	return value
+}
+
+// NewSchema builds the GraphQL schema. Author, category and review lookups on a book are
+// batched per query level through the request loaders, see WithLoaders.
+func NewSchema(bookUC u.BookLibraryServiceI, authorUC u.AuthorServiceI, categoryUC u.CategoryServiceI) (graphql.Schema, error) {
+	authorType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Author",
+		Fields: graphql.Fields{
+			"id":        field(graphql.NewNonNull(graphql.ID), func(a author.AuthorResponse) interface{} { return idOf(a.ID) }),
+			"name":      field(graphql.NewNonNull(graphql.String), func(a author.AuthorResponse) interface{} { return a.Name }),
+			"email":     field(graphql.NewNonNull(graphql.String), func(a author.AuthorResponse) interface{} { return a.Email }),
+			"createdAt": field(graphql.DateTime, func(a author.AuthorResponse) interface{} { return timeOf(a.CreatedAt) }),
+			"updatedAt": field(graphql.DateTime, func(a author.AuthorResponse) interface{} { return optionalTimeOf(a.UpdatedAt) }),
+		},
+	})
+
+	categoryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Category",
+		Fields: graphql.Fields{
+			"id":          field(graphql.NewNonNull(graphql.ID), func(c category.CategoryResponse) interface{} { return idOf(c.ID) }),
+			"name":        field(graphql.NewNonNull(graphql.String), func(c category.CategoryResponse) interface{} { return c.Name }),
+			"description": field(graphql.NewNonNull(graphql.String), func(c category.CategoryResponse) interface{} { return c.Description }),
+			"createdAt":   field(graphql.DateTime, func(c category.CategoryResponse) interface{} { return timeOf(c.CreatedAt) }),
+			"updatedAt":   field(graphql.DateTime, func(c category.CategoryResponse) interface{} { return optionalTimeOf(c.UpdatedAt) }),
+		},
+	})
+
+	reviewType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Review",
+		Fields: graphql.Fields{
+			"id":         field(graphql.NewNonNull(graphql.ID), func(r review.ReviewResponse) interface{} { return idOf(r.ID) }),
+			"memberId":   field(graphql.NewNonNull(graphql.ID), func(r review.ReviewResponse) interface{} { return idOf(r.MemberID) }),
+			"memberName": field(graphql.NewNonNull(graphql.String), func(r review.ReviewResponse) interface{} { return r.MemberName }),
+			"rating":     field(graphql.NewNonNull(graphql.Int), func(r review.ReviewResponse) interface{} { return r.Rating }),
+			"review":     field(graphql.NewNonNull(graphql.String), func(r review.ReviewResponse) interface{} { return r.Review }),
+			"createdAt":  field(graphql.DateTime, func(r review.ReviewResponse) interface{} { return timeOf(r.CreatedAt) }),
+		},
+	})
+
+	bookType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Book",
+		Fields: graphql.Fields{
+			"id":              field(graphql.NewNonNull(graphql.ID), func(b book.BookResponseDetail) interface{} { return idOf(b.ID) }),
+			"title":           field(graphql.NewNonNull(graphql.String), func(b book.BookResponseDetail) interface{} { return b.Title }),
+			"description":     field(graphql.NewNonNull(graphql.String), func(b book.BookResponseDetail) interface{} { return b.Description }),
+			"isbn":            field(graphql.NewNonNull(graphql.String), func(b book.BookResponseDetail) interface{} { return b.ISBN }),
+			"publishedFlag":   field(graphql.NewNonNull(graphql.Boolean), func(b book.BookResponseDetail) interface{} { return b.PublishedFlag }),
+			"publicationYear": field(graphql.Int, func(b book.BookResponseDetail) interface{} { return b.PublicationYear }),
+			"edition":         field(graphql.String, func(b book.BookResponseDetail) interface{} { return b.Edition }),
+			"pageCount":       field(graphql.Int, func(b book.BookResponseDetail) interface{} { return b.PageCount }),
+			"language":        field(graphql.String, func(b book.BookResponseDetail) interface{} { return b.Language }),
+			"ratingAverage":   field(graphql.NewNonNull(graphql.Float), func(b book.BookResponseDetail) interface{} { return b.RatingAverage }),
+			"ratingCount":     field(graphql.NewNonNull(graphql.Int), func(b book.BookResponseDetail) interface{} { return b.RatingCount }),
+			"createdAt":       field(graphql.DateTime, func(b book.BookResponseDetail) interface{} { return timeOf(b.CreatedAt) }),
+			"updatedAt":       field(graphql.DateTime, func(b book.BookResponseDetail) interface{} { return optionalTimeOf(b.UpdatedAt) }),
+			"author": &graphql.Field{
+				Type: authorType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					b := p.Source.(book.BookResponseDetail)
+					return loadersFrom(p.Context).authors.Load(p.Context, b.Author.ID), nil
+				},
+			},
+			"category": &graphql.Field{
+				Type: categoryType,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					b := p.Source.(book.BookResponseDetail)
+					return loadersFrom(p.Context).categories.Load(p.Context, b.Category.ID), nil
+				},
+			},
+			"reviews": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reviewType))),
+				Description: "Approved reviews, newest first.",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					b := p.Source.(book.BookResponseDetail)
+					return loadersFrom(p.Context).reviews.Load(p.Context, b.ID), nil
+				},
+			},
+		},
+	})
+
+	bookInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "BookInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":           &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"description":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"isbn":            &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"authorId":        &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"categoryId":      &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"publisherId":     &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"seriesId":        &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"seriesVolume":    &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"workId":          &graphql.InputObjectFieldConfig{Type: graphql.ID},
+			"publishedFlag":   &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+			"publicationYear": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"edition":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"pageCount":       &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			"language":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	authorInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "AuthorInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":  &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"email": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	categoryInputType := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CategoryInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        &graphql.InputObjectFieldConfig{Type: graphql.String},
+			"description": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		},
+	})
+
+	idArgs := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+
+	nameArgs := graphql.FieldConfigArgument{
+		"name":       &graphql.ArgumentConfig{Type: graphql.String},
+		"similarity": &graphql.ArgumentConfig{Type: graphql.Float},
+	}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"book": &graphql.Field{
+				Type: bookType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return bookUC.GetBookByID(p.Context, id)
+				},
+			},
+			"books": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(bookType))),
+				Args: graphql.FieldConfigArgument{
+					"search":      &graphql.ArgumentConfig{Type: graphql.String},
+					"publisherId": &graphql.ArgumentConfig{Type: graphql.ID},
+					"yearFrom":    &graphql.ArgumentConfig{Type: graphql.Int},
+					"yearTo":      &graphql.ArgumentConfig{Type: graphql.Int},
+					"sort":        &graphql.ArgumentConfig{Type: graphql.String},
+					"similarity":  &graphql.ArgumentConfig{Type: graphql.Float},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					publisherID, err := idArg(p.Args, "publisherId")
+					if err != nil {
+						return nil, err
+					}
+
+					books, err := bookUC.GetAllBooks(p.Context, book.BookSearch{
+						Search:              stringArg(p.Args, "search"),
+						PublisherID:         publisherID,
+						PublicationYearFrom: intArg(p.Args, "yearFrom"),
+						PublicationYearTo:   intArg(p.Args, "yearTo"),
+						Sort:                stringArg(p.Args, "sort"),
+						Similarity:          floatArg(p.Args, "similarity"),
+					})
+					if books == nil {
+						books = []book.BookResponseDetail{}
+					}
+
+					return books, err
+				},
+			},
+			"author": &graphql.Field{
+				Type: authorType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return authorUC.GetAuthorByID(p.Context, id)
+				},
+			},
+			"authors": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(authorType))),
+				Args: nameArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					authors, err := authorUC.GetAllAuthors(p.Context, stringArg(p.Args, "name"), floatArg(p.Args, "similarity"))
+					if authors == nil {
+						authors = []author.AuthorResponse{}
+					}
+
+					return authors, err
+				},
+			},
+			"category": &graphql.Field{
+				Type: categoryType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return nil, err
+					}
+
+					return categoryUC.GetCategoryByID(p.Context, id)
+				},
+			},
+			"categories": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+				Args: nameArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					categories, err := categoryUC.GetAllCategories(p.Context, stringArg(p.Args, "name"), floatArg(p.Args, "similarity"))
+					if categories == nil {
+						categories = []category.CategoryResponse{}
+					}
+
+					return categories, err
+				},
+			},
+		},
+	})
+
+	inputArgs := func(inputType graphql.Input, withID bool) graphql.FieldConfigArgument {
+		args := graphql.FieldConfigArgument{
+			"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(inputType)},
+		}
+		if withID {
+			args["id"] = &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)}
+		}
+
+		return args
+	}
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createBook": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: inputArgs(bookInputType, false),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					input, err := bookInputOf(p.Args["input"].(map[string]interface{}))
+					if err != nil {
+						return false, err
+					}
+
+					err = bookUC.CreateBook(p.Context, input)
+					return err == nil, err
+				},
+			},
+			"updateBook": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: inputArgs(bookInputType, true),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return false, err
+					}
+
+					input, err := bookInputOf(p.Args["input"].(map[string]interface{}))
+					if err != nil {
+						return false, err
+					}
+
+					err = bookUC.UpdateBook(p.Context, id, input)
+					return err == nil, err
+				},
+			},
+			"deleteBook": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return false, err
+					}
+
+					err = bookUC.DeleteBookByID(p.Context, id)
+					return err == nil, err
+				},
+			},
+			"createAuthor": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: inputArgs(authorInputType, false),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					err := authorUC.CreateAuthor(p.Context, authorInputOf(p.Args["input"].(map[string]interface{})))
+					return err == nil, err
+				},
+			},
+			"updateAuthor": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: inputArgs(authorInputType, true),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return false, err
+					}
+
+					err = authorUC.UpdateAuthor(p.Context, id, authorInputOf(p.Args["input"].(map[string]interface{})))
+					return err == nil, err
+				},
+			},
+			"deleteAuthor": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return false, err
+					}
+
+					err = authorUC.DeleteAuthorByID(p.Context, id)
+					return err == nil, err
+				},
+			},
+			"createCategory": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: inputArgs(categoryInputType, false),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					err := categoryUC.CreateCategory(p.Context, categoryInputOf(p.Args["input"].(map[string]interface{})))
+					return err == nil, err
+				},
+			},
+			"updateCategory": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: inputArgs(categoryInputType, true),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return false, err
+					}
+
+					err = categoryUC.UpdateCategory(p.Context, id, categoryInputOf(p.Args["input"].(map[string]interface{})))
+					return err == nil, err
+				},
+			},
+			"deleteCategory": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p.Args, "id")
+					if err != nil {
+						return false, err
+					}
+
+					err = categoryUC.DeleteCategoryByID(p.Context, id)
+					return err == nil, err
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    query,
+		Mutation: mutation,
+	})
+}
+
+func bookInputOf(args map[string]interface{}) (input book.BookInput, err error) {
+	input = book.BookInput{
+		Title:           stringArg(args, "title"),
+		Description:     stringArg(args, "description"),
+		ISBN:            stringArg(args, "isbn"),
+		SeriesVolume:    intArg(args, "seriesVolume"),
+		PublicationYear: intArg(args, "publicationYear"),
+		Edition:         stringArg(args, "edition"),
+		PageCount:       intArg(args, "pageCount"),
+		Language:        stringArg(args, "language"),
+	}
+
+	if publishedFlag, ok := args["publishedFlag"].(bool); ok {
+		input.PublishedFlag = &publishedFlag
+	}
+
+	if input.AuthorID, err = idArg(args, "authorId"); err != nil {
+		return input, err
+	}
+
+	if input.CategoryID, err = idArg(args, "categoryId"); err != nil {
+		return input, err
+	}
+
+	if input.PublisherID, err = optionalIDArg(args, "publisherId"); err != nil {
+		return input, err
+	}
+
+	if input.SeriesID, err = optionalIDArg(args, "seriesId"); err != nil {
+		return input, err
+	}
+
+	if input.WorkID, err = optionalIDArg(args, "workId"); err != nil {
+		return input, err
+	}
+
+	return input, nil
+}
+
+func authorInputOf(args map[string]interface{}) author.AuthorInput {
+	return author.AuthorInput{
+		Name:  stringArg(args, "name"),
+		Email: stringArg(args, "email"),
+	}
+}
+
+func categoryInputOf(args map[string]interface{}) category.CategoryInput {
+	return category.CategoryInput{
+		Name:        stringArg(args, "name"),
+		Description: stringArg(args, "description"),
+	}
+}
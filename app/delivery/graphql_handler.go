@@ -0,0 +1,46 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/book-library/app/delivery/gql"
+	u "github.com/book-library/app/usecase"
+	"github.com/google/uuid"
+	"github.com/graphql-go/handler"
+	"github.com/rs/zerolog/log"
+)
+
+type GraphQLHandler struct {
+	authorUC   u.AuthorServiceI
+	categoryUC u.CategoryServiceI
+	reviewUC   u.ReviewServiceI
+	handler    *handler.Handler
+}
+
+// NewGraphQLHandler serves the catalog schema. GraphiQL is only served when graphiQL is set,
+// which the server does in development.
+func NewGraphQLHandler(bookUC u.BookLibraryServiceI, authorUC u.AuthorServiceI, categoryUC u.CategoryServiceI, reviewUC u.ReviewServiceI, graphiQL bool) (GraphQLHandler, error) {
+	schema, err := gql.NewSchema(bookUC, authorUC, categoryUC)
+	if err != nil {
+		return GraphQLHandler{}, err
+	}
+
+	return GraphQLHandler{
+		authorUC:   authorUC,
+		categoryUC: categoryUC,
+		reviewUC:   reviewUC,
+		handler: handler.New(&handler.Config{
+			Schema:   &schema,
+			Pretty:   true,
+			GraphiQL: graphiQL,
+		}),
+	}, nil
+}
+
+func (h GraphQLHandler) ServeGraphQL(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	ctx = gql.WithLoaders(ctx, h.authorUC, h.categoryUC, h.reviewUC)
+
+	h.handler.ContextHandler(ctx, w, r)
+}
@@ -0,0 +1,43 @@
+package delivery
+
+import (
+	"net/http"
+
+	api "github.com/book-library/app/helper"
+	u "github.com/book-library/app/usecase"
+	"github.com/book-library/entity/health"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type HealthHandler struct {
+	healthUC u.HealthServiceI
+}
+
+func NewHealthHandler(healthUC u.HealthServiceI) HealthHandler {
+	return HealthHandler{
+		healthUC: healthUC,
+	}
+}
+
+// Healthz is the liveness probe. Probes are not logged because orchestrators call them every
+// few seconds.
+func (h HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	report := h.healthUC.Liveness(r.Context())
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to Healthz", Code: http.StatusOK, Success: true}, Data: report})
+}
+
+// Readyz is the readiness probe; it answers 503 when any dependency is down.
+func (h HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+
+	report := h.healthUC.Readiness(ctx)
+	if report.Status != health.StatusUp {
+		api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Service is not ready", Code: http.StatusServiceUnavailable, Success: false}, Data: report})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to Readyz", Code: http.StatusOK, Success: true}, Data: report})
+}
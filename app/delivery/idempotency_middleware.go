@@ -0,0 +1,118 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	api "github.com/book-library/app/helper"
+	u "github.com/book-library/app/usecase"
+	"github.com/book-library/entity/idempotency"
+	"github.com/book-library/logger"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// IdempotencyMiddleware makes POST, PUT, PATCH and DELETE requests carrying an Idempotency-Key
+// safe to retry. The first response is stored and replayed for retries with the same key and
+// payload; a reused key with a different payload gets 422 and a retry racing the first request
+// gets 409. Only successful responses are stored, a failed request frees its key so it can be
+// retried. Keys belong to the client named by clientKey and to the method and path they were
+// sent with, and stay leased to the first request while it runs.
+func IdempotencyMiddleware(idempotencyUC u.IdempotencyServiceI, clientKey func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(idempotency.KeyHeader)
+			if key == "" || !isMutatingMethod(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			log := log.With().Str("request_id", uuid.New().String()).Logger()
+			ctx := log.WithContext(r.Context())
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "io.ReadAll got an error on IdempotencyMiddleware"})
+				api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := u.IdempotencyScope(clientKey(r), r.Method, r.URL.Path)
+			replay, owner, err := idempotencyUC.Begin(ctx, scope, key, u.HashIdempotentRequest(r.Method, r.URL.RequestURI(), body))
+			switch {
+			case errors.Is(err, u.ErrIdempotencyKeyReused):
+				api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusUnprocessableEntity, Success: false})
+				return
+			case errors.Is(err, u.ErrIdempotencyKeyInProgress):
+				api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusConflict, Success: false})
+				return
+			case errors.Is(err, u.ErrIdempotencyKeyTooLong):
+				api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+				return
+			case err != nil:
+				logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: key, Message: "idempotencyUC.Begin got an error on IdempotencyMiddleware"})
+				api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+				return
+			}
+
+			if replay != nil {
+				if replay.ContentType != "" {
+					w.Header().Set("Content-Type", replay.ContentType)
+				}
+				w.Header().Set(idempotency.ReplayedHeader, "true")
+				w.WriteHeader(replay.StatusCode)
+				w.Write([]byte(replay.ResponseBody))
+				return
+			}
+
+			// The key is stored even if the client goes away, so its retry finds the outcome.
+			storeCtx := context.WithoutCancel(ctx)
+			completed := false
+			defer func() {
+				if !completed {
+					idempotencyUC.Release(storeCtx, scope, key, owner)
+				}
+			}()
+
+			holdCtx, stopHolding := context.WithCancel(storeCtx)
+			defer stopHolding()
+			go idempotencyUC.Hold(holdCtx, scope, key, owner)
+
+			var recorded bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&recorded)
+
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			if status < http.StatusOK || status >= http.StatusMultipleChoices {
+				return
+			}
+
+			err = idempotencyUC.Complete(storeCtx, scope, key, owner, idempotency.StoredResponse{
+				StatusCode:   status,
+				ContentType:  ww.Header().Get("Content-Type"),
+				ResponseBody: recorded.String(),
+			})
+			completed = err == nil
+		})
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,127 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	api "github.com/book-library/app/helper"
+	u "github.com/book-library/app/usecase"
+	"github.com/book-library/entity/member"
+	"github.com/book-library/logger"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type MemberHandler struct {
+	memberUC u.MemberServiceI
+}
+
+func NewMemberHandler(memberUC u.MemberServiceI) MemberHandler {
+	return MemberHandler{
+		memberUC: memberUC,
+	}
+}
+
+func (h MemberHandler) CreateMember(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+
+	var input member.MemberInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on MemberHandler.CreateMember"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: input})
+
+	err = h.memberUC.CreateMember(ctx, input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: input, Message: "h.memberUC.CreateMember got an error on MemberHandler.CreateMember"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to CreateMember", Code: http.StatusOK, Success: true})
+}
+
+func (h MemberHandler) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	var input member.MemberInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on MemberHandler.UpdateMember"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: input})
+
+	err = h.memberUC.UpdateMember(ctx, int64(idInt), input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: input, Message: "h.categoryUC.UpdateMember got an error on MemberHandler.UpdateMember"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to UpdateMember", Code: http.StatusOK, Success: true})
+}
+
+func (h MemberHandler) GetMemberById(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: idInt})
+
+	catById, err := h.memberUC.GetMemberByID(ctx, int64(idInt))
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: idInt, Message: "h.categoryUC.GetCategoryById got an error on MemberHandler.GetMemberById"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to GetMemberById", Code: http.StatusOK, Success: true}, Data: catById})
+}
+
+func (h MemberHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	name := r.URL.Query().Get("name")
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: name})
+
+	categories, err := h.memberUC.GetAllMembers(ctx, name)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: name, Message: "h.memberUC.GetAllMembers got an error on MemberHandler.GetMembers"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to GetMembers", Code: http.StatusOK, Success: true}, Data: categories})
+}
+
+func (h MemberHandler) DeleteMemberByID(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: idInt})
+
+	err := h.memberUC.DeleteMemberByID(ctx, int64(idInt))
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: idInt, Message: "h.memberUC.DeleteMemberByID got an error on MemberHandler.DeleteMemberByID"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to DeleteMemberByID", Code: http.StatusOK, Success: true})
+}
@@ -0,0 +1,28 @@
+package delivery
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+)
+
+const mergePatchContentType = "application/merge-patch+json"
+
+// readMergePatch returns the body of a PATCH request. It is sent as application/merge-patch+json
+// (RFC 7396); plain application/json is accepted too for clients that cannot set the type.
+func readMergePatch(r *http.Request) (patch []byte, code int, err error) {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergePatchContentType && mediaType != "application/json") {
+			return nil, http.StatusUnsupportedMediaType, errors.New("Content-Type must be " + mergePatchContentType)
+		}
+	}
+
+	patch, err = io.ReadAll(r.Body)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	return patch, http.StatusOK, nil
+}
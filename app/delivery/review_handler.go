@@ -0,0 +1,161 @@
+package delivery
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	api "github.com/book-library/app/helper"
+	u "github.com/book-library/app/usecase"
+	"github.com/book-library/entity/review"
+	"github.com/book-library/logger"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type ReviewHandler struct {
+	reviewUC u.ReviewServiceI
+}
+
+func NewReviewHandler(reviewUC u.ReviewServiceI) ReviewHandler {
+	return ReviewHandler{
+		reviewUC: reviewUC,
+	}
+}
+
+func (h ReviewHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+
+	var input review.ReviewInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on ReviewHandler.CreateReview"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: input})
+
+	err = h.reviewUC.CreateReview(ctx, input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: input, Message: "h.reviewUC.CreateReview got an error on ReviewHandler.CreateReview"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to CreateReview", Code: http.StatusOK, Success: true})
+}
+
+func (h ReviewHandler) UpdateReview(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	var input review.ReviewInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on ReviewHandler.UpdateReview"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: input})
+
+	err = h.reviewUC.UpdateReview(ctx, int64(idInt), input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: input, Message: "h.categoryUC.UpdateReview got an error on ReviewHandler.UpdateReview"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to UpdateReview", Code: http.StatusOK, Success: true})
+}
+
+func (h ReviewHandler) GetReviewById(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: idInt})
+
+	catById, err := h.reviewUC.GetReviewByID(ctx, int64(idInt))
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: idInt, Message: "h.categoryUC.GetCategoryById got an error on ReviewHandler.GetReviewById"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to GetReviewById", Code: http.StatusOK, Success: true}, Data: catById})
+}
+
+func (h ReviewHandler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	var input review.ReviewModerationInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Message: "json.NewDecoder got an error on ReviewHandler.ModerateReview"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusInternalServerError, Success: false})
+		return
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: input})
+
+	err = h.reviewUC.ModerateReview(ctx, int64(idInt), input)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: input, Message: "h.reviewUC.ModerateReview got an error on ReviewHandler.ModerateReview"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to ModerateReview", Code: http.StatusOK, Success: true})
+}
+
+func (h ReviewHandler) GetReviews(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	query := r.URL.Query()
+	bookID, _ := strconv.Atoi(query.Get("book_id"))
+	memberID, _ := strconv.Atoi(query.Get("member_id"))
+
+	search := review.ReviewSearch{
+		BookID:   int64(bookID),
+		MemberID: int64(memberID),
+		Status:   query.Get("status"),
+	}
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: search})
+
+	reviews, err := h.reviewUC.GetAllReviews(ctx, search)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: search, Message: "h.reviewUC.GetAllReviews got an error on ReviewHandler.GetReviews"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to GetReviews", Code: http.StatusOK, Success: true}, Data: reviews})
+}
+
+func (h ReviewHandler) DeleteReviewByID(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	id := r.PathValue("id")
+	idInt, _ := strconv.Atoi(id)
+
+	logger.LogInfo(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Request: idInt})
+
+	err := h.reviewUC.DeleteReviewByID(ctx, int64(idInt))
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: idInt, Message: "h.reviewUC.DeleteReviewByID got an error on ReviewHandler.DeleteReviewByID"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponseSuccessWithoutData(w, api.Meta{Message: "Success to DeleteReviewByID", Code: http.StatusOK, Success: true})
+}
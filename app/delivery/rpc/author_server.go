@@ -0,0 +1,83 @@
+package rpc
+
+import (
+	"context"
+
+	u "github.com/book-library/app/usecase"
+	"github.com/book-library/entity/author"
+	pb "github.com/book-library/proto/booklibrary/v1"
+)
+
+type AuthorServer struct {
+	pb.UnimplementedAuthorServiceServer
+	authorUC u.AuthorServiceI
+}
+
+// CreateAuthor implements pb.AuthorServiceServer.
+func (s AuthorServer) CreateAuthor(ctx context.Context, req *pb.CreateAuthorRequest) (*pb.CreateAuthorResponse, error) {
+	if err := s.authorUC.CreateAuthor(ctx, authorInputOf(req.GetAuthor())); err != nil {
+		return nil, usecaseError(err)
+	}
+
+	return &pb.CreateAuthorResponse{}, nil
+}
+
+// UpdateAuthor implements pb.AuthorServiceServer.
+func (s AuthorServer) UpdateAuthor(ctx context.Context, req *pb.UpdateAuthorRequest) (*pb.UpdateAuthorResponse, error) {
+	if err := s.authorUC.UpdateAuthor(ctx, req.GetId(), authorInputOf(req.GetAuthor())); err != nil {
+		return nil, usecaseError(err)
+	}
+
+	return &pb.UpdateAuthorResponse{}, nil
+}
+
+// GetAuthor implements pb.AuthorServiceServer.
+func (s AuthorServer) GetAuthor(ctx context.Context, req *pb.GetAuthorRequest) (*pb.GetAuthorResponse, error) {
+	authorByID, err := s.authorUC.GetAuthorByID(ctx, req.GetId())
+	if err != nil {
+		return nil, usecaseError(err)
+	}
+
+	return &pb.GetAuthorResponse{Author: authorOf(authorByID)}, nil
+}
+
+// ListAuthors implements pb.AuthorServiceServer.
+func (s AuthorServer) ListAuthors(ctx context.Context, req *pb.ListAuthorsRequest) (*pb.ListAuthorsResponse, error) {
+	authors, err := s.authorUC.GetAllAuthors(ctx, req.GetName(), 0)
+	if err != nil {
+		return nil, usecaseError(err)
+	}
+
+	resp := &pb.ListAuthorsResponse{}
+	for _, a := range authors {
+		resp.Authors = append(resp.Authors, authorOf(a))
+	}
+
+	return resp, nil
+}
+
+// DeleteAuthor implements pb.AuthorServiceServer.
+func (s AuthorServer) DeleteAuthor(ctx context.Context, req *pb.DeleteAuthorRequest) (*pb.DeleteAuthorResponse, error) {
+	if err := s.authorUC.DeleteAuthorByID(ctx, req.GetId()); err != nil {
+		return nil, usecaseError(err)
+	}
+
+	return &pb.DeleteAuthorResponse{}, nil
+}
+
+func authorInputOf(input *pb.AuthorInput) author.AuthorInput {
+	return author.AuthorInput{
+		Name:  input.GetName(),
+		Email: input.GetEmail(),
+	}
+}
+
+func authorOf(a author.AuthorResponse) *pb.Author {
+	return &pb.Author{
+		Id:        a.ID,
+		Name:      a.Name,
+		Email:     a.Email,
+		CreatedAt: timestampOf(a.CreatedAt),
+		UpdatedAt: optionalTimestampOf(a.UpdatedAt),
+	}
+}
@@ -0,0 +1,120 @@
+package rpc
+
+import (
+	"context"
+
+	u "github.com/book-library/app/usecase"
+	"github.com/book-library/entity/book"
+	pb "github.com/book-library/proto/booklibrary/v1"
+)
+
+type BookServer struct {
+	pb.UnimplementedBookServiceServer
+	bookUC u.BookLibraryServiceI
+}
+
+// CreateBook implements pb.BookServiceServer.
+func (s BookServer) CreateBook(ctx context.Context, req *pb.CreateBookRequest) (*pb.CreateBookResponse, error) {
+	if err := s.bookUC.CreateBook(ctx, bookInputOf(req.GetBook())); err != nil {
+		return nil, usecaseError(err)
+	}
+
+	return &pb.CreateBookResponse{}, nil
+}
+
+// UpdateBook implements pb.BookServiceServer.
+func (s BookServer) UpdateBook(ctx context.Context, req *pb.UpdateBookRequest) (*pb.UpdateBookResponse, error) {
+	if err := s.bookUC.UpdateBook(ctx, req.GetId(), bookInputOf(req.GetBook())); err != nil {
+		return nil, usecaseError(err)
+	}
+
+	return &pb.UpdateBookResponse{}, nil
+}
+
+// GetBook implements pb.BookServiceServer.
+func (s BookServer) GetBook(ctx context.Context, req *pb.GetBookRequest) (*pb.GetBookResponse, error) {
+	bookByID, err := s.bookUC.GetBookByID(ctx, req.GetId())
+	if err != nil {
+		return nil, usecaseError(err)
+	}
+
+	return &pb.GetBookResponse{Book: bookOf(bookByID)}, nil
+}
+
+// ListBooks implements pb.BookServiceServer.
+func (s BookServer) ListBooks(ctx context.Context, req *pb.ListBooksRequest) (*pb.ListBooksResponse, error) {
+	books, err := s.bookUC.GetAllBooks(ctx, book.BookSearch{
+		Search:              req.GetSearch(),
+		PublisherID:         req.GetPublisherId(),
+		PublicationYearFrom: int(req.GetPublicationYearFrom()),
+		PublicationYearTo:   int(req.GetPublicationYearTo()),
+		Sort:                req.GetSort(),
+	})
+	if err != nil {
+		return nil, usecaseError(err)
+	}
+
+	resp := &pb.ListBooksResponse{}
+	for _, b := range books {
+		resp.Books = append(resp.Books, bookOf(b))
+	}
+
+	return resp, nil
+}
+
+// DeleteBook implements pb.BookServiceServer.
+func (s BookServer) DeleteBook(ctx context.Context, req *pb.DeleteBookRequest) (*pb.DeleteBookResponse, error) {
+	if err := s.bookUC.DeleteBookByID(ctx, req.GetId()); err != nil {
+		return nil, usecaseError(err)
+	}
+
+	return &pb.DeleteBookResponse{}, nil
+}
+
+func bookInputOf(input *pb.BookInput) book.BookInput {
+	return book.BookInput{
+		Title:           input.GetTitle(),
+		Description:     input.GetDescription(),
+		ISBN:            input.GetIsbn(),
+		AuthorID:        input.GetAuthorId(),
+		CategoryID:      input.GetCategoryId(),
+		PublishedFlag:   input.PublishedFlag,
+		PublisherID:     input.PublisherId,
+		SeriesID:        input.SeriesId,
+		SeriesVolume:    int(input.GetSeriesVolume()),
+		WorkID:          input.WorkId,
+		PublicationYear: int(input.GetPublicationYear()),
+		Edition:         input.GetEdition(),
+		PageCount:       int(input.GetPageCount()),
+		Language:        input.GetLanguage(),
+	}
+}
+
+func bookOf(b book.BookResponseDetail) *pb.Book {
+	return &pb.Book{
+		Id:              b.ID,
+		Title:           b.Title,
+		Description:     b.Description,
+		Isbn:            b.ISBN,
+		PublishedFlag:   b.PublishedFlag,
+		AuthorId:        b.Author.ID,
+		AuthorName:      b.Author.Name,
+		CategoryId:      b.Category.ID,
+		CategoryName:    b.Category.Name,
+		PublisherId:     b.Publisher.ID,
+		PublisherName:   b.Publisher.Name,
+		SeriesId:        b.Series.ID,
+		SeriesName:      b.Series.Name,
+		SeriesVolume:    int32(b.Series.Volume),
+		WorkId:          b.Work.ID,
+		WorkTitle:       b.Work.Title,
+		PublicationYear: int32(b.PublicationYear),
+		Edition:         b.Edition,
+		PageCount:       int32(b.PageCount),
+		Language:        b.Language,
+		RatingAverage:   b.RatingAverage,
+		RatingCount:     int32(b.RatingCount),
+		CreatedAt:       timestampOf(b.CreatedAt),
+		UpdatedAt:       optionalTimestampOf(b.UpdatedAt),
+	}
+}
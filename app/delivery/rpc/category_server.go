@@ -0,0 +1,83 @@
+package rpc
+
+import (
+	"context"
+
+	u "github.com/book-library/app/usecase"
+	"github.com/book-library/entity/category"
+	pb "github.com/book-library/proto/booklibrary/v1"
+)
+
+type CategoryServer struct {
+	pb.UnimplementedCategoryServiceServer
+	categoryUC u.CategoryServiceI
+}
+
+// CreateCategory implements pb.CategoryServiceServer.
+func (s CategoryServer) CreateCategory(ctx context.Context, req *pb.CreateCategoryRequest) (*pb.CreateCategoryResponse, error) {
+	if err := s.categoryUC.CreateCategory(ctx, categoryInputOf(req.GetCategory())); err != nil {
+		return nil, usecaseError(err)
+	}
+
+	return &pb.CreateCategoryResponse{}, nil
+}
+
+// UpdateCategory implements pb.CategoryServiceServer.
+func (s CategoryServer) UpdateCategory(ctx context.Context, req *pb.UpdateCategoryRequest) (*pb.UpdateCategoryResponse, error) {
+	if err := s.categoryUC.UpdateCategory(ctx, req.GetId(), categoryInputOf(req.GetCategory())); err != nil {
+		return nil, usecaseError(err)
+	}
+
+	return &pb.UpdateCategoryResponse{}, nil
+}
+
+// GetCategory implements pb.CategoryServiceServer.
+func (s CategoryServer) GetCategory(ctx context.Context, req *pb.GetCategoryRequest) (*pb.GetCategoryResponse, error) {
+	categoryByID, err := s.categoryUC.GetCategoryByID(ctx, req.GetId())
+	if err != nil {
+		return nil, usecaseError(err)
+	}
+
+	return &pb.GetCategoryResponse{Category: categoryOf(categoryByID)}, nil
+}
+
+// ListCategories implements pb.CategoryServiceServer.
+func (s CategoryServer) ListCategories(ctx context.Context, req *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error) {
+	categories, err := s.categoryUC.GetAllCategories(ctx, req.GetName(), 0)
+	if err != nil {
+		return nil, usecaseError(err)
+	}
+
+	resp := &pb.ListCategoriesResponse{}
+	for _, c := range categories {
+		resp.Categories = append(resp.Categories, categoryOf(c))
+	}
+
+	return resp, nil
+}
+
+// DeleteCategory implements pb.CategoryServiceServer.
+func (s CategoryServer) DeleteCategory(ctx context.Context, req *pb.DeleteCategoryRequest) (*pb.DeleteCategoryResponse, error) {
+	if err := s.categoryUC.DeleteCategoryByID(ctx, req.GetId()); err != nil {
+		return nil, usecaseError(err)
+	}
+
+	return &pb.DeleteCategoryResponse{}, nil
+}
+
+func categoryInputOf(input *pb.CategoryInput) category.CategoryInput {
+	return category.CategoryInput{
+		Name:        input.GetName(),
+		Description: input.GetDescription(),
+	}
+}
+
+func categoryOf(c category.CategoryResponse) *pb.Category {
+	return &pb.Category{
+		Id:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		CreatedAt:   timestampOf(c.CreatedAt),
+		UpdatedAt:   optionalTimestampOf(c.UpdatedAt),
+	}
+}
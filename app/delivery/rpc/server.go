@@ -0,0 +1,78 @@
+// Package rpc serves the catalog over gRPC on top of the same usecases as the REST API.
+package rpc
+
+import (
+	"context"
+	"time"
+
+	u "github.com/book-library/app/usecase"
+	pb "github.com/book-library/proto/booklibrary/v1"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NewServer registers the book, author and category services together with gRPC health
+// checking and server reflection. The health server is returned so shutdown can mark the
+// services as not serving before the listener stops.
+func NewServer(bookUC u.BookLibraryServiceI, authorUC u.AuthorServiceI, categoryUC u.CategoryServiceI) (*grpc.Server, *health.Server) {
+	server := grpc.NewServer(grpc.UnaryInterceptor(loggingInterceptor))
+
+	pb.RegisterBookServiceServer(server, BookServer{bookUC: bookUC})
+	pb.RegisterAuthorServiceServer(server, AuthorServer{authorUC: authorUC})
+	pb.RegisterCategoryServiceServer(server, CategoryServer{categoryUC: categoryUC})
+
+	healthServer := health.NewServer()
+	for name := range server.GetServiceInfo() {
+		healthServer.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	reflection.Register(server)
+
+	return server, healthServer
+}
+
+// loggingInterceptor gives every call a request scoped logger, as the HTTP handlers do, so
+// the usecases log with a request_id.
+func loggingInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	logger := log.With().Str("request_id", uuid.New().String()).Str("method", info.FullMethod).Logger()
+	ctx = logger.WithContext(ctx)
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		logger.Error().Err(err).Msg("grpc call got an error")
+		return resp, err
+	}
+
+	logger.Info().Msg("grpc call")
+
+	return resp, nil
+}
+
+// usecaseError maps a usecase error the way the REST handlers do, which answer 400 Bad Request.
+func usecaseError(err error) error {
+	return status.Error(codes.InvalidArgument, err.Error())
+}
+
+func timestampOf(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
+
+func optionalTimestampOf(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+
+	return timestampOf(*t)
+}
@@ -0,0 +1,40 @@
+package delivery
+
+import (
+	"net/http"
+	"strconv"
+
+	api "github.com/book-library/app/helper"
+	u "github.com/book-library/app/usecase"
+	"github.com/book-library/logger"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type SuggestHandler struct {
+	suggestUC u.SuggestServiceI
+}
+
+func NewSuggestHandler(suggestUC u.SuggestServiceI) SuggestHandler {
+	return SuggestHandler{
+		suggestUC: suggestUC,
+	}
+}
+
+// Suggest answers search-as-you-type lookups. Successful requests are not logged because a
+// search box sends one per keystroke.
+func (h SuggestHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	log := log.With().Str("request_id", uuid.New().String()).Logger()
+	ctx := log.WithContext(r.Context())
+	q := r.URL.Query().Get("q")
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	suggestions, err := h.suggestUC.Suggest(ctx, q, limit)
+	if err != nil {
+		logger.LogError(logger.LogConfig{Logger: log, Req: r, Ctx: ctx, Err: err, Request: q, Message: "h.suggestUC.Suggest got an error on SuggestHandler.Suggest"})
+		api.APIResponseFailed(w, api.Meta{Message: err.Error(), Code: http.StatusBadRequest, Success: false})
+		return
+	}
+
+	api.APIResponse(w, api.Response{Meta: api.Meta{Message: "Success to Suggest", Code: http.StatusOK, Success: true}, Data: suggestions})
+}
@@ -1,15 +1,26 @@
 package helper
 
 import (
+	"context"
 	"encoding/json"
-	"log"
 	"net/http"
 	"time"
+
+	"github.com/book-library/app/metrics"
+	"github.com/book-library/app/tracing"
 )
 
-func TimeTrack(start time.Time, name string) {
-	elapsed := time.Since(start)
-	log.Printf("%s took %s", name, elapsed)
+// Track starts the span of the named usecase operation and returns the context carrying it,
+// so repository queries become its children. The returned function ends the span and records
+// the duration in the book_library_usecase_operation_duration_seconds histogram.
+func Track(ctx context.Context, name string) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracing.Tracer().Start(ctx, name)
+
+	return ctx, func() {
+		span.End()
+		metrics.ObserveUsecase(name, time.Since(start))
+	}
 }
 
 type Response struct {
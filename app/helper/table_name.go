@@ -1,7 +1,19 @@
 package helper
 
 var (
-	BookTableName     = "tb_book"
-	AuthorTableName   = "tb_author"
-	CategoryTableName = "tb_category"
+	BookTableName            = "tb_book"
+	AuthorTableName          = "tb_author"
+	CategoryTableName        = "tb_category"
+	PublisherTableName       = "tb_publisher"
+	SeriesTableName          = "tb_series"
+	WorkTableName            = "tb_work"
+	MemberTableName          = "tb_member"
+	ReviewTableName          = "tb_review"
+	CollectionTableName      = "tb_collection"
+	CollectionBookTableName  = "tb_collection_book"
+	WebhookTableName         = "tb_webhook"
+	WebhookDeliveryTableName = "tb_webhook_delivery"
+	OutboxTableName          = "tb_outbox"
+	OutboxLeaseTableName     = "tb_outbox_lease"
+	IdempotencyTableName     = "tb_idempotency_key"
 )
@@ -2,15 +2,22 @@ package http
 
 import (
 	"github.com/book-library/app/delivery"
+	"github.com/book-library/app/metrics"
 	"github.com/go-chi/chi/v5"
 )
 
 func BookPath(r *chi.Mux, bh delivery.BookHandler) {
 	r.Route("/api/v1/book/", func(r chi.Router) {
 		r.Post("/create", bh.CreateBook)
+		r.Post("/batch", bh.BatchBooks)
+		r.Get("/export", bh.ExportBooks)
+		r.Post("/import", bh.ImportBooks)
 		r.Put("/update/{id}", bh.UpdateBook)
+		r.Patch("/{id}", bh.PatchBook)
 		r.Get("/all", bh.GetBooks)
+		r.Get("/search", bh.SearchBooks)
 		r.Get("/{id}", bh.GetBookById)
+		r.Get("/{id}/similar", bh.GetSimilarBooks)
 		r.Delete("/{id}", bh.DeleteBookyByID)
 	})
 }
@@ -18,7 +25,9 @@ func BookPath(r *chi.Mux, bh delivery.BookHandler) {
 func AuthorPath(r *chi.Mux, ah delivery.AuthorHandler) {
 	r.Route("/api/v1/author", func(r chi.Router) {
 		r.Post("/create", ah.CreateAuthor)
+		r.Post("/batch", ah.BatchAuthors)
 		r.Put("/update/{id}", ah.UpdateAuthor)
+		r.Patch("/{id}", ah.PatchAuthor)
 		r.Get("/all", ah.GetAuthors)
 		r.Get("/{id}", ah.GetAuhtorById)
 		r.Delete("/{id}", ah.DeleteAuthorByID)
@@ -28,9 +37,112 @@ func AuthorPath(r *chi.Mux, ah delivery.AuthorHandler) {
 func CategoryPath(r *chi.Mux, ch delivery.CategoryHandler) {
 	r.Route("/api/v1/category", func(r chi.Router) {
 		r.Post("/create", ch.CreateCategory)
+		r.Post("/batch", ch.BatchCategories)
 		r.Put("/update/{id}", ch.UpdateCategory)
+		r.Patch("/{id}", ch.PatchCategory)
 		r.Get("/all", ch.GetCategories)
 		r.Get("/{id}", ch.GetCategoryById)
 		r.Delete("/{id}", ch.DeleteCategoryByID)
 	})
 }
+
+func PublisherPath(r *chi.Mux, ph delivery.PublisherHandler) {
+	r.Route("/api/v1/publisher", func(r chi.Router) {
+		r.Post("/create", ph.CreatePublisher)
+		r.Put("/update/{id}", ph.UpdatePublisher)
+		r.Get("/all", ph.GetPublishers)
+		r.Get("/{id}", ph.GetPublisherById)
+		r.Delete("/{id}", ph.DeletePublisherByID)
+	})
+}
+
+func SeriesPath(r *chi.Mux, sh delivery.SeriesHandler) {
+	r.Route("/api/v1/series", func(r chi.Router) {
+		r.Post("/create", sh.CreateSeries)
+		r.Put("/update/{id}", sh.UpdateSeries)
+		r.Get("/all", sh.GetSeries)
+		r.Get("/{id}", sh.GetSeriesById)
+		r.Delete("/{id}", sh.DeleteSeriesByID)
+	})
+}
+
+func WorkPath(r *chi.Mux, wh delivery.WorkHandler) {
+	r.Route("/api/v1/work", func(r chi.Router) {
+		r.Post("/create", wh.CreateWork)
+		r.Put("/update/{id}", wh.UpdateWork)
+		r.Get("/all", wh.GetWorks)
+		r.Get("/{id}", wh.GetWorkById)
+		r.Delete("/{id}", wh.DeleteWorkByID)
+	})
+}
+
+func MemberPath(r *chi.Mux, mh delivery.MemberHandler) {
+	r.Route("/api/v1/member", func(r chi.Router) {
+		r.Post("/create", mh.CreateMember)
+		r.Put("/update/{id}", mh.UpdateMember)
+		r.Get("/all", mh.GetMembers)
+		r.Get("/{id}", mh.GetMemberById)
+		r.Delete("/{id}", mh.DeleteMemberByID)
+	})
+}
+
+func ReviewPath(r *chi.Mux, rh delivery.ReviewHandler) {
+	r.Route("/api/v1/review", func(r chi.Router) {
+		r.Post("/create", rh.CreateReview)
+		r.Put("/update/{id}", rh.UpdateReview)
+		r.Put("/moderate/{id}", rh.ModerateReview)
+		r.Get("/all", rh.GetReviews)
+		r.Get("/{id}", rh.GetReviewById)
+		r.Delete("/{id}", rh.DeleteReviewByID)
+	})
+}
+
+func CollectionPath(r *chi.Mux, ch delivery.CollectionHandler) {
+	r.Route("/api/v1/collection", func(r chi.Router) {
+		r.Post("/create", ch.CreateCollection)
+		r.Put("/update/{id}", ch.UpdateCollection)
+		r.Get("/all", ch.GetCollections)
+		r.Get("/featured", ch.GetFeaturedCollections)
+		r.Get("/{id}", ch.GetCollectionById)
+		r.Delete("/{id}", ch.DeleteCollectionByID)
+		r.Post("/{id}/book", ch.AddCollectionBook)
+		r.Put("/{id}/book/{book_id}", ch.UpdateCollectionBook)
+		r.Delete("/{id}/book/{book_id}", ch.RemoveCollectionBook)
+	})
+}
+
+func WebhookPath(r *chi.Mux, wh delivery.WebhookHandler) {
+	r.Route("/api/v1/webhook", func(r chi.Router) {
+		r.Post("/create", wh.CreateWebhook)
+		r.Put("/update/{id}", wh.UpdateWebhook)
+		r.Get("/all", wh.GetWebhooks)
+		r.Get("/{id}", wh.GetWebhookById)
+		r.Delete("/{id}", wh.DeleteWebhookByID)
+		r.Post("/{id}/ping", wh.PingWebhook)
+		r.Get("/{id}/deliveries", wh.GetWebhookDeliveries)
+	})
+}
+
+func EventPath(r *chi.Mux, eh delivery.EventHandler) {
+	r.Route("/api/v1/events", func(r chi.Router) {
+		r.Get("/", eh.StreamEvents)
+	})
+}
+
+func SuggestPath(r *chi.Mux, sh delivery.SuggestHandler) {
+	r.Get("/api/v1/suggest", sh.Suggest)
+}
+
+func GraphQLPath(r *chi.Mux, gh delivery.GraphQLHandler) {
+	r.Get("/graphql", gh.ServeGraphQL)
+	r.Post("/graphql", gh.ServeGraphQL)
+}
+
+func MetricsPath(r *chi.Mux) {
+	r.Handle("/metrics", metrics.Handler())
+}
+
+func HealthPath(r *chi.Mux, hh delivery.HealthHandler) {
+	r.Get("/healthz", hh.Healthz)
+	r.Get("/readyz", hh.Readyz)
+}
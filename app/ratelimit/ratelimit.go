@@ -0,0 +1,281 @@
+// Package ratelimit throttles each client with a token bucket per route.
+package ratelimit
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/book-library/app/helper"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+
+	// sweepInterval is how often the buckets of clients that went quiet are dropped.
+	sweepInterval = time.Minute
+)
+
+// Limit is a token bucket refilled at Rate tokens per second and holding at most Burst tokens.
+// A zero Rate means the route is not limited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+type bucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+	full   time.Time
+}
+
+type Limiter struct {
+	defaultLimit Limit
+	routes       map[string]Limit
+	apiKeys      map[string]bool
+	maxBuckets   int
+
+	mu sync.Mutex
+	// buckets indexes the elements of recent, which holds the buckets most recently used first.
+	buckets   map[string]*list.Element
+	recent    *list.List
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewLimiter returns a limiter that tells clients apart by the API keys in apiKeys, and everyone
+// else by IP. It keeps at most maxBuckets buckets, evicting the least recently used; an evicted
+// client starts over with a full bucket, so maxBuckets must stay well above the number of
+// clients active within a refill. A maxBuckets of 0 means no cap.
+func NewLimiter(defaultLimit Limit, routes map[string]Limit, apiKeys []string, maxBuckets int) *Limiter {
+	if routes == nil {
+		routes = map[string]Limit{}
+	}
+	if defaultLimit.Rate > 0 && defaultLimit.Burst < 1 {
+		defaultLimit.Burst = int(math.Ceil(defaultLimit.Rate))
+	}
+
+	keys := map[string]bool{}
+	for _, key := range apiKeys {
+		if key = strings.TrimSpace(key); key != "" {
+			keys[key] = true
+		}
+	}
+
+	return &Limiter{
+		defaultLimit: defaultLimit,
+		routes:       routes,
+		apiKeys:      keys,
+		maxBuckets:   maxBuckets,
+		buckets:      map[string]*list.Element{},
+		recent:       list.New(),
+		lastSweep:    time.Now(),
+		now:          time.Now,
+	}
+}
+
+// ParseRoutes reads per-route limits written as comma separated "METHOD /pattern=rate:burst"
+// entries, e.g. "GET /api/v1/book/all=1:5,GET /healthz=0". The pattern is the chi route
+// pattern and a rate of 0 turns limiting off for that route.
+func ParseRoutes(spec string) (routes map[string]Limit, err error) {
+	routes = map[string]Limit{}
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		route, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("rate limit route %q is missing \"=rate:burst\"", entry)
+		}
+
+		fields := strings.Fields(route)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("rate limit route %q must be \"METHOD /pattern\"", route)
+		}
+
+		limit, err := parseLimit(value)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit route %q: %w", route, err)
+		}
+
+		routes[routeKey(fields[0], fields[1])] = limit
+	}
+
+	return routes, nil
+}
+
+func parseLimit(value string) (limit Limit, err error) {
+	rate, burst, hasBurst := strings.Cut(strings.TrimSpace(value), ":")
+
+	limit.Rate, err = strconv.ParseFloat(rate, 64)
+	if err != nil || limit.Rate < 0 {
+		return limit, fmt.Errorf("invalid rate %q", rate)
+	}
+
+	limit.Burst = int(math.Ceil(limit.Rate))
+	if hasBurst {
+		limit.Burst, err = strconv.Atoi(burst)
+		if err != nil || limit.Burst < 1 {
+			return limit, fmt.Errorf("invalid burst %q", burst)
+		}
+	}
+
+	if limit.Rate > 0 && limit.Burst < 1 {
+		limit.Burst = 1
+	}
+
+	return limit, nil
+}
+
+func routeKey(method, pattern string) string {
+	return strings.ToUpper(method) + " " + pattern
+}
+
+// limitFor picks the route's own limit, then one configured for any method ("*"), then the default.
+func (l *Limiter) limitFor(method, pattern string) (key string, limit Limit) {
+	if limit, ok := l.routes[routeKey(method, pattern)]; ok {
+		return routeKey(method, pattern), limit
+	}
+
+	if limit, ok := l.routes[routeKey("*", pattern)]; ok {
+		return routeKey("*", pattern), limit
+	}
+
+	return "default", l.defaultLimit
+}
+
+// take spends a token from the client's bucket. It returns the tokens left and how long until
+// the next token is available (when denied) or the bucket is full again (when allowed).
+func (l *Limiter) take(key string, limit Limit) (allowed bool, remaining int, wait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+
+	var b *bucket
+	if element, ok := l.buckets[key]; ok {
+		l.recent.MoveToFront(element)
+		b = element.Value.(*bucket)
+	} else {
+		l.makeRoom(now)
+		b = &bucket{key: key, tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = l.recent.PushFront(b)
+	}
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, 0, secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+
+	b.tokens--
+	untilFull := secondsToDuration((float64(limit.Burst) - b.tokens) / limit.Rate)
+	b.full = now.Add(untilFull)
+
+	return true, int(b.tokens), untilFull
+}
+
+// sweep drops the buckets that have refilled completely, since a new bucket starts full anyway.
+func (l *Limiter) sweep(now time.Time) {
+	for _, element := range l.buckets {
+		if now.After(element.Value.(*bucket).full) {
+			l.remove(element)
+		}
+	}
+	l.lastSweep = now
+}
+
+// makeRoom frees a place for a new bucket once maxBuckets is reached: the full buckets go first,
+// and if none is, the least recently used one.
+func (l *Limiter) makeRoom(now time.Time) {
+	if l.maxBuckets <= 0 || len(l.buckets) < l.maxBuckets {
+		return
+	}
+
+	l.sweep(now)
+	if len(l.buckets) >= l.maxBuckets {
+		l.remove(l.recent.Back())
+	}
+}
+
+func (l *Limiter) remove(element *list.Element) {
+	l.recent.Remove(element)
+	delete(l.buckets, element.Value.(*bucket).key)
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// ClientKey identifies the caller by its API key when the key is one of the limiter's, and by
+// the client IP otherwise, so a client cannot shed its limit by sending made-up keys. RemoteAddr
+// holds the client IP once RealIP has run.
+func (l *Limiter) ClientKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" && l.apiKeys[key] {
+		return "key:" + key
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return "ip:" + ip
+}
+
+// Middleware limits every request routed by mux. The route is resolved up front so limits can
+// be set per chi route pattern. It must run after RealIP.
+func (l *Limiter) Middleware(mux *chi.Mux) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rctx := chi.NewRouteContext()
+			pattern := r.URL.Path
+			if mux.Match(rctx, r.Method, r.URL.Path) {
+				pattern = rctx.RoutePattern()
+			}
+
+			routeKey, limit := l.limitFor(r.Method, pattern)
+			if limit.Rate <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			client := l.ClientKey(r)
+			allowed, remaining, wait := l.take(routeKey+"|"+client, limit)
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+			w.Header().Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(wait)))
+
+			if !allowed {
+				log.Debug().Str("remote_addr", r.RemoteAddr).Str("route", routeKey).Msg("rate limit exceeded")
+
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(wait)))
+				api.APIResponseFailed(w, api.Meta{Message: "Too many requests", Code: http.StatusTooManyRequests, Success: false})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestClientKeyTrustsOnlyKnownAPIKeys(t *testing.T) {
+	l := NewLimiter(Limit{Rate: 1}, nil, []string{"known"}, 0)
+
+	tests := []struct {
+		name   string
+		apiKey string
+		key    string
+	}{
+		{name: "no key", apiKey: "", key: "ip:192.0.2.1"},
+		{name: "known key", apiKey: "known", key: "key:known"},
+		{name: "made-up key", apiKey: "made-up", key: "ip:192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = "192.0.2.1:4242"
+			r.Header.Set(APIKeyHeader, tt.apiKey)
+			r.Header.Set("X-User-ID", "someone")
+
+			if key := l.ClientKey(r); key != tt.key {
+				t.Errorf("ClientKey = %s, want %s", key, tt.key)
+			}
+		})
+	}
+}
+
+func TestRotatingHeadersKeepsTheLimit(t *testing.T) {
+	mux := chi.NewRouter()
+	mux.Use(NewLimiter(Limit{Rate: 0.001, Burst: 2}, nil, nil, 0).Middleware(mux))
+	mux.Get("/", func(w http.ResponseWriter, r *http.Request) {})
+
+	codes := []int{}
+	for i := 0; i < 3; i++ {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = "192.0.2.1:4242"
+		r.Header.Set(APIKeyHeader, "key-"+strconv.Itoa(i))
+		r.Header.Set("X-User-ID", strconv.Itoa(i))
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, r)
+		codes = append(codes, rec.Code)
+	}
+
+	if codes[2] != http.StatusTooManyRequests {
+		t.Errorf("codes = %v, want the third request limited", codes)
+	}
+}
+
+func TestBucketsAreCapped(t *testing.T) {
+	l := NewLimiter(Limit{Rate: 0.001, Burst: 1}, nil, nil, 2)
+	limit := Limit{Rate: 0.001, Burst: 1}
+
+	l.take("a", limit)
+	l.take("b", limit)
+	l.take("a", limit)
+	l.take("c", limit)
+
+	if len(l.buckets) != 2 || l.recent.Len() != 2 {
+		t.Fatalf("buckets = %d, list = %d, want 2", len(l.buckets), l.recent.Len())
+	}
+
+	if _, ok := l.buckets["b"]; ok {
+		t.Error("b was kept, want the least recently used bucket evicted")
+	}
+
+	if allowed, _, _ := l.take("a", limit); allowed {
+		t.Error("a got a token, want its bucket kept empty")
+	}
+}
+
+func TestRealIP(t *testing.T) {
+	trusted, err := ParseNetworks("10.0.0.0/8,192.0.2.10")
+	if err != nil {
+		t.Fatalf("ParseNetworks: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		peer      string
+		forwarded string
+		realIP    string
+		client    string
+	}{
+		{name: "untrusted peer", peer: "203.0.113.9:1234", forwarded: "198.51.100.7", client: "203.0.113.9:1234"},
+		{name: "untrusted peer with x-real-ip", peer: "203.0.113.9:1234", realIP: "198.51.100.7", client: "203.0.113.9:1234"},
+		{name: "trusted peer", peer: "10.0.0.1:1234", forwarded: "198.51.100.7", client: "198.51.100.7"},
+		{name: "spoofed hops left of the client", peer: "10.0.0.1:1234", forwarded: "6.6.6.6, 198.51.100.7, 10.0.0.2", client: "198.51.100.7"},
+		{name: "single trusted ip", peer: "192.0.2.10:1234", realIP: "198.51.100.7", client: "198.51.100.7"},
+		{name: "trusted peer without headers", peer: "10.0.0.1:1234", client: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.peer
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			var client string
+			RealIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				client = r.RemoteAddr
+			})).ServeHTTP(httptest.NewRecorder(), r)
+
+			if client != tt.client {
+				t.Errorf("RemoteAddr = %s, want %s", client, tt.client)
+			}
+		})
+	}
+}
@@ -12,10 +12,11 @@ import (
 )
 
 type AuthorRepositoryI interface {
-	CreateAuthor(ctx context.Context, trx *gorm.DB, input author.AuthorInput) (err error)
-	GetAllAuthors(ctx context.Context, name string) (resp []author.AuthorResponse, err error)
-	GetAuthorById(ctx context.Context, id int64, email string) (resp author.AuthorResponse, err error)
-	UpdateAuthor(ctx context.Context, trx *gorm.DB, id int64, input author.AuthorInput) (err error)
+	CreateAuthor(ctx context.Context, trx *gorm.DB, input author.AuthorInput) (id int64, err error)
+	GetAllAuthors(ctx context.Context, name string, similarity float64) (resp []author.AuthorResponse, err error)
+	GetAuthorsByIDs(ctx context.Context, ids []int64) (resp []author.AuthorResponse, err error)
+	GetAuthorById(ctx context.Context, trx *gorm.DB, id int64, email string) (resp author.AuthorResponse, err error)
+	UpdateAuthor(ctx context.Context, trx *gorm.DB, id int64, input author.AuthorInput, columns ...string) (err error)
 	DeleteAuthor(ctx context.Context, trx *gorm.DB, id int64) error
 }
 
@@ -28,7 +29,7 @@ func NewAuthorRepository(conn *gorm.DB) AuthorRepositoryI {
 }
 
 // CreateAuthor implements AuthorRepositoryI.
-func (a AuthorRepository) CreateAuthor(ctx context.Context, trx *gorm.DB, input author.AuthorInput) (err error) {
+func (a AuthorRepository) CreateAuthor(ctx context.Context, trx *gorm.DB, input author.AuthorInput) (id int64, err error) {
 	if trx == nil {
 		trx = a.conn.WithContext(ctx)
 	}
@@ -40,10 +41,10 @@ func (a AuthorRepository) CreateAuthor(ctx context.Context, trx *gorm.DB, input
 
 	sql := trx.Table("public" + "." + "tb_author").Create(&input)
 	if sql.Error != nil {
-		return sql.Error
+		return id, sql.Error
 	}
 
-	return nil
+	return input.ID, nil
 }
 
 // DeleteAuthor implements AuthorRepositoryI.
@@ -61,43 +62,58 @@ func (a AuthorRepository) DeleteAuthor(ctx context.Context, trx *gorm.DB, id int
 }
 
 // GetAllAuthors implements AuthorRepositoryI.
-func (a AuthorRepository) GetAllAuthors(ctx context.Context, name string) (resp []author.AuthorResponse, err error) {
+// With a similarity threshold the name matches by trigram similarity, best match first;
+// otherwise it matches as a substring.
+func (a AuthorRepository) GetAllAuthors(ctx context.Context, name string, similarity float64) (resp []author.AuthorResponse, err error) {
 	query := `SELECT id, name, email, created_at, updated_at FROM ` + _db.AuthorTableName
 
 	params := []interface{}{}
-	if name != "" {
-		query += ` WHERE lower(name) ilike ?`
+	switch {
+	case name != "" && similarity > 0:
+		condition, matchParams := trigramMatch(a.conn, "name", name, similarity)
+		query += ` WHERE ` + condition + ` ORDER BY similarity(name, ?) DESC, id ASC`
+		params = append(append(params, matchParams...), name)
+	case name != "":
+		query += ` WHERE lower(name) ` + ilike(a.conn) + ` ? ORDER BY id ASC`
 		params = append(params, fmt.Sprintf("%%%s%%", strings.ToLower(name)))
+	default:
+		query += ` ORDER BY id ASC`
 	}
 
-	query += ` ORDER BY id ASC`
-
-	sql := a.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
-	if sql.Error != nil {
-		return resp, sql.Error
-	}
+	err = withSimilarityThreshold(ctx, a.conn, similarity, func(db *gorm.DB) error {
+		return db.Raw(query, params...).Scan(&resp).Error
+	})
 
 	return resp, err
 }
 
 // GetAuthorById implements AuthorRepositoryI.
-func (a AuthorRepository) GetAuthorById(ctx context.Context, id int64, email string) (resp author.AuthorResponse, err error) {
+func (a AuthorRepository) GetAuthorById(ctx context.Context, trx *gorm.DB, id int64, email string) (resp author.AuthorResponse, err error) {
+	if trx == nil {
+		trx = a.conn.WithContext(ctx)
+	}
+
 	params := []interface{}{}
+	where := []string{}
 	query := `SELECT id, name, email, created_at, updated_at FROM ` + _db.AuthorTableName
 
 	if id != 0 {
-		query += ` WHERE id = ?`
+		where = append(where, `id = ?`)
 		params = append(params, id)
 	}
 
 	if email != "" {
-		query += ` WHERE lower(email) = ?`
+		where = append(where, `lower(email) = ?`)
 		params = append(params, email)
 	}
 
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+
 	query += ` LIMIT 1`
 
-	sql := a.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
+	sql := trx.Raw(query, params...).Scan(&resp)
 	if sql.Error != nil {
 		return resp, sql.Error
 	}
@@ -106,7 +122,8 @@ func (a AuthorRepository) GetAuthorById(ctx context.Context, id int64, email str
 }
 
 // UpdateAuthor implements AuthorRepositoryI.
-func (a AuthorRepository) UpdateAuthor(ctx context.Context, trx *gorm.DB, id int64, input author.AuthorInput) (err error) {
+// When columns are given only those are written, which is how patches leave the rest alone.
+func (a AuthorRepository) UpdateAuthor(ctx context.Context, trx *gorm.DB, id int64, input author.AuthorInput, columns ...string) (err error) {
 	if trx == nil {
 		trx = a.conn.WithContext(ctx)
 	}
@@ -118,6 +135,8 @@ func (a AuthorRepository) UpdateAuthor(ctx context.Context, trx *gorm.DB, id int
 		"updated_at": &now,
 	}
 
+	updateAuthor = onlyColumns(updateAuthor, columns)
+
 	sql := trx.Table(_db.AuthorTableName).Where("id = ?", id).Updates(updateAuthor)
 	if sql.Error != nil {
 		return sql.Error
@@ -125,3 +144,15 @@ func (a AuthorRepository) UpdateAuthor(ctx context.Context, trx *gorm.DB, id int
 
 	return err
 }
+
+// GetAuthorsByIDs implements AuthorRepositoryI.
+func (a AuthorRepository) GetAuthorsByIDs(ctx context.Context, ids []int64) (resp []author.AuthorResponse, err error) {
+	query := `SELECT id, name, email, created_at, updated_at FROM ` + _db.AuthorTableName + ` WHERE id IN ? ORDER BY id ASC`
+
+	sql := a.conn.WithContext(ctx).Raw(query, ids).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
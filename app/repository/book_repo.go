@@ -2,19 +2,28 @@ package repository
 
 import (
 	"context"
+	"strconv"
+	"strings"
 	"time"
 
 	_db "github.com/book-library/app/helper"
 	"github.com/book-library/entity/book"
+	"github.com/book-library/entity/review"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BookLibraryRepositoryI interface {
-	CreateBookLibrary(ctx context.Context, trx *gorm.DB, input book.BookInput) (err error)
-	GetAllBookLibraries(ctx context.Context, search string) (resp []book.BookResponse, err error)
-	GetBookLibraryById(ctx context.Context, id, authorID, categoryID int64) (resp book.BookResponse, err error)
-	UpdateBookLibrary(ctx context.Context, trx *gorm.DB, id int64, input book.BookInput) (rerr error)
+	CreateBookLibrary(ctx context.Context, trx *gorm.DB, input book.BookInput) (id int64, err error)
+	CountBooks(ctx context.Context) (total int64, err error)
+	GetAllBookLibraries(ctx context.Context, search book.BookSearch) (resp []book.BookResponse, err error)
+	GetBookFacets(ctx context.Context, search book.BookSearch) (resp book.BookFacets, err error)
+	GetBookLibraryById(ctx context.Context, trx *gorm.DB, id, authorID, categoryID, publisherID int64) (resp book.BookResponse, err error)
+	GetBookLibraryByISBN(ctx context.Context, trx *gorm.DB, isbn string) (resp book.BookResponse, err error)
+	UpdateBookLibrary(ctx context.Context, trx *gorm.DB, id int64, input book.BookInput, columns ...string) (rerr error)
 	DeleteBookLibrary(ctx context.Context, trx *gorm.DB, id int64) error
+	UpdateBookRating(ctx context.Context, trx *gorm.DB, id int64) error
+	GetSimilarBookCandidates(ctx context.Context, target book.BookResponse) (resp []book.BookResponse, err error)
 }
 
 type BookLibraryRepository struct {
@@ -26,7 +35,7 @@ func NewBookLibraryRepository(conn *gorm.DB) BookLibraryRepositoryI {
 }
 
 // CreateBookLibrary implements BookLibraryRepositoryI.
-func (b BookLibraryRepository) CreateBookLibrary(ctx context.Context, trx *gorm.DB, input book.BookInput) (err error) {
+func (b BookLibraryRepository) CreateBookLibrary(ctx context.Context, trx *gorm.DB, input book.BookInput) (id int64, err error) {
 	if trx == nil {
 		trx = b.conn.WithContext(ctx)
 	}
@@ -37,10 +46,10 @@ func (b BookLibraryRepository) CreateBookLibrary(ctx context.Context, trx *gorm.
 	input.UpdatedAt = nil
 	sql := trx.Table(_db.BookTableName).Create(&input)
 	if sql.Error != nil {
-		return sql.Error
+		return id, sql.Error
 	}
 
-	return nil
+	return input.ID, nil
 }
 
 // DeleteBookLibrary implements BookLibraryRepositoryI.
@@ -58,62 +67,303 @@ func (b BookLibraryRepository) DeleteBookLibrary(ctx context.Context, trx *gorm.
 }
 
 // GetAllBookLibrary implements BookLibraryRepositoryI.
-func (b BookLibraryRepository) GetAllBookLibraries(ctx context.Context, search string) (resp []book.BookResponse, err error) {
-	query := `
-		SELECT
-			tbb.id, tbb.title, tbb.description, tbb.isbn, tbb.published_flag, 
+// A fuzzy search (Similarity set) lists the best matches first unless another sort is asked for.
+func (b BookLibraryRepository) GetAllBookLibraries(ctx context.Context, search book.BookSearch) (resp []book.BookResponse, err error) {
+	err = withSimilarityThreshold(ctx, b.conn, bookSimilarity(search), func(db *gorm.DB) error {
+		return b.getAllBookLibraries(db, search).Scan(&resp).Error
+	})
+
+	return resp, err
+}
+
+func (b BookLibraryRepository) getAllBookLibraries(db *gorm.DB, search book.BookSearch) *gorm.DB {
+	query := db.
+		Table(_db.BookTableName + " tbb").
+		Select(`
+			tbb.id, tbb.title, tbb.description as boook_description, tbb.isbn, tbb.published_flag,
+			tbb.publication_year, tbb.edition, tbb.page_count, tbb.language,
 			tba.id as author_id, tba.name as author_name, tba.email as author_email,
-			tbc.id as category_id, tbc.name as category_name, tbc.description as category_description
-		FROM 
-			tb_book tbb
-		LEFT JOIN 
-			tb_category tbc on tbb.category_id = tbc.id 
-		LEFT JOIN 
-			tb_author tba on tbb.author_id = tba.id 
-		WHERE
-			tbb.published_flag = true
-	`
-	params := []interface{}{}
+			tbc.id as category_id, tbc.name as category_name, tbc.description as category_description,
+			coalesce(tbp.id, 0) as publisher_id, coalesce(tbp.name, '') as publisher_name,
+			coalesce(tbs.id, 0) as series_id, coalesce(tbs.name, '') as series_name, tbb.series_volume,
+			coalesce(tbw.id, 0) as work_id, coalesce(tbw.title, '') as work_title,
+			tbb.rating_average, tbb.rating_count, tbb.created_at, tbb.updated_at
+		`).
+		Joins(`LEFT JOIN ` + _db.CategoryTableName + ` tbc on tbb.category_id = tbc.id`).
+		Joins(`LEFT JOIN ` + _db.AuthorTableName + ` tba on tbb.author_id = tba.id`).
+		Joins(`LEFT JOIN ` + _db.PublisherTableName + ` tbp on tbb.publisher_id = tbp.id`).
+		Joins(`LEFT JOIN ` + _db.SeriesTableName + ` tbs on tbb.series_id = tbs.id`).
+		Joins(`LEFT JOIN ` + _db.WorkTableName + ` tbw on tbb.work_id = tbw.id`).
+		Scopes(bookFilters(search)...)
 
-	if search != "" {
-		query += ` AND tbb.isbn = ? OR tbb.title = ? OR tba.name = ?`
-		params = append(params, search, search, search)
+	switch {
+	case search.Sort == book.SortByRating:
+		return query.Order("tbb.rating_average DESC, tbb.rating_count DESC, tbb.id ASC")
+	case bookSimilarity(search) > 0:
+		return query.Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:  greatest(db) + "(similarity(tbb.title, ?), similarity(coalesce(tba.name, ''), ?)) DESC, tbb.id ASC",
+			Vars: []interface{}{search.Search, search.Search},
+		}})
+	default:
+		return query.Order("tbb.id ASC")
 	}
+}
 
-	sql := b.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
-	if sql.Error != nil {
-		return resp, sql.Error
+// bookSimilarity is the trigram threshold of search, 0 when it does not match fuzzily.
+func bookSimilarity(search book.BookSearch) float64 {
+	if search.Search == "" {
+		return 0
 	}
 
+	return search.Similarity
+}
+
+// GetBookFacets implements BookLibraryRepositoryI.
+// Every facet is one grouped query over the books matching search without the facet's own filter.
+func (b BookLibraryRepository) GetBookFacets(ctx context.Context, search book.BookSearch) (resp book.BookFacets, err error) {
+	err = withSimilarityThreshold(ctx, b.conn, bookSimilarity(search), func(db *gorm.DB) (err error) {
+		resp, err = b.getBookFacets(db, search)
+		return err
+	})
+
 	return resp, err
 }
 
-// GetBookLibraryById implements BookLibraryRepositoryI.
-func (b BookLibraryRepository) GetBookLibraryById(ctx context.Context, id, authorID, categoryID int64) (resp book.BookResponse, err error) {
-	query := `
+func (b BookLibraryRepository) getBookFacets(db *gorm.DB, search book.BookSearch) (resp book.BookFacets, err error) {
+	facetQuery := func(search book.BookSearch) *gorm.DB {
+		return db.Session(&gorm.Session{NewDB: true}).
+			Table(_db.BookTableName + " tbb").
+			Joins(`LEFT JOIN ` + _db.AuthorTableName + ` tba on tbb.author_id = tba.id`).
+			Scopes(bookFilters(search)...)
+	}
+
+	byCategory := search
+	byCategory.CategoryID = 0
+	err = facetQuery(byCategory).
+		Select(`CAST(tbb.category_id AS TEXT) as value, coalesce(tbc.name, '') as label, count(*) as count`).
+		Joins(`LEFT JOIN ` + _db.CategoryTableName + ` tbc on tbb.category_id = tbc.id`).
+		Group("tbb.category_id, tbc.name").
+		Order("count DESC, tbb.category_id ASC").
+		Scan(&resp.Category).Error
+	if err != nil {
+		return resp, err
+	}
+
+	byAuthor := search
+	byAuthor.AuthorID = 0
+	err = facetQuery(byAuthor).
+		Select(`CAST(tbb.author_id AS TEXT) as value, coalesce(tba.name, '') as label, count(*) as count`).
+		Group("tbb.author_id, tba.name").
+		Order("count DESC, tbb.author_id ASC").
+		Scan(&resp.Author).Error
+	if err != nil {
+		return resp, err
+	}
+
+	var years []struct {
+		Bucket int
+		Count  int64
+	}
+	byYear := search
+	byYear.PublicationYearFrom, byYear.PublicationYearTo = 0, 0
+	err = facetQuery(byYear).
+		Select("(tbb.publication_year / ?) * ? as bucket, count(*) as count", book.PublicationYearBucket, book.PublicationYearBucket).
+		Group("bucket").
+		Order("bucket DESC").
+		Scan(&years).Error
+	if err != nil {
+		return resp, err
+	}
+
+	for _, year := range years {
+		label := "unknown"
+		if year.Bucket != 0 {
+			label = strconv.Itoa(year.Bucket) + "-" + strconv.Itoa(year.Bucket+book.PublicationYearBucket-1)
+		}
+
+		resp.PublicationYear = append(resp.PublicationYear, book.BookFacetBucket{Value: strconv.Itoa(year.Bucket), Label: label, Count: year.Count})
+	}
+
+	var availability []struct {
+		PublishedFlag bool
+		Count         int64
+	}
+	byAvailability := search
+	byAvailability.Published = book.PublishedAny
+	err = facetQuery(byAvailability).
+		Select("tbb.published_flag, count(*) as count").
+		Group("tbb.published_flag").
+		Order("tbb.published_flag DESC").
+		Scan(&availability).Error
+	if err != nil {
+		return resp, err
+	}
+
+	for _, state := range availability {
+		bucket := book.BookFacetBucket{Value: book.PublishedFalse, Label: "unpublished", Count: state.Count}
+		if state.PublishedFlag {
+			bucket = book.BookFacetBucket{Value: book.PublishedTrue, Label: "published", Count: state.Count}
+		}
+
+		resp.Availability = append(resp.Availability, bucket)
+	}
+
+	return resp, nil
+}
+
+// bookFilters turns search into one scope per filter. Each scope adds its own parameterized
+// condition, so they compose with AND whatever the combination.
+func bookFilters(search book.BookSearch) (scopes []func(*gorm.DB) *gorm.DB) {
+	where := func(condition string, args ...interface{}) {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			return db.Where(condition, args...)
+		})
+	}
+
+	// dialectWhere is where for conditions that depend on the engine of db.
+	dialectWhere := func(condition func(db *gorm.DB) (string, []interface{})) {
+		scopes = append(scopes, func(db *gorm.DB) *gorm.DB {
+			query, args := condition(db)
+			return db.Where(query, args...)
+		})
+	}
+
+	switch search.Published {
+	case book.PublishedAny:
+	case book.PublishedFalse:
+		where("tbb.published_flag = ?", false)
+	default:
+		where("tbb.published_flag = ?", true)
+	}
+
+	if search.AuthorID != 0 {
+		where("tbb.author_id = ?", search.AuthorID)
+	}
+
+	if search.CategoryID != 0 {
+		where("tbb.category_id = ?", search.CategoryID)
+	}
+
+	if search.PublisherID != 0 {
+		where("tbb.publisher_id = ?", search.PublisherID)
+	}
+
+	if search.PublicationYearFrom != 0 {
+		where("tbb.publication_year >= ?", search.PublicationYearFrom)
+	}
+
+	if search.PublicationYearTo != 0 {
+		where("tbb.publication_year <= ?", search.PublicationYearTo)
+	}
+
+	if search.ISBNPrefix != "" {
+		dialectWhere(func(db *gorm.DB) (string, []interface{}) {
+			return "tbb.isbn LIKE ?" + likeEscape(db), []interface{}{escapeLike(search.ISBNPrefix) + "%"}
+		})
+	}
+
+	if search.TitleContains != "" {
+		dialectWhere(func(db *gorm.DB) (string, []interface{}) {
+			return "tbb.title " + ilike(db) + " ?" + likeEscape(db), []interface{}{"%" + escapeLike(search.TitleContains) + "%"}
+		})
+	}
+
+	if search.CreatedFrom != nil {
+		where("tbb.created_at >= ?", *search.CreatedFrom)
+	}
+
+	if search.CreatedTo != nil {
+		where("tbb.created_at < ?", *search.CreatedTo)
+	}
+
+	if search.UpdatedFrom != nil {
+		where("tbb.updated_at >= ?", *search.UpdatedFrom)
+	}
+
+	if search.UpdatedTo != nil {
+		where("tbb.updated_at < ?", *search.UpdatedTo)
+	}
+
+	switch {
+	case bookSimilarity(search) > 0:
+		dialectWhere(func(db *gorm.DB) (string, []interface{}) {
+			title, titleParams := trigramMatch(db, "tbb.title", search.Search, search.Similarity)
+			name, nameParams := trigramMatch(db, "tba.name", search.Search, search.Similarity)
+			params := append(append([]interface{}{search.Search}, titleParams...), nameParams...)
+			return "(tbb.isbn = ? OR " + title + " OR " + name + ")", params
+		})
+	case search.Search != "":
+		where("(tbb.isbn = ? OR tbb.title = ? OR tba.name = ?)", search.Search, search.Search, search.Search)
+	}
+
+	return scopes
+}
+
+// escapeLike makes the LIKE wildcards in value match literally.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// selectBookRow reads the tb_book columns of a BookResponse.
+const selectBookRow = `
 		SELECT
-			tbb.id, tbb.title, tbb.isbn, tbb.description,tbb.published_flag, tbb.author_id, tbb.category_id
+			tbb.id, tbb.title, tbb.isbn, tbb.description as boook_description, tbb.published_flag, tbb.author_id, tbb.category_id,
+			coalesce(tbb.publisher_id, 0) as publisher_id, coalesce(tbb.series_id, 0) as series_id, tbb.series_volume,
+			coalesce(tbb.work_id, 0) as work_id, tbb.publication_year, tbb.edition, tbb.page_count, tbb.language,
+			tbb.rating_average, tbb.rating_count, tbb.created_at, tbb.updated_at
 		FROM 
 			tb_book tbb
 	`
 
+// GetBookLibraryById implements BookLibraryRepositoryI.
+func (b BookLibraryRepository) GetBookLibraryById(ctx context.Context, trx *gorm.DB, id, authorID, categoryID, publisherID int64) (resp book.BookResponse, err error) {
+	if trx == nil {
+		trx = b.conn.WithContext(ctx)
+	}
+
+	query := selectBookRow
+
 	params := []interface{}{}
+	where := []string{}
 	if id != 0 {
-		query += ` WHERE tbb.id = ?`
+		where = append(where, `tbb.id = ?`)
 		params = append(params, id)
 	}
 
 	if authorID != 0 {
-		query += ` WHERE tbb.author_id = ?`
+		where = append(where, `tbb.author_id = ?`)
 		params = append(params, authorID)
 	}
 
 	if categoryID != 0 {
-		query += ` WHERE tbb.category_id = ?`
+		where = append(where, `tbb.category_id = ?`)
 		params = append(params, categoryID)
 	}
 
-	sql := b.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
+	if publisherID != 0 {
+		where = append(where, `tbb.publisher_id = ?`)
+		params = append(params, publisherID)
+	}
+
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+
+	sql := trx.Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// GetBookLibraryByISBN implements BookLibraryRepositoryI.
+func (b BookLibraryRepository) GetBookLibraryByISBN(ctx context.Context, trx *gorm.DB, isbn string) (resp book.BookResponse, err error) {
+	if trx == nil {
+		trx = b.conn.WithContext(ctx)
+	}
+
+	sql := trx.Raw(selectBookRow+` WHERE tbb.isbn = ? LIMIT 1`, isbn).Scan(&resp)
 	if sql.Error != nil {
 		return resp, sql.Error
 	}
@@ -122,22 +372,33 @@ func (b BookLibraryRepository) GetBookLibraryById(ctx context.Context, id, autho
 }
 
 // UpdateBookLibrary implements BookLibraryRepositoryI.
-func (b BookLibraryRepository) UpdateBookLibrary(ctx context.Context, trx *gorm.DB, id int64, input book.BookInput) (err error) {
+// When columns are given only those are written, which is how patches leave the rest alone.
+func (b BookLibraryRepository) UpdateBookLibrary(ctx context.Context, trx *gorm.DB, id int64, input book.BookInput, columns ...string) (err error) {
 	if trx == nil {
 		trx = b.conn.WithContext(ctx)
 	}
 
 	now := time.Now()
 	updateBookLibrary := map[string]interface{}{
-		"title":          input.Title,
-		"isbn":           input.ISBN,
-		"description":    input.Description,
-		"published_flag": input.PublishedFlag,
-		"author_id":      input.AuthorID,
-		"category_id":    input.CategoryID,
-		"updated_at":     &now,
+		"title":            input.Title,
+		"isbn":             input.ISBN,
+		"description":      input.Description,
+		"published_flag":   input.PublishedFlag,
+		"author_id":        input.AuthorID,
+		"category_id":      input.CategoryID,
+		"publisher_id":     input.PublisherID,
+		"series_id":        input.SeriesID,
+		"series_volume":    input.SeriesVolume,
+		"work_id":          input.WorkID,
+		"publication_year": input.PublicationYear,
+		"edition":          input.Edition,
+		"page_count":       input.PageCount,
+		"language":         input.Language,
+		"updated_at":       &now,
 	}
 
+	updateBookLibrary = onlyColumns(updateBookLibrary, columns)
+
 	sql := trx.Table(_db.BookTableName).Where("id = ?", id).Updates(updateBookLibrary)
 	if sql.Error != nil {
 		return sql.Error
@@ -145,3 +406,74 @@ func (b BookLibraryRepository) UpdateBookLibrary(ctx context.Context, trx *gorm.
 
 	return err
 }
+
+// UpdateBookRating implements BookLibraryRepositoryI.
+// The book row is locked first so concurrent review writes recompute the aggregate one after another.
+func (b BookLibraryRepository) UpdateBookRating(ctx context.Context, trx *gorm.DB, id int64) error {
+	if trx == nil {
+		trx = b.conn.WithContext(ctx)
+	}
+
+	if err := lockForUpdate(trx, _db.BookTableName, id); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE ` + _db.BookTableName + ` SET
+			rating_average = coalesce((SELECT avg(rating) FROM ` + _db.ReviewTableName + ` WHERE book_id = ? AND status = ?), 0),
+			rating_count = (SELECT count(*) FROM ` + _db.ReviewTableName + ` WHERE book_id = ? AND status = ?)
+		WHERE id = ?
+	`
+
+	sql := trx.Exec(query, id, review.StatusApproved, id, review.StatusApproved, id)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// GetSimilarBookCandidates implements BookLibraryRepositoryI.
+// Candidates are published books sharing the author, category or series of the target.
+// Other editions of the same work are left out because they are the same title.
+func (b BookLibraryRepository) GetSimilarBookCandidates(ctx context.Context, target book.BookResponse) (resp []book.BookResponse, err error) {
+	query := `
+		SELECT
+			tbb.id, tbb.title, tbb.author_id, tba.name as author_name, tbb.category_id, tbc.name as category_name,
+			coalesce(tbb.series_id, 0) as series_id, coalesce(tbb.work_id, 0) as work_id,
+			tbb.rating_average, tbb.rating_count
+		FROM
+			tb_book tbb
+		LEFT JOIN
+			tb_author tba on tbb.author_id = tba.id
+		LEFT JOIN
+			tb_category tbc on tbb.category_id = tbc.id
+		WHERE
+			tbb.published_flag = true
+			AND tbb.id <> ?
+			AND (tbb.author_id = ? OR tbb.category_id = ? OR (tbb.series_id IS NOT NULL AND tbb.series_id = ?))
+	`
+	params := []interface{}{target.ID, target.AuthorID, target.CategoryID, target.SeriesID}
+
+	if target.WorkID != 0 {
+		query += ` AND (tbb.work_id IS NULL OR tbb.work_id <> ?)`
+		params = append(params, target.WorkID)
+	}
+
+	sql := b.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// CountBooks implements BookLibraryRepositoryI.
+func (b BookLibraryRepository) CountBooks(ctx context.Context) (total int64, err error) {
+	sql := b.conn.WithContext(ctx).Table(_db.BookTableName).Count(&total)
+	if sql.Error != nil {
+		return total, sql.Error
+	}
+
+	return total, err
+}
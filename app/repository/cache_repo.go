@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/book-library/app/cache"
+	"github.com/book-library/app/metrics"
+	"github.com/book-library/entity/author"
+	"github.com/book-library/entity/book"
+	"github.com/book-library/entity/category"
+	_l "github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// The cached repositories decorate the lookups by id with a read-through cache. Writes drop
+// the entry before calling the wrapped repository, and once more when the write is visible to
+// other connections: right after it without a transaction, after the commit with one. The second
+// drop removes an old row a read put back while the write was not committed yet.
+
+// Cache is the store shared by the cached repositories. It remembers the keys written inside a
+// transaction until CachedTransactionRepository ends it.
+type Cache struct {
+	store   cache.Store
+	ttl     time.Duration
+	mu      sync.Mutex
+	pending map[*gorm.DB][]cacheEntry
+}
+
+type cacheEntry struct {
+	name string
+	key  string
+}
+
+func NewCache(store cache.Store, ttl time.Duration) *Cache {
+	return &Cache{store: store, ttl: ttl, pending: map[*gorm.DB][]cacheEntry{}}
+}
+
+func cacheKey(name string, id int64) string {
+	return name + ":" + strconv.FormatInt(id, 10)
+}
+
+// readThrough returns the cached value of key, or loads, stores and returns it. Values with
+// found false are not stored so a create is visible right away. Cache failures are logged and
+// fall back to load, the database stays the source of truth.
+func readThrough[T any](ctx context.Context, store cache.Store, name, key string, ttl time.Duration, load func() (T, error), found func(T) bool) (resp T, err error) {
+	_log := _l.Ctx(ctx)
+
+	value, ok, err := store.Get(ctx, key)
+	if err != nil {
+		_log.Warn().Err(err).Str("key", key).Msg("store.Get got an error on cache " + name)
+	}
+
+	if ok && json.Unmarshal(value, &resp) == nil {
+		metrics.ObserveCache(name, true)
+		return resp, nil
+	}
+	metrics.ObserveCache(name, false)
+
+	resp, err = load()
+	if err != nil || !found(resp) {
+		return resp, err
+	}
+
+	value, err = json.Marshal(resp)
+	if err == nil {
+		err = store.Set(ctx, key, value, ttl)
+	}
+	if err != nil {
+		_log.Warn().Err(err).Str("key", key).Msg("store.Set got an error on cache " + name)
+	}
+
+	return resp, nil
+}
+
+func (c *Cache) invalidate(ctx context.Context, entries ...cacheEntry) {
+	for _, entry := range entries {
+		if err := c.store.Delete(ctx, entry.key); err != nil {
+			_l.Ctx(ctx).Warn().Err(err).Str("key", entry.key).Msg("store.Delete got an error on cache " + entry.name)
+		}
+	}
+}
+
+// write drops key around the write. Inside a transaction the second drop waits for the commit.
+func (c *Cache) write(ctx context.Context, trx *gorm.DB, name, key string, write func() error) error {
+	entry := cacheEntry{name: name, key: key}
+	c.invalidate(ctx, entry)
+
+	err := write()
+	if trx == nil {
+		c.invalidate(ctx, entry)
+		return err
+	}
+
+	c.mu.Lock()
+	c.pending[trx] = append(c.pending[trx], entry)
+	c.mu.Unlock()
+
+	return err
+}
+
+// end forgets the keys written in trx and returns them.
+func (c *Cache) end(trx *gorm.DB) []cacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := c.pending[trx]
+	delete(c.pending, trx)
+
+	return entries
+}
+
+// CachedTransactionRepository drops the keys written in a transaction after it commits. The
+// cached repositories only see committed rows when every transaction goes through it.
+type CachedTransactionRepository struct {
+	TransactionRepositoryI
+	cache *Cache
+}
+
+func NewCachedTransactionRepository(next TransactionRepositoryI, c *Cache) TransactionRepositoryI {
+	return CachedTransactionRepository{TransactionRepositoryI: next, cache: c}
+}
+
+// RollBackTransaction implements TransactionRepositoryI.
+func (c CachedTransactionRepository) RollBackTransaction(ctx context.Context, trx *gorm.DB) *gorm.DB {
+	c.cache.end(trx)
+	return c.TransactionRepositoryI.RollBackTransaction(ctx, trx)
+}
+
+// CommitTransaction implements TransactionRepositoryI. The keys are dropped even when the commit
+// fails, whether it went through is not known then.
+func (c CachedTransactionRepository) CommitTransaction(ctx context.Context, trx *gorm.DB) *gorm.DB {
+	result := c.TransactionRepositoryI.CommitTransaction(ctx, trx)
+	c.cache.invalidate(ctx, c.cache.end(trx)...)
+
+	return result
+}
+
+type CachedBookLibraryRepository struct {
+	BookLibraryRepositoryI
+	cache *Cache
+}
+
+func NewCachedBookLibraryRepository(next BookLibraryRepositoryI, c *Cache) BookLibraryRepositoryI {
+	return CachedBookLibraryRepository{BookLibraryRepositoryI: next, cache: c}
+}
+
+// GetBookLibraryById implements BookLibraryRepositoryI. Only the lookup by book id outside a
+// transaction is cached, a transaction has to see its own writes.
+func (c CachedBookLibraryRepository) GetBookLibraryById(ctx context.Context, trx *gorm.DB, id, authorID, categoryID, publisherID int64) (resp book.BookResponse, err error) {
+	if trx != nil || id == 0 || authorID != 0 || categoryID != 0 || publisherID != 0 {
+		return c.BookLibraryRepositoryI.GetBookLibraryById(ctx, trx, id, authorID, categoryID, publisherID)
+	}
+
+	return readThrough(ctx, c.cache.store, "book", cacheKey("book", id), c.cache.ttl, func() (book.BookResponse, error) {
+		return c.BookLibraryRepositoryI.GetBookLibraryById(ctx, nil, id, 0, 0, 0)
+	}, func(resp book.BookResponse) bool { return resp.ID != 0 })
+}
+
+// UpdateBookLibrary implements BookLibraryRepositoryI.
+func (c CachedBookLibraryRepository) UpdateBookLibrary(ctx context.Context, trx *gorm.DB, id int64, input book.BookInput, columns ...string) (err error) {
+	return c.cache.write(ctx, trx, "book", cacheKey("book", id), func() error {
+		return c.BookLibraryRepositoryI.UpdateBookLibrary(ctx, trx, id, input, columns...)
+	})
+}
+
+// DeleteBookLibrary implements BookLibraryRepositoryI.
+func (c CachedBookLibraryRepository) DeleteBookLibrary(ctx context.Context, trx *gorm.DB, id int64) error {
+	return c.cache.write(ctx, trx, "book", cacheKey("book", id), func() error {
+		return c.BookLibraryRepositoryI.DeleteBookLibrary(ctx, trx, id)
+	})
+}
+
+// UpdateBookRating implements BookLibraryRepositoryI.
+func (c CachedBookLibraryRepository) UpdateBookRating(ctx context.Context, trx *gorm.DB, id int64) error {
+	return c.cache.write(ctx, trx, "book", cacheKey("book", id), func() error {
+		return c.BookLibraryRepositoryI.UpdateBookRating(ctx, trx, id)
+	})
+}
+
+type CachedAuthorRepository struct {
+	AuthorRepositoryI
+	cache *Cache
+}
+
+func NewCachedAuthorRepository(next AuthorRepositoryI, c *Cache) AuthorRepositoryI {
+	return CachedAuthorRepository{AuthorRepositoryI: next, cache: c}
+}
+
+// GetAuthorById implements AuthorRepositoryI. Only the lookup by author id outside a
+// transaction is cached.
+func (c CachedAuthorRepository) GetAuthorById(ctx context.Context, trx *gorm.DB, id int64, email string) (resp author.AuthorResponse, err error) {
+	if trx != nil || id == 0 || email != "" {
+		return c.AuthorRepositoryI.GetAuthorById(ctx, trx, id, email)
+	}
+
+	return readThrough(ctx, c.cache.store, "author", cacheKey("author", id), c.cache.ttl, func() (author.AuthorResponse, error) {
+		return c.AuthorRepositoryI.GetAuthorById(ctx, nil, id, "")
+	}, func(resp author.AuthorResponse) bool { return resp.ID != 0 })
+}
+
+// UpdateAuthor implements AuthorRepositoryI.
+func (c CachedAuthorRepository) UpdateAuthor(ctx context.Context, trx *gorm.DB, id int64, input author.AuthorInput, columns ...string) (err error) {
+	return c.cache.write(ctx, trx, "author", cacheKey("author", id), func() error {
+		return c.AuthorRepositoryI.UpdateAuthor(ctx, trx, id, input, columns...)
+	})
+}
+
+// DeleteAuthor implements AuthorRepositoryI.
+func (c CachedAuthorRepository) DeleteAuthor(ctx context.Context, trx *gorm.DB, id int64) error {
+	return c.cache.write(ctx, trx, "author", cacheKey("author", id), func() error {
+		return c.AuthorRepositoryI.DeleteAuthor(ctx, trx, id)
+	})
+}
+
+type CachedCategoryRepository struct {
+	CategoryRepositoryI
+	cache *Cache
+}
+
+func NewCachedCategoryRepository(next CategoryRepositoryI, c *Cache) CategoryRepositoryI {
+	return CachedCategoryRepository{CategoryRepositoryI: next, cache: c}
+}
+
+// GetCategoryById implements CategoryRepositoryI. Only the lookup by category id outside a
+// transaction is cached.
+func (c CachedCategoryRepository) GetCategoryById(ctx context.Context, trx *gorm.DB, id int64, name string) (resp category.CategoryResponse, err error) {
+	if trx != nil || id == 0 || name != "" {
+		return c.CategoryRepositoryI.GetCategoryById(ctx, trx, id, name)
+	}
+
+	return readThrough(ctx, c.cache.store, "category", cacheKey("category", id), c.cache.ttl, func() (category.CategoryResponse, error) {
+		return c.CategoryRepositoryI.GetCategoryById(ctx, nil, id, "")
+	}, func(resp category.CategoryResponse) bool { return resp.ID != 0 })
+}
+
+// UpdateCategory implements CategoryRepositoryI.
+func (c CachedCategoryRepository) UpdateCategory(ctx context.Context, trx *gorm.DB, id int64, input category.CategoryInput, columns ...string) (err error) {
+	return c.cache.write(ctx, trx, "category", cacheKey("category", id), func() error {
+		return c.CategoryRepositoryI.UpdateCategory(ctx, trx, id, input, columns...)
+	})
+}
+
+// DeleteCategory implements CategoryRepositoryI.
+func (c CachedCategoryRepository) DeleteCategory(ctx context.Context, trx *gorm.DB, id int64) error {
+	return c.cache.write(ctx, trx, "category", cacheKey("category", id), func() error {
+		return c.CategoryRepositoryI.DeleteCategory(ctx, trx, id)
+	})
+}
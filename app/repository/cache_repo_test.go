@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/book-library/app/cache"
+	"github.com/book-library/entity/author"
+	"gorm.io/gorm"
+)
+
+// stubAuthorDB keeps one committed author name and the name written by the open transaction, so
+// a read can race an uncommitted update.
+type stubAuthorDB struct {
+	AuthorRepositoryI
+	committed string
+	pending   string
+}
+
+func (s *stubAuthorDB) GetAuthorById(ctx context.Context, trx *gorm.DB, id int64, email string) (author.AuthorResponse, error) {
+	return author.AuthorResponse{ID: id, Name: s.committed}, nil
+}
+
+func (s *stubAuthorDB) UpdateAuthor(ctx context.Context, trx *gorm.DB, id int64, input author.AuthorInput, columns ...string) error {
+	if trx == nil {
+		s.committed = input.Name
+		return nil
+	}
+
+	s.pending = input.Name
+	return nil
+}
+
+func (s *stubAuthorDB) BeginTransaction(ctx context.Context) *gorm.DB {
+	return &gorm.DB{}
+}
+
+func (s *stubAuthorDB) RollBackTransaction(ctx context.Context, trx *gorm.DB) *gorm.DB {
+	s.pending = ""
+	return &gorm.DB{}
+}
+
+func (s *stubAuthorDB) CommitTransaction(ctx context.Context, trx *gorm.DB) *gorm.DB {
+	s.committed, s.pending = s.pending, ""
+	return &gorm.DB{}
+}
+
+func newStubAuthorCache(committed string) (*stubAuthorDB, AuthorRepositoryI, TransactionRepositoryI) {
+	db := &stubAuthorDB{committed: committed}
+	c := NewCache(cache.NewLRU(10), 0)
+
+	return db, NewCachedAuthorRepository(db, c), NewCachedTransactionRepository(db, c)
+}
+
+func authorName(t *testing.T, repo AuthorRepositoryI) string {
+	t.Helper()
+
+	resp, err := repo.GetAuthorById(context.Background(), nil, 1, "")
+	if err != nil {
+		t.Fatalf("GetAuthorById: %v", err)
+	}
+
+	return resp.Name
+}
+
+func TestCachedRepositoryInvalidatesAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	_, repo, trRepo := newStubAuthorCache("Ann Leckie")
+	authorName(t, repo)
+
+	trx := trRepo.BeginTransaction(ctx)
+	if err := repo.UpdateAuthor(ctx, trx, 1, author.AuthorInput{Name: "Ursula K. Le Guin"}); err != nil {
+		t.Fatalf("UpdateAuthor: %v", err)
+	}
+
+	// A read between the write and the commit still sees, and caches, the committed row.
+	if name := authorName(t, repo); name != "Ann Leckie" {
+		t.Fatalf("name before the commit = %s, want Ann Leckie", name)
+	}
+
+	trRepo.CommitTransaction(ctx, trx)
+
+	if name := authorName(t, repo); name != "Ursula K. Le Guin" {
+		t.Errorf("name after the commit = %s, want the committed update", name)
+	}
+}
+
+func TestCachedRepositoryKeepsEntryOnRollback(t *testing.T) {
+	ctx := context.Background()
+	db, repo, trRepo := newStubAuthorCache("Ann Leckie")
+
+	trx := trRepo.BeginTransaction(ctx)
+	repo.UpdateAuthor(ctx, trx, 1, author.AuthorInput{Name: "Ursula K. Le Guin"})
+	authorName(t, repo)
+	trRepo.RollBackTransaction(ctx, trx)
+
+	// Only the cache can answer now, the rolled back transaction did not change the row.
+	db.committed = "changed behind the cache"
+	if name := authorName(t, repo); name != "Ann Leckie" {
+		t.Errorf("name after the rollback = %s, want the cached row", name)
+	}
+
+	cached := trRepo.(CachedTransactionRepository).cache
+	if len(cached.pending) != 0 {
+		t.Errorf("pending = %v, want the ended transaction forgotten", cached.pending)
+	}
+}
+
+func TestCachedRepositoryInvalidatesWithoutTransaction(t *testing.T) {
+	_, repo, _ := newStubAuthorCache("Ann Leckie")
+	authorName(t, repo)
+
+	if err := repo.UpdateAuthor(context.Background(), nil, 1, author.AuthorInput{Name: "Ursula K. Le Guin"}); err != nil {
+		t.Fatalf("UpdateAuthor: %v", err)
+	}
+
+	if name := authorName(t, repo); name != "Ursula K. Le Guin" {
+		t.Errorf("name after the update = %s, want the update", name)
+	}
+}
@@ -12,10 +12,11 @@ import (
 )
 
 type CategoryRepositoryI interface {
-	CreateCategory(ctx context.Context, trx *gorm.DB, input category.CategoryInput) (err error)
-	GetAllCategories(ctx context.Context, name string) (resp []category.CategoryResponse, err error)
-	GetCategoryById(ctx context.Context, id int64, name string) (resp category.CategoryResponse, err error)
-	UpdateCategory(ctx context.Context, trx *gorm.DB, id int64, input category.CategoryInput) (err error)
+	CreateCategory(ctx context.Context, trx *gorm.DB, input category.CategoryInput) (id int64, err error)
+	GetAllCategories(ctx context.Context, name string, similarity float64) (resp []category.CategoryResponse, err error)
+	GetCategoriesByIDs(ctx context.Context, ids []int64) (resp []category.CategoryResponse, err error)
+	GetCategoryById(ctx context.Context, trx *gorm.DB, id int64, name string) (resp category.CategoryResponse, err error)
+	UpdateCategory(ctx context.Context, trx *gorm.DB, id int64, input category.CategoryInput, columns ...string) (err error)
 	DeleteCategory(ctx context.Context, trx *gorm.DB, id int64) error
 }
 
@@ -28,7 +29,7 @@ func NewCategoryRepository(conn *gorm.DB) CategoryRepositoryI {
 }
 
 // CreateCategory implements CategoryRepositoryI.
-func (c CategoryRepository) CreateCategory(ctx context.Context, trx *gorm.DB, input category.CategoryInput) (err error) {
+func (c CategoryRepository) CreateCategory(ctx context.Context, trx *gorm.DB, input category.CategoryInput) (id int64, err error) {
 	if trx == nil {
 		trx = c.conn.WithContext(ctx)
 	}
@@ -39,10 +40,10 @@ func (c CategoryRepository) CreateCategory(ctx context.Context, trx *gorm.DB, in
 
 	sql := trx.Table(_db.CategoryTableName).Create(&input)
 	if sql.Error != nil {
-		return sql.Error
+		return id, sql.Error
 	}
 
-	return nil
+	return input.ID, nil
 }
 
 // DeleteCategory implements CategoryRepositoryI.
@@ -60,43 +61,58 @@ func (c CategoryRepository) DeleteCategory(ctx context.Context, trx *gorm.DB, id
 }
 
 // GetAllCategories implements CategoryRepositoryI.
-func (c CategoryRepository) GetAllCategories(ctx context.Context, name string) (resp []category.CategoryResponse, err error) {
+// With a similarity threshold the name matches by trigram similarity, best match first;
+// otherwise it matches as a substring.
+func (c CategoryRepository) GetAllCategories(ctx context.Context, name string, similarity float64) (resp []category.CategoryResponse, err error) {
 	query := `SELECT id, name, description, created_at, updated_at FROM tb_category`
 
 	params := []interface{}{}
-	if name != "" {
-		query += ` WHERE lower(name) ilike ?`
+	switch {
+	case name != "" && similarity > 0:
+		condition, matchParams := trigramMatch(c.conn, "name", name, similarity)
+		query += ` WHERE ` + condition + ` ORDER BY similarity(name, ?) DESC, id ASC`
+		params = append(append(params, matchParams...), name)
+	case name != "":
+		query += ` WHERE lower(name) ` + ilike(c.conn) + ` ? ORDER BY id ASC`
 		params = append(params, fmt.Sprintf("%%%s%%", strings.ToLower(name)))
+	default:
+		query += ` ORDER BY id ASC`
 	}
 
-	query += ` ORDER BY id ASC`
-
-	sql := c.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
-	if sql.Error != nil {
-		return resp, sql.Error
-	}
+	err = withSimilarityThreshold(ctx, c.conn, similarity, func(db *gorm.DB) error {
+		return db.Raw(query, params...).Scan(&resp).Error
+	})
 
 	return resp, err
 }
 
 // GetCategoryById implements CategoryRepositoryI.
-func (c CategoryRepository) GetCategoryById(ctx context.Context, id int64, name string) (resp category.CategoryResponse, err error) {
+func (c CategoryRepository) GetCategoryById(ctx context.Context, trx *gorm.DB, id int64, name string) (resp category.CategoryResponse, err error) {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
 	params := []interface{}{}
+	where := []string{}
 	query := `SELECT id, name, description, created_at, updated_at FROM ` + _db.CategoryTableName
 
 	if id != 0 {
-		query += ` WHERE id = ?`
+		where = append(where, `id = ?`)
 		params = append(params, id)
 	}
 
 	if name != "" {
-		query += ` WHERE lower(name) = ?`
+		where = append(where, `lower(name) = ?`)
 		params = append(params, strings.ToLower(name))
 	}
 
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+
 	query += ` LIMIT 1`
 
-	sql := c.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
+	sql := trx.Raw(query, params...).Scan(&resp)
 	if sql.Error != nil {
 		return resp, sql.Error
 	}
@@ -105,7 +121,8 @@ func (c CategoryRepository) GetCategoryById(ctx context.Context, id int64, name
 }
 
 // UpdateCategory implements CategoryRepositoryI.
-func (c CategoryRepository) UpdateCategory(ctx context.Context, trx *gorm.DB, id int64, input category.CategoryInput) (err error) {
+// When columns are given only those are written, which is how patches leave the rest alone.
+func (c CategoryRepository) UpdateCategory(ctx context.Context, trx *gorm.DB, id int64, input category.CategoryInput, columns ...string) (err error) {
 	if trx == nil {
 		trx = c.conn.WithContext(ctx)
 	}
@@ -117,6 +134,8 @@ func (c CategoryRepository) UpdateCategory(ctx context.Context, trx *gorm.DB, id
 		"updated_at":  &now,
 	}
 
+	updateCategory = onlyColumns(updateCategory, columns)
+
 	sql := trx.Table(_db.CategoryTableName).Where("id = ?", id).Updates(updateCategory)
 	if sql.Error != nil {
 		return sql.Error
@@ -124,3 +143,15 @@ func (c CategoryRepository) UpdateCategory(ctx context.Context, trx *gorm.DB, id
 
 	return err
 }
+
+// GetCategoriesByIDs implements CategoryRepositoryI.
+func (c CategoryRepository) GetCategoriesByIDs(ctx context.Context, ids []int64) (resp []category.CategoryResponse, err error) {
+	query := `SELECT id, name, description, created_at, updated_at FROM ` + _db.CategoryTableName + ` WHERE id IN ? ORDER BY id ASC`
+
+	sql := c.conn.WithContext(ctx).Raw(query, ids).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	_db "github.com/book-library/app/helper"
+	"github.com/book-library/entity/collection"
+	"gorm.io/gorm"
+)
+
+type CollectionRepositoryI interface {
+	CreateCollection(ctx context.Context, trx *gorm.DB, input collection.CollectionInput) (err error)
+	GetAllCollections(ctx context.Context, search collection.CollectionSearch) (resp []collection.CollectionResponse, err error)
+	GetCollectionById(ctx context.Context, id int64) (resp collection.CollectionResponse, err error)
+	UpdateCollection(ctx context.Context, trx *gorm.DB, id int64, input collection.CollectionInput) (err error)
+	DeleteCollection(ctx context.Context, trx *gorm.DB, id int64) error
+
+	GetCollectionEntries(ctx context.Context, id int64) (resp []collection.CollectionEntryResponse, err error)
+	GetCollectionEntry(ctx context.Context, trx *gorm.DB, id, bookID int64) (resp collection.CollectionEntryResponse, err error)
+	GetCollectionLastPosition(ctx context.Context, trx *gorm.DB, id int64) (position int, err error)
+	ShiftCollectionEntries(ctx context.Context, trx *gorm.DB, id int64, fromPosition, delta int) (err error)
+	CreateCollectionEntry(ctx context.Context, trx *gorm.DB, input collection.CollectionEntryInput) (err error)
+	UpdateCollectionEntry(ctx context.Context, trx *gorm.DB, input collection.CollectionEntryInput) (err error)
+	DeleteCollectionEntry(ctx context.Context, trx *gorm.DB, id, bookID int64) error
+}
+
+type CollectionRepository struct {
+	conn *gorm.DB
+}
+
+func NewCollectionRepository(conn *gorm.DB) CollectionRepositoryI {
+	return CollectionRepository{conn: conn}
+}
+
+// CreateCollection implements CollectionRepositoryI.
+func (c CollectionRepository) CreateCollection(ctx context.Context, trx *gorm.DB, input collection.CollectionInput) (err error) {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
+	now := time.Now()
+
+	input.CreatedAt = now
+	input.UpdatedAt = nil
+
+	sql := trx.Table(_db.CollectionTableName).Create(&input)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// DeleteCollection implements CollectionRepositoryI.
+// Entries are removed by the ON DELETE CASCADE of tb_collection_book.
+func (c CollectionRepository) DeleteCollection(ctx context.Context, trx *gorm.DB, id int64) error {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
+	sql := trx.Table(_db.CollectionTableName).Where("id = ?", id).Delete(&collection.CollectionInput{})
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// GetAllCollections implements CollectionRepositoryI.
+func (c CollectionRepository) GetAllCollections(ctx context.Context, search collection.CollectionSearch) (resp []collection.CollectionResponse, err error) {
+	query := `
+		SELECT
+			tbcl.id, tbcl.name, tbcl.description, coalesce(tbcl.owner_id, 0) as owner_id, coalesce(tbm.name, '') as owner_name,
+			tbcl.visibility, tbcl.featured_flag, tbcl.created_at, tbcl.updated_at,
+			(SELECT count(*) FROM tb_collection_book tbcb WHERE tbcb.collection_id = tbcl.id) as book_count
+		FROM
+			tb_collection tbcl
+		LEFT JOIN
+			tb_member tbm on tbcl.owner_id = tbm.id
+		WHERE
+			1 = 1
+	`
+
+	params := []interface{}{}
+	if search.Name != "" {
+		query += ` AND lower(tbcl.name) ` + ilike(c.conn) + ` ?`
+		params = append(params, fmt.Sprintf("%%%s%%", strings.ToLower(search.Name)))
+	}
+
+	if search.OwnerID != 0 {
+		query += ` AND tbcl.owner_id = ?`
+		params = append(params, search.OwnerID)
+	}
+
+	if search.Visibility != "" {
+		query += ` AND tbcl.visibility = ?`
+		params = append(params, search.Visibility)
+	}
+
+	if search.FeaturedOnly {
+		query += ` AND tbcl.featured_flag = true`
+	}
+
+	query += ` ORDER BY tbcl.id ASC`
+
+	sql := c.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// GetCollectionById implements CollectionRepositoryI.
+func (c CollectionRepository) GetCollectionById(ctx context.Context, id int64) (resp collection.CollectionResponse, err error) {
+	query := `
+		SELECT
+			tbcl.id, tbcl.name, tbcl.description, coalesce(tbcl.owner_id, 0) as owner_id, coalesce(tbm.name, '') as owner_name,
+			tbcl.visibility, tbcl.featured_flag, tbcl.created_at, tbcl.updated_at
+		FROM
+			tb_collection tbcl
+		LEFT JOIN
+			tb_member tbm on tbcl.owner_id = tbm.id
+		WHERE
+			tbcl.id = ?
+		LIMIT 1
+	`
+
+	sql := c.conn.WithContext(ctx).Raw(query, id).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// UpdateCollection implements CollectionRepositoryI.
+func (c CollectionRepository) UpdateCollection(ctx context.Context, trx *gorm.DB, id int64, input collection.CollectionInput) (err error) {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
+	now := time.Now()
+	updateCollection := map[string]interface{}{
+		"name":          input.Name,
+		"description":   input.Description,
+		"owner_id":      input.OwnerID,
+		"visibility":    input.Visibility,
+		"featured_flag": input.FeaturedFlag,
+		"updated_at":    &now,
+	}
+
+	sql := trx.Table(_db.CollectionTableName).Where("id = ?", id).Updates(updateCollection)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// GetCollectionEntries implements CollectionRepositoryI.
+func (c CollectionRepository) GetCollectionEntries(ctx context.Context, id int64) (resp []collection.CollectionEntryResponse, err error) {
+	query := `
+		SELECT
+			tbcb.book_id, tbb.title, coalesce(tba.name, '') as author_name, tbcb.position, tbcb.note, tbcb.created_at
+		FROM
+			tb_collection_book tbcb
+		JOIN
+			tb_book tbb on tbcb.book_id = tbb.id
+		LEFT JOIN
+			tb_author tba on tbb.author_id = tba.id
+		WHERE
+			tbcb.collection_id = ?
+		ORDER BY
+			tbcb.position ASC, tbcb.book_id ASC
+	`
+
+	sql := c.conn.WithContext(ctx).Raw(query, id).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// GetCollectionEntry implements CollectionRepositoryI.
+func (c CollectionRepository) GetCollectionEntry(ctx context.Context, trx *gorm.DB, id, bookID int64) (resp collection.CollectionEntryResponse, err error) {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
+	query := `SELECT book_id, position, note, created_at FROM ` + _db.CollectionBookTableName + ` WHERE collection_id = ? AND book_id = ? LIMIT 1`
+
+	sql := trx.Raw(query, id, bookID).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// GetCollectionLastPosition implements CollectionRepositoryI.
+// The collection row is locked so concurrent writers see a stable ordering.
+func (c CollectionRepository) GetCollectionLastPosition(ctx context.Context, trx *gorm.DB, id int64) (position int, err error) {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
+	if err = lockForUpdate(trx, _db.CollectionTableName, id); err != nil {
+		return position, err
+	}
+
+	sql := trx.Raw(`SELECT coalesce(max(position), 0) FROM `+_db.CollectionBookTableName+` WHERE collection_id = ?`, id).Scan(&position)
+	if sql.Error != nil {
+		return position, sql.Error
+	}
+
+	return position, err
+}
+
+// ShiftCollectionEntries implements CollectionRepositoryI.
+// Every entry at fromPosition or later moves by delta.
+func (c CollectionRepository) ShiftCollectionEntries(ctx context.Context, trx *gorm.DB, id int64, fromPosition, delta int) (err error) {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
+	sql := trx.Table(_db.CollectionBookTableName).
+		Where("collection_id = ? AND position >= ?", id, fromPosition).
+		Update("position", gorm.Expr("position + ?", delta))
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// CreateCollectionEntry implements CollectionRepositoryI.
+func (c CollectionRepository) CreateCollectionEntry(ctx context.Context, trx *gorm.DB, input collection.CollectionEntryInput) (err error) {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
+	input.CreatedAt = time.Now()
+
+	sql := trx.Table(_db.CollectionBookTableName).Create(&input)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// UpdateCollectionEntry implements CollectionRepositoryI.
+func (c CollectionRepository) UpdateCollectionEntry(ctx context.Context, trx *gorm.DB, input collection.CollectionEntryInput) (err error) {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
+	updateEntry := map[string]interface{}{
+		"position": input.Position,
+		"note":     input.Note,
+	}
+
+	sql := trx.Table(_db.CollectionBookTableName).
+		Where("collection_id = ? AND book_id = ?", input.CollectionID, input.BookID).
+		Updates(updateEntry)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// DeleteCollectionEntry implements CollectionRepositoryI.
+func (c CollectionRepository) DeleteCollectionEntry(ctx context.Context, trx *gorm.DB, id, bookID int64) error {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
+	sql := trx.Table(_db.CollectionBookTableName).Where("collection_id = ? AND book_id = ?", id, bookID).Delete(&collection.CollectionEntryInput{})
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+// onlyColumns narrows an update to columns, keeping updated_at. Without columns every value
+// is written.
+func onlyColumns(values map[string]interface{}, columns []string) map[string]interface{} {
+	if len(columns) == 0 {
+		return values
+	}
+
+	narrowed := map[string]interface{}{"updated_at": values["updated_at"]}
+	for _, column := range columns {
+		if value, ok := values[column]; ok {
+			narrowed[column] = value
+		}
+	}
+
+	return narrowed
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// The repositories run on Postgres or SQLite. The raw SQL is written once and the few places
+// where the engines disagree go through the helpers below.
+
+const (
+	// DriverSQLite is the DRIVER_NAME that keeps the data in a SQLite file. It is also the name
+	// gorm gives the dialect.
+	DriverSQLite = "sqlite"
+
+	// SQLiteDriverName is the database/sql driver to open SQLite with. It is go-sqlite3 with the
+	// pg_trgm similarity() function added, so trigram search works on both engines.
+	SQLiteDriverName = "sqlite3_book_library"
+)
+
+func init() {
+	sql.Register(SQLiteDriverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("similarity", sqliteSimilarity, true)
+		},
+	})
+}
+
+// sqliteSimilarity is similarity() for SQLite. NULL scores 0, so like in Postgres it never
+// matches.
+func sqliteSimilarity(a, b interface{}) float64 {
+	left, _ := a.(string)
+	right, _ := b.(string)
+
+	return trigramSimilarity(left, right)
+}
+
+func isSQLite(db *gorm.DB) bool {
+	return db.Dialector.Name() == DriverSQLite
+}
+
+// ilike is the case-insensitive LIKE of db. SQLite's LIKE already ignores case.
+func ilike(db *gorm.DB) string {
+	if isSQLite(db) {
+		return "LIKE"
+	}
+
+	return "ILIKE"
+}
+
+// likeEscape is the clause that makes the backslashes of escapeLike escape on db. Postgres
+// escapes with a backslash by default, SQLite has no escape character unless told.
+func likeEscape(db *gorm.DB) string {
+	if isSQLite(db) {
+		return ` ESCAPE '\'`
+	}
+
+	return ""
+}
+
+// greatest is the function returning the largest of its arguments on db.
+func greatest(db *gorm.DB) string {
+	if isSQLite(db) {
+		return "max"
+	}
+
+	return "greatest"
+}
+
+// trigramMatch is the condition that column is similar to a value at threshold, taking the
+// value as its parameters. On Postgres it is the indexed % operator, with the threshold set by
+// withSimilarityThreshold; SQLite has no operator to set, so similarity() is compared instead.
+func trigramMatch(db *gorm.DB, column string, value string, threshold float64) (condition string, params []interface{}) {
+	if isSQLite(db) {
+		return "similarity(" + column + ", ?) >= ?", []interface{}{value, threshold}
+	}
+
+	return column + " % ?", []interface{}{value}
+}
+
+// lockForUpdate locks the row with id of table until trx ends. SQLite transactions take the
+// database write lock when they begin, see the _txlock of the connection, so there is no row to
+// lock.
+func lockForUpdate(trx *gorm.DB, table string, id int64) error {
+	if isSQLite(trx) {
+		return nil
+	}
+
+	return trx.Exec(`SELECT id FROM `+table+` WHERE id = ? FOR UPDATE`, id).Error
+}
+
+// skipLocked is the clause ending a SELECT that claims rows in trx, skipping the rows another
+// transaction has claimed. SQLite needs none: the write lock taken at BEGIN already keeps other
+// claims out until trx ends.
+func skipLocked(trx *gorm.DB) string {
+	if isSQLite(trx) {
+		return ""
+	}
+
+	return " FOR UPDATE SKIP LOCKED"
+}
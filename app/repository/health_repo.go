@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/book-library/migration"
+	"gorm.io/gorm"
+)
+
+type HealthRepositoryI interface {
+	// Dialect names the database behind the repository, which is the name of its readiness check.
+	Dialect() string
+	Ping(ctx context.Context) (err error)
+	GetPendingMigrations(ctx context.Context) (versions []string, err error)
+}
+
+type HealthRepository struct {
+	conn *gorm.DB
+}
+
+func NewHealthRepository(conn *gorm.DB) HealthRepositoryI {
+	return HealthRepository{conn: conn}
+}
+
+// Dialect implements HealthRepositoryI.
+func (h HealthRepository) Dialect() string {
+	return h.conn.Dialector.Name()
+}
+
+// Ping implements HealthRepositoryI.
+func (h HealthRepository) Ping(ctx context.Context) (err error) {
+	db, err := h.conn.DB()
+	if err != nil {
+		return err
+	}
+
+	return db.PingContext(ctx)
+}
+
+// GetPendingMigrations implements HealthRepositoryI.
+func (h HealthRepository) GetPendingMigrations(ctx context.Context) (versions []string, err error) {
+	return migration.Pending(h.conn.WithContext(ctx))
+}
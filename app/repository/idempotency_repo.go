@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	_db "github.com/book-library/app/helper"
+	"github.com/book-library/entity/idempotency"
+	"gorm.io/gorm"
+)
+
+type IdempotencyRepositoryI interface {
+	CreateIdempotencyKey(ctx context.Context, input idempotency.IdempotencyInput) (created bool, err error)
+	GetIdempotencyKey(ctx context.Context, scope, key string) (resp idempotency.IdempotencyRecord, err error)
+	ExtendIdempotencyKey(ctx context.Context, scope, key, owner string, lockedUntil time.Time) (err error)
+	CompleteIdempotencyKey(ctx context.Context, scope, key, owner string, input idempotency.StoredResponse) (err error)
+	DeleteIdempotencyKey(ctx context.Context, scope, key, owner string) (err error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (deleted int64, err error)
+}
+
+type IdempotencyRepository struct {
+	conn *gorm.DB
+}
+
+func NewIdempotencyRepository(conn *gorm.DB) IdempotencyRepositoryI {
+	return IdempotencyRepository{conn: conn}
+}
+
+// CreateIdempotencyKey implements IdempotencyRepositoryI.
+// It takes the key in its scope unless another request holds it; an expired record, or one
+// whose request stopped before completing and let its lease run out, is replaced.
+// The primary key makes this safe across instances.
+func (i IdempotencyRepository) CreateIdempotencyKey(ctx context.Context, input idempotency.IdempotencyInput) (created bool, err error) {
+	err = i.conn.WithContext(ctx).Transaction(func(trx *gorm.DB) error {
+		now := time.Now()
+		query := `
+			DELETE FROM ` + _db.IdempotencyTableName + `
+			WHERE scope = ? AND idempotency_key = ? AND (expires_at < ? OR (completed_flag = ? AND locked_until < ?))
+		`
+
+		sql := trx.Exec(query, input.Scope, input.IdempotencyKey, now, false, now)
+		if sql.Error != nil {
+			return sql.Error
+		}
+
+		query = `
+			INSERT INTO ` + _db.IdempotencyTableName + ` (scope, idempotency_key, owner, request_hash, created_at, locked_until, expires_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (scope, idempotency_key) DO NOTHING
+		`
+
+		sql = trx.Exec(query, input.Scope, input.IdempotencyKey, input.Owner, input.RequestHash, now, input.LockedUntil, input.ExpiresAt)
+		if sql.Error != nil {
+			return sql.Error
+		}
+
+		created = sql.RowsAffected == 1
+		return nil
+	})
+
+	return created, err
+}
+
+// GetIdempotencyKey implements IdempotencyRepositoryI.
+func (i IdempotencyRepository) GetIdempotencyKey(ctx context.Context, scope, key string) (resp idempotency.IdempotencyRecord, err error) {
+	query := `
+		SELECT
+			scope, idempotency_key, owner, request_hash, status_code, content_type, response_body, completed_flag, created_at, locked_until, expires_at
+		FROM
+			` + _db.IdempotencyTableName + `
+		WHERE
+			scope = ? AND idempotency_key = ?
+		LIMIT 1
+	`
+
+	sql := i.conn.WithContext(ctx).Raw(query, scope, key).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// ExtendIdempotencyKey implements IdempotencyRepositoryI.
+// It moves the lease of a key still held by owner to lockedUntil.
+func (i IdempotencyRepository) ExtendIdempotencyKey(ctx context.Context, scope, key, owner string, lockedUntil time.Time) (err error) {
+	sql := i.conn.WithContext(ctx).Table(_db.IdempotencyTableName).
+		Where("scope = ? AND idempotency_key = ? AND owner = ? AND completed_flag = ?", scope, key, owner, false).
+		Update("locked_until", lockedUntil)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// CompleteIdempotencyKey implements IdempotencyRepositoryI.
+// A key taken over by another request after the lease ran out is left to that request.
+func (i IdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, scope, key, owner string, input idempotency.StoredResponse) (err error) {
+	updateKey := map[string]interface{}{
+		"status_code":    input.StatusCode,
+		"content_type":   input.ContentType,
+		"response_body":  input.ResponseBody,
+		"completed_flag": true,
+	}
+
+	sql := i.conn.WithContext(ctx).Table(_db.IdempotencyTableName).Where("scope = ? AND idempotency_key = ? AND owner = ?", scope, key, owner).Updates(updateKey)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// DeleteIdempotencyKey implements IdempotencyRepositoryI.
+// A key taken over by another request after the lease ran out is left to that request.
+func (i IdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, scope, key, owner string) (err error) {
+	sql := i.conn.WithContext(ctx).Exec(`DELETE FROM `+_db.IdempotencyTableName+` WHERE scope = ? AND idempotency_key = ? AND owner = ?`, scope, key, owner)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// DeleteExpiredIdempotencyKeys implements IdempotencyRepositoryI.
+func (i IdempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context) (deleted int64, err error) {
+	now := time.Now()
+	query := `DELETE FROM ` + _db.IdempotencyTableName + ` WHERE expires_at < ? OR (completed_flag = ? AND locked_until < ?)`
+
+	sql := i.conn.WithContext(ctx).Exec(query, now, false, now)
+	if sql.Error != nil {
+		return deleted, sql.Error
+	}
+
+	return sql.RowsAffected, err
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	_db "github.com/book-library/app/helper"
+	"github.com/book-library/entity/member"
+	"gorm.io/gorm"
+)
+
+type MemberRepositoryI interface {
+	CreateMember(ctx context.Context, trx *gorm.DB, input member.MemberInput) (err error)
+	GetAllMembers(ctx context.Context, name string) (resp []member.MemberResponse, err error)
+	GetMemberById(ctx context.Context, id int64, email string) (resp member.MemberResponse, err error)
+	UpdateMember(ctx context.Context, trx *gorm.DB, id int64, input member.MemberInput) (err error)
+	DeleteMember(ctx context.Context, trx *gorm.DB, id int64) error
+}
+
+type MemberRepository struct {
+	conn *gorm.DB
+}
+
+func NewMemberRepository(conn *gorm.DB) MemberRepositoryI {
+	return MemberRepository{conn: conn}
+}
+
+// CreateMember implements MemberRepositoryI.
+func (m MemberRepository) CreateMember(ctx context.Context, trx *gorm.DB, input member.MemberInput) (err error) {
+	if trx == nil {
+		trx = m.conn.WithContext(ctx)
+	}
+
+	now := time.Now()
+
+	input.CreatedAt = now
+	input.UpdatedAt = nil
+
+	sql := trx.Table(_db.MemberTableName).Create(&input)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// DeleteMember implements MemberRepositoryI.
+func (m MemberRepository) DeleteMember(ctx context.Context, trx *gorm.DB, id int64) error {
+	if trx == nil {
+		trx = m.conn.WithContext(ctx)
+	}
+
+	sql := trx.Table(_db.MemberTableName).Where("id = ?", id).Delete(&member.MemberInput{})
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// GetAllMembers implements MemberRepositoryI.
+func (m MemberRepository) GetAllMembers(ctx context.Context, name string) (resp []member.MemberResponse, err error) {
+	query := `SELECT id, name, email, created_at, updated_at FROM ` + _db.MemberTableName
+
+	params := []interface{}{}
+	if name != "" {
+		query += ` WHERE lower(name) ` + ilike(m.conn) + ` ?`
+		params = append(params, fmt.Sprintf("%%%s%%", strings.ToLower(name)))
+	}
+
+	query += ` ORDER BY id ASC`
+
+	sql := m.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// GetMemberById implements MemberRepositoryI.
+func (m MemberRepository) GetMemberById(ctx context.Context, id int64, email string) (resp member.MemberResponse, err error) {
+	params := []interface{}{}
+	where := []string{}
+	query := `SELECT id, name, email, created_at, updated_at FROM ` + _db.MemberTableName
+
+	if id != 0 {
+		where = append(where, `id = ?`)
+		params = append(params, id)
+	}
+
+	if email != "" {
+		where = append(where, `lower(email) = ?`)
+		params = append(params, email)
+	}
+
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+
+	query += ` LIMIT 1`
+
+	sql := m.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// UpdateMember implements MemberRepositoryI.
+func (m MemberRepository) UpdateMember(ctx context.Context, trx *gorm.DB, id int64, input member.MemberInput) (err error) {
+	if trx == nil {
+		trx = m.conn.WithContext(ctx)
+	}
+
+	now := time.Now()
+	updateMember := map[string]interface{}{
+		"name":       input.Name,
+		"email":      input.Email,
+		"updated_at": &now,
+	}
+
+	sql := trx.Table(_db.MemberTableName).Where("id = ?", id).Updates(updateMember)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/book-library/entity/author"
+	"gorm.io/gorm"
+)
+
+type MemoryAuthorRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryAuthorRepository(store *MemoryStore) AuthorRepositoryI {
+	return MemoryAuthorRepository{store: store}
+}
+
+// CreateAuthor implements AuthorRepositoryI.
+func (m MemoryAuthorRepository) CreateAuthor(ctx context.Context, trx *gorm.DB, input author.AuthorInput) (id int64, err error) {
+	err = m.store.write(trx, func() (undo func()) {
+		id, undo = m.store.authors.insert(func(id int64) author.AuthorResponse {
+			return author.AuthorResponse{ID: id, Name: input.Name, Email: input.Email, CreatedAt: time.Now()}
+		})
+		return undo
+	})
+
+	return id, err
+}
+
+// GetAllAuthors implements AuthorRepositoryI.
+func (m MemoryAuthorRepository) GetAllAuthors(ctx context.Context, name string, similarity float64) (resp []author.AuthorResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	return filterByName(m.store.authors.all(), name, similarity, func(a author.AuthorResponse) string { return a.Name }), nil
+}
+
+// GetAuthorsByIDs implements AuthorRepositoryI.
+func (m MemoryAuthorRepository) GetAuthorsByIDs(ctx context.Context, ids []int64) (resp []author.AuthorResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp = []author.AuthorResponse{}
+	for _, a := range m.store.authors.all() {
+		if containsID(ids, a.ID) {
+			resp = append(resp, a)
+		}
+	}
+
+	return resp, nil
+}
+
+// GetAuthorById implements AuthorRepositoryI.
+func (m MemoryAuthorRepository) GetAuthorById(ctx context.Context, trx *gorm.DB, id int64, email string) (resp author.AuthorResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp, _ = m.store.authors.find(func(a author.AuthorResponse) bool {
+		return (id == 0 || a.ID == id) && (email == "" || strings.EqualFold(a.Email, email))
+	})
+
+	return resp, nil
+}
+
+// UpdateAuthor implements AuthorRepositoryI.
+func (m MemoryAuthorRepository) UpdateAuthor(ctx context.Context, trx *gorm.DB, id int64, input author.AuthorInput, columns ...string) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.authors.update(id, func(a *author.AuthorResponse) {
+			now := time.Now()
+			a.UpdatedAt = &now
+
+			if writesColumn(columns, "name") {
+				a.Name = input.Name
+			}
+
+			if writesColumn(columns, "email") {
+				a.Email = input.Email
+			}
+		})
+	})
+}
+
+// DeleteAuthor implements AuthorRepositoryI.
+func (m MemoryAuthorRepository) DeleteAuthor(ctx context.Context, trx *gorm.DB, id int64) error {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.authors.delete(id)
+	})
+}
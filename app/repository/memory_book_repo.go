@@ -0,0 +1,389 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/book-library/entity/book"
+	"github.com/book-library/entity/collection"
+	"github.com/book-library/entity/review"
+	"gorm.io/gorm"
+)
+
+type MemoryBookLibraryRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryBookLibraryRepository(store *MemoryStore) BookLibraryRepositoryI {
+	return MemoryBookLibraryRepository{store: store}
+}
+
+// CreateBookLibrary implements BookLibraryRepositoryI.
+func (m MemoryBookLibraryRepository) CreateBookLibrary(ctx context.Context, trx *gorm.DB, input book.BookInput) (id int64, err error) {
+	err = m.store.write(trx, func() (undo func()) {
+		id, undo = m.store.books.insert(func(id int64) book.BookResponse {
+			row := book.BookResponse{ID: id, CreatedAt: time.Now()}
+			setBookColumns(&row, input, nil)
+			return row
+		})
+		return undo
+	})
+
+	return id, err
+}
+
+// setBookColumns copies the columns of input to row, all of them when columns is empty.
+// Unset optional references are stored as 0, the way the SQL queries coalesce NULL.
+func setBookColumns(row *book.BookResponse, input book.BookInput, columns []string) {
+	if writesColumn(columns, "title") {
+		row.Title = input.Title
+	}
+
+	if writesColumn(columns, "isbn") {
+		row.ISBN = input.ISBN
+	}
+
+	if writesColumn(columns, "description") {
+		row.BoookDescription = input.Description
+	}
+
+	if writesColumn(columns, "published_flag") {
+		row.PublishedFlag = input.PublishedFlag != nil && *input.PublishedFlag
+	}
+
+	if writesColumn(columns, "author_id") {
+		row.AuthorID = input.AuthorID
+	}
+
+	if writesColumn(columns, "category_id") {
+		row.CategoryID = input.CategoryID
+	}
+
+	if writesColumn(columns, "publisher_id") {
+		row.PublisherID = valueOrZero(input.PublisherID)
+	}
+
+	if writesColumn(columns, "series_id") {
+		row.SeriesID = valueOrZero(input.SeriesID)
+	}
+
+	if writesColumn(columns, "series_volume") {
+		row.SeriesVolume = input.SeriesVolume
+	}
+
+	if writesColumn(columns, "work_id") {
+		row.WorkID = valueOrZero(input.WorkID)
+	}
+
+	if writesColumn(columns, "publication_year") {
+		row.PublicationYear = input.PublicationYear
+	}
+
+	if writesColumn(columns, "edition") {
+		row.Edition = input.Edition
+	}
+
+	if writesColumn(columns, "page_count") {
+		row.PageCount = input.PageCount
+	}
+
+	if writesColumn(columns, "language") {
+		row.Language = input.Language
+	}
+}
+
+func valueOrZero(value *int64) int64 {
+	if value == nil {
+		return 0
+	}
+
+	return *value
+}
+
+// joinBook fills the names the SQL queries join in. It must be called with the store locked.
+func (m MemoryBookLibraryRepository) joinBook(row book.BookResponse) book.BookResponse {
+	if a, ok := m.store.authors.get(row.AuthorID); ok {
+		row.AuthorName, row.AuthorEmail = a.Name, a.Email
+	}
+
+	if c, ok := m.store.categories.get(row.CategoryID); ok {
+		row.CategoryName, row.CategoryDescription = c.Name, c.Description
+	}
+
+	if p, ok := m.store.publishers.get(row.PublisherID); ok {
+		row.PublisherName = p.Name
+	}
+
+	if s, ok := m.store.series.get(row.SeriesID); ok {
+		row.SeriesName = s.Name
+	}
+
+	if w, ok := m.store.works.get(row.WorkID); ok {
+		row.WorkTitle = w.Title
+	}
+
+	return row
+}
+
+// searchBooks returns the joined books matching search in id order. It must be called with
+// the store locked.
+func (m MemoryBookLibraryRepository) searchBooks(search book.BookSearch) (resp []book.BookResponse) {
+	resp = []book.BookResponse{}
+	for _, row := range m.store.books.all() {
+		row = m.joinBook(row)
+		if bookMatches(row, search) {
+			resp = append(resp, row)
+		}
+	}
+
+	return resp
+}
+
+// bookMatches is bookFilters for a joined row.
+func bookMatches(row book.BookResponse, search book.BookSearch) bool {
+	switch search.Published {
+	case book.PublishedAny:
+	case book.PublishedFalse:
+		if row.PublishedFlag {
+			return false
+		}
+	default:
+		if !row.PublishedFlag {
+			return false
+		}
+	}
+
+	switch {
+	case search.AuthorID != 0 && row.AuthorID != search.AuthorID,
+		search.CategoryID != 0 && row.CategoryID != search.CategoryID,
+		search.PublisherID != 0 && row.PublisherID != search.PublisherID,
+		search.PublicationYearFrom != 0 && row.PublicationYear < search.PublicationYearFrom,
+		search.PublicationYearTo != 0 && row.PublicationYear > search.PublicationYearTo,
+		search.ISBNPrefix != "" && !strings.HasPrefix(row.ISBN, search.ISBNPrefix),
+		search.TitleContains != "" && !containsFold(row.Title, search.TitleContains),
+		search.CreatedFrom != nil && row.CreatedAt.Before(*search.CreatedFrom),
+		search.CreatedTo != nil && !row.CreatedAt.Before(*search.CreatedTo),
+		search.UpdatedFrom != nil && (row.UpdatedAt == nil || row.UpdatedAt.Before(*search.UpdatedFrom)),
+		search.UpdatedTo != nil && (row.UpdatedAt == nil || !row.UpdatedAt.Before(*search.UpdatedTo)):
+		return false
+	}
+
+	switch similarity := bookSimilarity(search); {
+	case similarity > 0:
+		return row.ISBN == search.Search ||
+			trigramSimilarity(row.Title, search.Search) >= similarity ||
+			trigramSimilarity(row.AuthorName, search.Search) >= similarity
+	case search.Search != "":
+		return row.ISBN == search.Search || row.Title == search.Search || row.AuthorName == search.Search
+	}
+
+	return true
+}
+
+// GetAllBookLibraries implements BookLibraryRepositoryI.
+func (m MemoryBookLibraryRepository) GetAllBookLibraries(ctx context.Context, search book.BookSearch) (resp []book.BookResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp = m.searchBooks(search)
+
+	switch {
+	case search.Sort == book.SortByRating:
+		sort.SliceStable(resp, func(i, j int) bool {
+			if resp[i].RatingAverage != resp[j].RatingAverage {
+				return resp[i].RatingAverage > resp[j].RatingAverage
+			}
+
+			return resp[i].RatingCount > resp[j].RatingCount
+		})
+	case bookSimilarity(search) > 0:
+		score := func(row book.BookResponse) float64 {
+			return max(trigramSimilarity(row.Title, search.Search), trigramSimilarity(row.AuthorName, search.Search))
+		}
+		sort.SliceStable(resp, func(i, j int) bool { return score(resp[i]) > score(resp[j]) })
+	}
+
+	return resp, nil
+}
+
+// GetBookFacets implements BookLibraryRepositoryI.
+func (m MemoryBookLibraryRepository) GetBookFacets(ctx context.Context, search book.BookSearch) (resp book.BookFacets, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	byCategory := search
+	byCategory.CategoryID = 0
+	resp.Category = countBookFacet(m.searchBooks(byCategory), func(row book.BookResponse) (int64, string) {
+		return row.CategoryID, row.CategoryName
+	})
+
+	byAuthor := search
+	byAuthor.AuthorID = 0
+	resp.Author = countBookFacet(m.searchBooks(byAuthor), func(row book.BookResponse) (int64, string) {
+		return row.AuthorID, row.AuthorName
+	})
+
+	byYear := search
+	byYear.PublicationYearFrom, byYear.PublicationYearTo = 0, 0
+	years := countBookFacet(m.searchBooks(byYear), func(row book.BookResponse) (int64, string) {
+		bucket := row.PublicationYear / book.PublicationYearBucket * book.PublicationYearBucket
+		if bucket == 0 {
+			return 0, "unknown"
+		}
+
+		return int64(bucket), strconv.Itoa(bucket) + "-" + strconv.Itoa(bucket+book.PublicationYearBucket-1)
+	})
+	sort.SliceStable(years, func(i, j int) bool {
+		left, _ := strconv.Atoi(years[i].Value)
+		right, _ := strconv.Atoi(years[j].Value)
+		return left > right
+	})
+	resp.PublicationYear = years
+
+	byAvailability := search
+	byAvailability.Published = book.PublishedAny
+	availability := countBookFacet(m.searchBooks(byAvailability), func(row book.BookResponse) (int64, string) {
+		if row.PublishedFlag {
+			return 1, "published"
+		}
+
+		return 0, "unpublished"
+	})
+	for i := range availability {
+		availability[i].Value = book.PublishedFalse
+		if availability[i].Label == "published" {
+			availability[i].Value = book.PublishedTrue
+		}
+	}
+	sort.SliceStable(availability, func(i, j int) bool { return availability[i].Value == book.PublishedTrue })
+	resp.Availability = availability
+
+	return resp, nil
+}
+
+// countBookFacet groups rows by the key of group, most frequent first and then by key.
+func countBookFacet(rows []book.BookResponse, group func(book.BookResponse) (key int64, label string)) []book.BookFacetBucket {
+	counts := map[int64]*book.BookFacetBucket{}
+	keys := []int64{}
+	for _, row := range rows {
+		key, label := group(row)
+		if _, ok := counts[key]; !ok {
+			counts[key] = &book.BookFacetBucket{Value: strconv.FormatInt(key, 10), Label: label}
+			keys = append(keys, key)
+		}
+		counts[key].Count++
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]].Count != counts[keys[j]].Count {
+			return counts[keys[i]].Count > counts[keys[j]].Count
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	buckets := []book.BookFacetBucket{}
+	for _, key := range keys {
+		buckets = append(buckets, *counts[key])
+	}
+
+	return buckets
+}
+
+// GetBookLibraryById implements BookLibraryRepositoryI.
+func (m MemoryBookLibraryRepository) GetBookLibraryById(ctx context.Context, trx *gorm.DB, id, authorID, categoryID, publisherID int64) (resp book.BookResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp, _ = m.store.books.find(func(row book.BookResponse) bool {
+		return (id == 0 || row.ID == id) &&
+			(authorID == 0 || row.AuthorID == authorID) &&
+			(categoryID == 0 || row.CategoryID == categoryID) &&
+			(publisherID == 0 || row.PublisherID == publisherID)
+	})
+
+	return resp, nil
+}
+
+// GetBookLibraryByISBN implements BookLibraryRepositoryI.
+func (m MemoryBookLibraryRepository) GetBookLibraryByISBN(ctx context.Context, trx *gorm.DB, isbn string) (resp book.BookResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp, _ = m.store.books.find(func(row book.BookResponse) bool { return row.ISBN == isbn })
+
+	return resp, nil
+}
+
+// UpdateBookLibrary implements BookLibraryRepositoryI.
+func (m MemoryBookLibraryRepository) UpdateBookLibrary(ctx context.Context, trx *gorm.DB, id int64, input book.BookInput, columns ...string) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.books.update(id, func(row *book.BookResponse) {
+			now := time.Now()
+			row.UpdatedAt = &now
+			setBookColumns(row, input, columns)
+		})
+	})
+}
+
+// DeleteBookLibrary implements BookLibraryRepositoryI.
+// Reviews and collection entries of the book go with it, like the cascades of the schema.
+func (m MemoryBookLibraryRepository) DeleteBookLibrary(ctx context.Context, trx *gorm.DB, id int64) error {
+	return m.store.write(trx, func() (undo func()) {
+		return undoAll([]func(){
+			m.store.reviews.deleteWhere(func(r review.ReviewResponse) bool { return r.BookID == id }),
+			m.store.collectionEntries.deleteWhere(func(e collection.CollectionEntryInput) bool { return e.BookID == id }),
+			m.store.books.delete(id),
+		})
+	})
+}
+
+// UpdateBookRating implements BookLibraryRepositoryI.
+func (m MemoryBookLibraryRepository) UpdateBookRating(ctx context.Context, trx *gorm.DB, id int64) error {
+	return m.store.write(trx, func() (undo func()) {
+		total, count := 0, 0
+		for _, r := range m.store.reviews.all() {
+			if r.BookID == id && r.Status == review.StatusApproved {
+				total += r.Rating
+				count++
+			}
+		}
+
+		return m.store.books.update(id, func(row *book.BookResponse) {
+			row.RatingAverage, row.RatingCount = 0, count
+			if count > 0 {
+				row.RatingAverage = float64(total) / float64(count)
+			}
+		})
+	})
+}
+
+// GetSimilarBookCandidates implements BookLibraryRepositoryI.
+func (m MemoryBookLibraryRepository) GetSimilarBookCandidates(ctx context.Context, target book.BookResponse) (resp []book.BookResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp = []book.BookResponse{}
+	for _, row := range m.store.books.all() {
+		related := row.AuthorID == target.AuthorID || row.CategoryID == target.CategoryID ||
+			(row.SeriesID != 0 && row.SeriesID == target.SeriesID)
+		sameWork := target.WorkID != 0 && row.WorkID == target.WorkID
+
+		if row.PublishedFlag && row.ID != target.ID && related && !sameWork {
+			resp = append(resp, m.joinBook(row))
+		}
+	}
+
+	return resp, nil
+}
+
+// CountBooks implements BookLibraryRepositoryI.
+func (m MemoryBookLibraryRepository) CountBooks(ctx context.Context) (total int64, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	return int64(len(m.store.books.rows)), nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/book-library/entity/category"
+	"gorm.io/gorm"
+)
+
+type MemoryCategoryRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryCategoryRepository(store *MemoryStore) CategoryRepositoryI {
+	return MemoryCategoryRepository{store: store}
+}
+
+// CreateCategory implements CategoryRepositoryI.
+func (m MemoryCategoryRepository) CreateCategory(ctx context.Context, trx *gorm.DB, input category.CategoryInput) (id int64, err error) {
+	err = m.store.write(trx, func() (undo func()) {
+		id, undo = m.store.categories.insert(func(id int64) category.CategoryResponse {
+			return category.CategoryResponse{ID: id, Name: input.Name, Description: input.Description, CreatedAt: time.Now()}
+		})
+		return undo
+	})
+
+	return id, err
+}
+
+// GetAllCategories implements CategoryRepositoryI.
+func (m MemoryCategoryRepository) GetAllCategories(ctx context.Context, name string, similarity float64) (resp []category.CategoryResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	return filterByName(m.store.categories.all(), name, similarity, func(c category.CategoryResponse) string { return c.Name }), nil
+}
+
+// GetCategoriesByIDs implements CategoryRepositoryI.
+func (m MemoryCategoryRepository) GetCategoriesByIDs(ctx context.Context, ids []int64) (resp []category.CategoryResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp = []category.CategoryResponse{}
+	for _, c := range m.store.categories.all() {
+		if containsID(ids, c.ID) {
+			resp = append(resp, c)
+		}
+	}
+
+	return resp, nil
+}
+
+// GetCategoryById implements CategoryRepositoryI.
+func (m MemoryCategoryRepository) GetCategoryById(ctx context.Context, trx *gorm.DB, id int64, name string) (resp category.CategoryResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp, _ = m.store.categories.find(func(c category.CategoryResponse) bool {
+		return (id == 0 || c.ID == id) && (name == "" || strings.EqualFold(c.Name, name))
+	})
+
+	return resp, nil
+}
+
+// UpdateCategory implements CategoryRepositoryI.
+func (m MemoryCategoryRepository) UpdateCategory(ctx context.Context, trx *gorm.DB, id int64, input category.CategoryInput, columns ...string) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.categories.update(id, func(c *category.CategoryResponse) {
+			now := time.Now()
+			c.UpdatedAt = &now
+
+			if writesColumn(columns, "name") {
+				c.Name = input.Name
+			}
+
+			if writesColumn(columns, "description") {
+				c.Description = input.Description
+			}
+		})
+	})
+}
+
+// DeleteCategory implements CategoryRepositoryI.
+func (m MemoryCategoryRepository) DeleteCategory(ctx context.Context, trx *gorm.DB, id int64) error {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.categories.delete(id)
+	})
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/book-library/entity/collection"
+	"gorm.io/gorm"
+)
+
+type MemoryCollectionRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryCollectionRepository(store *MemoryStore) CollectionRepositoryI {
+	return MemoryCollectionRepository{store: store}
+}
+
+// CreateCollection implements CollectionRepositoryI.
+func (m MemoryCollectionRepository) CreateCollection(ctx context.Context, trx *gorm.DB, input collection.CollectionInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		_, undo = m.store.collections.insert(func(id int64) collection.CollectionResponse {
+			row := collection.CollectionResponse{ID: id, CreatedAt: time.Now()}
+			setCollectionColumns(&row, input)
+			return row
+		})
+		return undo
+	})
+}
+
+func setCollectionColumns(row *collection.CollectionResponse, input collection.CollectionInput) {
+	row.Name, row.Description, row.Visibility = input.Name, input.Description, input.Visibility
+	row.OwnerID = valueOrZero(input.OwnerID)
+	row.FeaturedFlag = input.FeaturedFlag != nil && *input.FeaturedFlag
+}
+
+// DeleteCollection implements CollectionRepositoryI.
+// Entries are removed with the collection, like the ON DELETE CASCADE of tb_collection_book.
+func (m MemoryCollectionRepository) DeleteCollection(ctx context.Context, trx *gorm.DB, id int64) error {
+	return m.store.write(trx, func() (undo func()) {
+		return undoAll([]func(){
+			m.store.collectionEntries.deleteWhere(func(e collection.CollectionEntryInput) bool { return e.CollectionID == id }),
+			m.store.collections.delete(id),
+		})
+	})
+}
+
+// joinCollection fills the owner name. It must be called with the store locked.
+func (m MemoryCollectionRepository) joinCollection(row collection.CollectionResponse) collection.CollectionResponse {
+	if owner, ok := m.store.members.get(row.OwnerID); ok {
+		row.OwnerName = owner.Name
+	}
+
+	return row
+}
+
+// GetAllCollections implements CollectionRepositoryI.
+func (m MemoryCollectionRepository) GetAllCollections(ctx context.Context, search collection.CollectionSearch) (resp []collection.CollectionResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	counts := map[int64]int{}
+	for _, e := range m.store.collectionEntries.all() {
+		counts[e.CollectionID]++
+	}
+
+	resp = []collection.CollectionResponse{}
+	for _, row := range m.store.collections.all() {
+		switch {
+		case search.Name != "" && !containsFold(row.Name, search.Name),
+			search.OwnerID != 0 && row.OwnerID != search.OwnerID,
+			search.Visibility != "" && row.Visibility != search.Visibility,
+			search.FeaturedOnly && !row.FeaturedFlag:
+			continue
+		}
+
+		row = m.joinCollection(row)
+		row.BookCount = counts[row.ID]
+		resp = append(resp, row)
+	}
+
+	return resp, nil
+}
+
+// GetCollectionById implements CollectionRepositoryI.
+func (m MemoryCollectionRepository) GetCollectionById(ctx context.Context, id int64) (resp collection.CollectionResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	if row, ok := m.store.collections.get(id); ok {
+		resp = m.joinCollection(row)
+	}
+
+	return resp, nil
+}
+
+// UpdateCollection implements CollectionRepositoryI.
+func (m MemoryCollectionRepository) UpdateCollection(ctx context.Context, trx *gorm.DB, id int64, input collection.CollectionInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.collections.update(id, func(row *collection.CollectionResponse) {
+			now := time.Now()
+			row.UpdatedAt = &now
+			setCollectionColumns(row, input)
+		})
+	})
+}
+
+// GetCollectionEntries implements CollectionRepositoryI.
+func (m MemoryCollectionRepository) GetCollectionEntries(ctx context.Context, id int64) (resp []collection.CollectionEntryResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp = []collection.CollectionEntryResponse{}
+	for _, e := range m.store.collectionEntries.all() {
+		b, ok := m.store.books.get(e.BookID)
+		if e.CollectionID != id || !ok {
+			continue
+		}
+
+		entry := collection.CollectionEntryResponse{BookID: e.BookID, Title: b.Title, Position: e.Position, Note: e.Note, CreatedAt: e.CreatedAt}
+		if a, ok := m.store.authors.get(b.AuthorID); ok {
+			entry.AuthorName = a.Name
+		}
+		resp = append(resp, entry)
+	}
+
+	sort.Slice(resp, func(i, j int) bool {
+		if resp[i].Position != resp[j].Position {
+			return resp[i].Position < resp[j].Position
+		}
+
+		return resp[i].BookID < resp[j].BookID
+	})
+
+	return resp, nil
+}
+
+// entryID returns the row id of the entry of bookID in the collection. It must be called with
+// the store locked.
+func (m MemoryCollectionRepository) entryID(id, bookID int64) (entryID int64, ok bool) {
+	for rowID, e := range m.store.collectionEntries.rows {
+		if e.CollectionID == id && e.BookID == bookID {
+			return rowID, true
+		}
+	}
+
+	return 0, false
+}
+
+// GetCollectionEntry implements CollectionRepositoryI.
+func (m MemoryCollectionRepository) GetCollectionEntry(ctx context.Context, trx *gorm.DB, id, bookID int64) (resp collection.CollectionEntryResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	if entryID, ok := m.entryID(id, bookID); ok {
+		e := m.store.collectionEntries.rows[entryID]
+		resp = collection.CollectionEntryResponse{BookID: e.BookID, Position: e.Position, Note: e.Note, CreatedAt: e.CreatedAt}
+	}
+
+	return resp, nil
+}
+
+// GetCollectionLastPosition implements CollectionRepositoryI.
+func (m MemoryCollectionRepository) GetCollectionLastPosition(ctx context.Context, trx *gorm.DB, id int64) (position int, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	for _, e := range m.store.collectionEntries.rows {
+		if e.CollectionID == id && e.Position > position {
+			position = e.Position
+		}
+	}
+
+	return position, nil
+}
+
+// ShiftCollectionEntries implements CollectionRepositoryI.
+// Every entry at fromPosition or later moves by delta.
+func (m MemoryCollectionRepository) ShiftCollectionEntries(ctx context.Context, trx *gorm.DB, id int64, fromPosition, delta int) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		changes := []func(){}
+		for entryID, e := range m.store.collectionEntries.rows {
+			if e.CollectionID == id && e.Position >= fromPosition {
+				changes = append(changes, m.store.collectionEntries.update(entryID, func(e *collection.CollectionEntryInput) {
+					e.Position += delta
+				}))
+			}
+		}
+
+		return undoAll(changes)
+	})
+}
+
+// CreateCollectionEntry implements CollectionRepositoryI.
+func (m MemoryCollectionRepository) CreateCollectionEntry(ctx context.Context, trx *gorm.DB, input collection.CollectionEntryInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		input.CreatedAt = time.Now()
+		_, undo = m.store.collectionEntries.insert(func(int64) collection.CollectionEntryInput { return input })
+		return undo
+	})
+}
+
+// UpdateCollectionEntry implements CollectionRepositoryI.
+func (m MemoryCollectionRepository) UpdateCollectionEntry(ctx context.Context, trx *gorm.DB, input collection.CollectionEntryInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		entryID, ok := m.entryID(input.CollectionID, input.BookID)
+		if !ok {
+			return func() {}
+		}
+
+		return m.store.collectionEntries.update(entryID, func(e *collection.CollectionEntryInput) {
+			e.Position, e.Note = input.Position, input.Note
+		})
+	})
+}
+
+// DeleteCollectionEntry implements CollectionRepositoryI.
+func (m MemoryCollectionRepository) DeleteCollectionEntry(ctx context.Context, trx *gorm.DB, id, bookID int64) error {
+	return m.store.write(trx, func() (undo func()) {
+		entryID, ok := m.entryID(id, bookID)
+		if !ok {
+			return func() {}
+		}
+
+		return m.store.collectionEntries.delete(entryID)
+	})
+}
@@ -0,0 +1,25 @@
+package repository
+
+import "context"
+
+// MemoryHealthRepository reports the in-memory store as always up, with nothing to migrate.
+type MemoryHealthRepository struct{}
+
+func NewMemoryHealthRepository() HealthRepositoryI {
+	return MemoryHealthRepository{}
+}
+
+// Dialect implements HealthRepositoryI.
+func (h MemoryHealthRepository) Dialect() string {
+	return DriverMemory
+}
+
+// Ping implements HealthRepositoryI.
+func (h MemoryHealthRepository) Ping(ctx context.Context) (err error) {
+	return nil
+}
+
+// GetPendingMigrations implements HealthRepositoryI.
+func (h MemoryHealthRepository) GetPendingMigrations(ctx context.Context) (versions []string, err error) {
+	return []string{}, nil
+}
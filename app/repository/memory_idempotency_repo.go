@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/book-library/entity/idempotency"
+)
+
+type MemoryIdempotencyRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryIdempotencyRepository(store *MemoryStore) IdempotencyRepositoryI {
+	return MemoryIdempotencyRepository{store: store}
+}
+
+// idempotencyKeyHeld reports whether record still holds its key at now.
+func idempotencyKeyHeld(record idempotency.IdempotencyRecord, now time.Time) bool {
+	if record.ExpiresAt.Before(now) {
+		return false
+	}
+
+	return record.CompletedFlag || !record.LockedUntil.Before(now)
+}
+
+// CreateIdempotencyKey implements IdempotencyRepositoryI.
+// It takes the key in its scope unless another request holds it; an expired record, or one
+// whose request stopped before completing and let its lease run out, is replaced.
+func (m MemoryIdempotencyRepository) CreateIdempotencyKey(ctx context.Context, input idempotency.IdempotencyInput) (created bool, err error) {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	now := time.Now()
+	id := idempotencyID{scope: input.Scope, key: input.IdempotencyKey}
+	if record, ok := m.store.idempotencyKeys[id]; ok && idempotencyKeyHeld(record, now) {
+		return false, nil
+	}
+
+	m.store.idempotencyKeys[id] = idempotency.IdempotencyRecord{
+		Scope:          input.Scope,
+		IdempotencyKey: input.IdempotencyKey,
+		Owner:          input.Owner,
+		RequestHash:    input.RequestHash,
+		CreatedAt:      now,
+		LockedUntil:    input.LockedUntil,
+		ExpiresAt:      input.ExpiresAt,
+	}
+
+	return true, nil
+}
+
+// GetIdempotencyKey implements IdempotencyRepositoryI.
+func (m MemoryIdempotencyRepository) GetIdempotencyKey(ctx context.Context, scope, key string) (resp idempotency.IdempotencyRecord, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	return m.store.idempotencyKeys[idempotencyID{scope: scope, key: key}], nil
+}
+
+// ExtendIdempotencyKey implements IdempotencyRepositoryI.
+// It moves the lease of a key still held by owner to lockedUntil.
+func (m MemoryIdempotencyRepository) ExtendIdempotencyKey(ctx context.Context, scope, key, owner string, lockedUntil time.Time) (err error) {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	id := idempotencyID{scope: scope, key: key}
+	record, ok := m.store.idempotencyKeys[id]
+	if !ok || record.Owner != owner || record.CompletedFlag {
+		return nil
+	}
+
+	record.LockedUntil = lockedUntil
+	m.store.idempotencyKeys[id] = record
+
+	return nil
+}
+
+// CompleteIdempotencyKey implements IdempotencyRepositoryI.
+// A key taken over by another request after the lease ran out is left to that request.
+func (m MemoryIdempotencyRepository) CompleteIdempotencyKey(ctx context.Context, scope, key, owner string, input idempotency.StoredResponse) (err error) {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	id := idempotencyID{scope: scope, key: key}
+	record, ok := m.store.idempotencyKeys[id]
+	if !ok || record.Owner != owner {
+		return nil
+	}
+
+	record.StatusCode, record.ContentType, record.ResponseBody = input.StatusCode, input.ContentType, input.ResponseBody
+	record.CompletedFlag = true
+	m.store.idempotencyKeys[id] = record
+
+	return nil
+}
+
+// DeleteIdempotencyKey implements IdempotencyRepositoryI.
+// A key taken over by another request after the lease ran out is left to that request.
+func (m MemoryIdempotencyRepository) DeleteIdempotencyKey(ctx context.Context, scope, key, owner string) (err error) {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	id := idempotencyID{scope: scope, key: key}
+	if record, ok := m.store.idempotencyKeys[id]; ok && record.Owner == owner {
+		delete(m.store.idempotencyKeys, id)
+	}
+
+	return nil
+}
+
+// DeleteExpiredIdempotencyKeys implements IdempotencyRepositoryI.
+func (m MemoryIdempotencyRepository) DeleteExpiredIdempotencyKeys(ctx context.Context) (deleted int64, err error) {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	now := time.Now()
+	for id, record := range m.store.idempotencyKeys {
+		if !idempotencyKeyHeld(record, now) {
+			delete(m.store.idempotencyKeys, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/book-library/entity/collection"
+	"github.com/book-library/entity/member"
+	"gorm.io/gorm"
+)
+
+type MemoryMemberRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryMemberRepository(store *MemoryStore) MemberRepositoryI {
+	return MemoryMemberRepository{store: store}
+}
+
+// CreateMember implements MemberRepositoryI.
+func (m MemoryMemberRepository) CreateMember(ctx context.Context, trx *gorm.DB, input member.MemberInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		_, undo = m.store.members.insert(func(id int64) member.MemberResponse {
+			return member.MemberResponse{ID: id, Name: input.Name, Email: input.Email, CreatedAt: time.Now()}
+		})
+		return undo
+	})
+}
+
+// DeleteMember implements MemberRepositoryI.
+// Collections the member owns go with it, entries included, like the cascades of the schema.
+func (m MemoryMemberRepository) DeleteMember(ctx context.Context, trx *gorm.DB, id int64) error {
+	return m.store.write(trx, func() (undo func()) {
+		changes := []func(){}
+		for _, c := range m.store.collections.all() {
+			if c.OwnerID == id {
+				changes = append(changes,
+					m.store.collectionEntries.deleteWhere(func(e collection.CollectionEntryInput) bool { return e.CollectionID == c.ID }),
+					m.store.collections.delete(c.ID),
+				)
+			}
+		}
+
+		return undoAll(append(changes, m.store.members.delete(id)))
+	})
+}
+
+// GetAllMembers implements MemberRepositoryI.
+func (m MemoryMemberRepository) GetAllMembers(ctx context.Context, name string) (resp []member.MemberResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	return filterByName(m.store.members.all(), name, 0, func(mb member.MemberResponse) string { return mb.Name }), nil
+}
+
+// GetMemberById implements MemberRepositoryI.
+func (m MemoryMemberRepository) GetMemberById(ctx context.Context, id int64, email string) (resp member.MemberResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp, _ = m.store.members.find(func(mb member.MemberResponse) bool {
+		return (id == 0 || mb.ID == id) && (email == "" || strings.EqualFold(mb.Email, email))
+	})
+
+	return resp, nil
+}
+
+// UpdateMember implements MemberRepositoryI.
+func (m MemoryMemberRepository) UpdateMember(ctx context.Context, trx *gorm.DB, id int64, input member.MemberInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.members.update(id, func(mb *member.MemberResponse) {
+			now := time.Now()
+			mb.Name, mb.Email, mb.UpdatedAt = input.Name, input.Email, &now
+		})
+	})
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/book-library/entity/outbox"
+	"gorm.io/gorm"
+)
+
+type MemoryOutboxRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryOutboxRepository(store *MemoryStore) OutboxRepositoryI {
+	return MemoryOutboxRepository{store: store}
+}
+
+// CreateOutboxEvent implements OutboxRepositoryI.
+// It must be given the usecase transaction so a rollback drops the event with the change.
+func (m MemoryOutboxRepository) CreateOutboxEvent(ctx context.Context, trx *gorm.DB, input outbox.OutboxInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		_, undo = m.store.outboxEvents.insert(func(id int64) outbox.OutboxInput {
+			input.ID, input.CreatedAt, input.PublishedAt, input.PublishedSeq = id, time.Now(), nil, 0
+			return input
+		})
+		return undo
+	})
+}
+
+// outboxEvents returns up to limit events that match accepts, in id order or with bySeq in
+// publish order.
+func (m MemoryOutboxRepository) outboxEvents(limit int, bySeq bool, match func(outbox.OutboxInput) bool) (resp []outbox.OutboxEvent) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	rows := m.store.outboxEvents.all()
+	if bySeq {
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].PublishedSeq < rows[j].PublishedSeq })
+	}
+
+	resp = []outbox.OutboxEvent{}
+	for _, row := range rows {
+		if len(resp) == limit {
+			break
+		}
+
+		if match(row) {
+			resp = append(resp, outbox.OutboxEvent{
+				ID: row.ID, AggregateType: row.AggregateType, AggregateID: row.AggregateID,
+				EventType: row.EventType, Payload: row.Payload, Attempts: row.Attempts, CreatedAt: row.CreatedAt,
+				PublishedSeq: row.PublishedSeq,
+			})
+		}
+	}
+
+	return resp
+}
+
+// AcquireOutboxLease implements OutboxRepositoryI.
+func (m MemoryOutboxRepository) AcquireOutboxLease(ctx context.Context, owner string, ttl time.Duration) (acquired bool, err error) {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	now := time.Now()
+	lease := &m.store.outboxLease
+	if lease.owner != owner && !lease.expiresAt.Before(now) {
+		return false, nil
+	}
+
+	lease.owner, lease.expiresAt = owner, now.Add(ttl)
+
+	return true, nil
+}
+
+// ReleaseOutboxLease implements OutboxRepositoryI.
+func (m MemoryOutboxRepository) ReleaseOutboxLease(ctx context.Context, owner string) (err error) {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	if m.store.outboxLease.owner == owner {
+		m.store.outboxLease.expiresAt = time.Time{}
+	}
+
+	return nil
+}
+
+// GetPendingOutboxEvents implements OutboxRepositoryI.
+func (m MemoryOutboxRepository) GetPendingOutboxEvents(ctx context.Context, limit int) (resp []outbox.OutboxEvent, err error) {
+	return m.outboxEvents(limit, false, func(row outbox.OutboxInput) bool { return row.PublishedAt == nil }), nil
+}
+
+// MarkOutboxEventPublished implements OutboxRepositoryI.
+func (m MemoryOutboxRepository) MarkOutboxEventPublished(ctx context.Context, id int64) (err error) {
+	return m.store.write(nil, func() (undo func()) {
+		seq := m.lastPublishedSeq() + 1
+		return m.store.outboxEvents.update(id, func(row *outbox.OutboxInput) {
+			now := time.Now()
+			row.Attempts, row.LastError, row.PublishedAt, row.PublishedSeq = row.Attempts+1, "", &now, seq
+		})
+	})
+}
+
+// lastPublishedSeq is the highest publish sequence; the caller holds the store lock.
+func (m MemoryOutboxRepository) lastPublishedSeq() (seq int64) {
+	for _, row := range m.store.outboxEvents.rows {
+		seq = max(seq, row.PublishedSeq)
+	}
+
+	return seq
+}
+
+// MarkOutboxEventFailed implements OutboxRepositoryI.
+func (m MemoryOutboxRepository) MarkOutboxEventFailed(ctx context.Context, id int64, lastError string) (err error) {
+	return m.store.write(nil, func() (undo func()) {
+		return m.store.outboxEvents.update(id, func(row *outbox.OutboxInput) {
+			row.Attempts, row.LastError = row.Attempts+1, lastError
+		})
+	})
+}
+
+// GetPublishedOutboxEventsAfter implements OutboxRepositoryI.
+func (m MemoryOutboxRepository) GetPublishedOutboxEventsAfter(ctx context.Context, afterSeq int64, aggregateTypes []string, limit int) (resp []outbox.OutboxEvent, err error) {
+	return m.outboxEvents(limit, true, func(row outbox.OutboxInput) bool {
+		if row.PublishedAt == nil || row.PublishedSeq <= afterSeq {
+			return false
+		}
+
+		if len(aggregateTypes) == 0 {
+			return true
+		}
+
+		for _, aggregateType := range aggregateTypes {
+			if row.AggregateType == aggregateType {
+				return true
+			}
+		}
+
+		return false
+	}), nil
+}
+
+// GetLastPublishedOutboxSeq implements OutboxRepositoryI.
+func (m MemoryOutboxRepository) GetLastPublishedOutboxSeq(ctx context.Context) (seq int64, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	return m.lastPublishedSeq(), nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/book-library/entity/publisher"
+	"gorm.io/gorm"
+)
+
+type MemoryPublisherRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryPublisherRepository(store *MemoryStore) PublisherRepositoryI {
+	return MemoryPublisherRepository{store: store}
+}
+
+// CreatePublisher implements PublisherRepositoryI.
+func (m MemoryPublisherRepository) CreatePublisher(ctx context.Context, trx *gorm.DB, input publisher.PublisherInput) (id int64, err error) {
+	err = m.store.write(trx, func() (undo func()) {
+		id, undo = m.store.publishers.insert(func(id int64) publisher.PublisherResponse {
+			return publisher.PublisherResponse{ID: id, Name: input.Name, Address: input.Address, Website: input.Website, CreatedAt: time.Now()}
+		})
+		return undo
+	})
+
+	return id, err
+}
+
+// DeletePublisher implements PublisherRepositoryI.
+func (m MemoryPublisherRepository) DeletePublisher(ctx context.Context, trx *gorm.DB, id int64) error {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.publishers.delete(id)
+	})
+}
+
+// GetAllPublishers implements PublisherRepositoryI.
+func (m MemoryPublisherRepository) GetAllPublishers(ctx context.Context, name string) (resp []publisher.PublisherResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	return filterByName(m.store.publishers.all(), name, 0, func(p publisher.PublisherResponse) string { return p.Name }), nil
+}
+
+// GetPublisherById implements PublisherRepositoryI.
+func (m MemoryPublisherRepository) GetPublisherById(ctx context.Context, trx *gorm.DB, id int64, name string) (resp publisher.PublisherResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp, _ = m.store.publishers.find(func(p publisher.PublisherResponse) bool {
+		return (id == 0 || p.ID == id) && (name == "" || strings.EqualFold(p.Name, name))
+	})
+
+	return resp, nil
+}
+
+// UpdatePublisher implements PublisherRepositoryI.
+func (m MemoryPublisherRepository) UpdatePublisher(ctx context.Context, trx *gorm.DB, id int64, input publisher.PublisherInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.publishers.update(id, func(p *publisher.PublisherResponse) {
+			now := time.Now()
+			p.Name, p.Address, p.Website, p.UpdatedAt = input.Name, input.Address, input.Website, &now
+		})
+	})
+}
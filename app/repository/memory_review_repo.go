@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/book-library/entity/review"
+	"gorm.io/gorm"
+)
+
+type MemoryReviewRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryReviewRepository(store *MemoryStore) ReviewRepositoryI {
+	return MemoryReviewRepository{store: store}
+}
+
+// CreateReview implements ReviewRepositoryI.
+func (m MemoryReviewRepository) CreateReview(ctx context.Context, trx *gorm.DB, input review.ReviewInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		_, undo = m.store.reviews.insert(func(id int64) review.ReviewResponse {
+			return review.ReviewResponse{
+				ID: id, BookID: input.BookID, MemberID: input.MemberID, Rating: input.Rating,
+				Review: input.Review, Status: input.Status, CreatedAt: time.Now(),
+			}
+		})
+		return undo
+	})
+}
+
+// DeleteReview implements ReviewRepositoryI.
+func (m MemoryReviewRepository) DeleteReview(ctx context.Context, trx *gorm.DB, id int64) error {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.reviews.delete(id)
+	})
+}
+
+// GetAllReviews implements ReviewRepositoryI. Like the joins of the SQL query, reviews whose
+// book or member is gone are left out.
+func (m MemoryReviewRepository) GetAllReviews(ctx context.Context, search review.ReviewSearch) (resp []review.ReviewResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp = []review.ReviewResponse{}
+	rows := m.store.reviews.all()
+	for i := len(rows) - 1; i >= 0; i-- {
+		r := rows[i]
+
+		b, bookFound := m.store.books.get(r.BookID)
+		mb, memberFound := m.store.members.get(r.MemberID)
+		switch {
+		case !bookFound || !memberFound,
+			search.BookID != 0 && r.BookID != search.BookID,
+			len(search.BookIDs) > 0 && !containsID(search.BookIDs, r.BookID),
+			search.MemberID != 0 && r.MemberID != search.MemberID,
+			search.Status != "" && r.Status != search.Status:
+			continue
+		}
+
+		r.BookTitle, r.MemberName = b.Title, mb.Name
+		resp = append(resp, r)
+	}
+
+	return resp, nil
+}
+
+// GetReviewById implements ReviewRepositoryI.
+func (m MemoryReviewRepository) GetReviewById(ctx context.Context, id, bookID, memberID int64) (resp review.ReviewResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp, _ = m.store.reviews.find(func(r review.ReviewResponse) bool {
+		return (id == 0 || r.ID == id) && (bookID == 0 || r.BookID == bookID) && (memberID == 0 || r.MemberID == memberID)
+	})
+
+	return resp, nil
+}
+
+// UpdateReview implements ReviewRepositoryI.
+func (m MemoryReviewRepository) UpdateReview(ctx context.Context, trx *gorm.DB, id int64, input review.ReviewInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.reviews.update(id, func(r *review.ReviewResponse) {
+			now := time.Now()
+			r.Rating, r.Review, r.Status, r.UpdatedAt = input.Rating, input.Review, input.Status, &now
+		})
+	})
+}
+
+// UpdateReviewStatus implements ReviewRepositoryI.
+func (m MemoryReviewRepository) UpdateReviewStatus(ctx context.Context, trx *gorm.DB, id int64, status string) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.reviews.update(id, func(r *review.ReviewResponse) {
+			now := time.Now()
+			r.Status, r.UpdatedAt = status, &now
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/book-library/entity/series"
+	"gorm.io/gorm"
+)
+
+type MemorySeriesRepository struct {
+	store *MemoryStore
+}
+
+func NewMemorySeriesRepository(store *MemoryStore) SeriesRepositoryI {
+	return MemorySeriesRepository{store: store}
+}
+
+// CreateSeries implements SeriesRepositoryI.
+func (m MemorySeriesRepository) CreateSeries(ctx context.Context, trx *gorm.DB, input series.SeriesInput) (id int64, err error) {
+	err = m.store.write(trx, func() (undo func()) {
+		id, undo = m.store.series.insert(func(id int64) series.SeriesResponse {
+			return series.SeriesResponse{ID: id, Name: input.Name, Description: input.Description, CreatedAt: time.Now()}
+		})
+		return undo
+	})
+
+	return id, err
+}
+
+// DeleteSeries implements SeriesRepositoryI.
+func (m MemorySeriesRepository) DeleteSeries(ctx context.Context, trx *gorm.DB, id int64) error {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.series.delete(id)
+	})
+}
+
+// GetAllSeries implements SeriesRepositoryI.
+func (m MemorySeriesRepository) GetAllSeries(ctx context.Context, name string) (resp []series.SeriesResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	return filterByName(m.store.series.all(), name, 0, func(s series.SeriesResponse) string { return s.Name }), nil
+}
+
+// GetSeriesById implements SeriesRepositoryI.
+func (m MemorySeriesRepository) GetSeriesById(ctx context.Context, trx *gorm.DB, id int64, name string) (resp series.SeriesResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp, _ = m.store.series.find(func(s series.SeriesResponse) bool {
+		return (id == 0 || s.ID == id) && (name == "" || strings.EqualFold(s.Name, name))
+	})
+
+	return resp, nil
+}
+
+// UpdateSeries implements SeriesRepositoryI.
+func (m MemorySeriesRepository) UpdateSeries(ctx context.Context, trx *gorm.DB, id int64, input series.SeriesInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.series.update(id, func(s *series.SeriesResponse) {
+			now := time.Now()
+			s.Name, s.Description, s.UpdatedAt = input.Name, input.Description, &now
+		})
+	})
+}
+
+// GetSeriesVolumes implements SeriesRepositoryI.
+func (m MemorySeriesRepository) GetSeriesVolumes(ctx context.Context, id int64) (resp []series.SeriesVolume, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp = []series.SeriesVolume{}
+	for _, b := range m.store.books.all() {
+		if b.SeriesID == id {
+			resp = append(resp, series.SeriesVolume{
+				BookID: b.ID, Title: b.Title, ISBN: b.ISBN, Volume: b.SeriesVolume,
+				PublicationYear: b.PublicationYear, PublishedFlag: b.PublishedFlag,
+			})
+		}
+	}
+	sort.SliceStable(resp, func(i, j int) bool { return resp[i].Volume < resp[j].Volume })
+
+	return resp, nil
+}
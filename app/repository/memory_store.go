@@ -0,0 +1,369 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/book-library/entity/author"
+	"github.com/book-library/entity/book"
+	"github.com/book-library/entity/category"
+	"github.com/book-library/entity/collection"
+	"github.com/book-library/entity/idempotency"
+	"github.com/book-library/entity/member"
+	"github.com/book-library/entity/outbox"
+	"github.com/book-library/entity/publisher"
+	"github.com/book-library/entity/review"
+	"github.com/book-library/entity/series"
+	"github.com/book-library/entity/webhook"
+	"github.com/book-library/entity/work"
+	"gorm.io/gorm"
+)
+
+// DriverMemory is the DRIVER_NAME that runs on the in-memory repositories.
+const DriverMemory = "memory"
+
+// MemoryStore holds the tables behind the in-memory repositories, used with DRIVER_NAME=memory
+// to run without a database. A write given a transaction from MemoryTransactionRepository keeps
+// how to undo itself until the transaction ends, so a rollback puts every row back. Writers are
+// serialized: one transaction is open at a time and a write without one waits for it to end, so
+// no other write can land on the rows a rollback restores and no transaction acts on another's
+// uncommitted rows. Unlike Postgres, reads outside the transaction do not wait and can still
+// see its rows before it commits, and only the ON DELETE CASCADE rules of the schema are kept,
+// not the other constraints.
+type MemoryStore struct {
+	mu           sync.RWMutex
+	transactions map[*gorm.DB]*memoryTransaction
+
+	// tx is held from BeginTransaction until the transaction ends, and by every write made
+	// without a transaction. It is taken before mu.
+	tx sync.Mutex
+
+	books             *memoryTable[book.BookResponse]
+	authors           *memoryTable[author.AuthorResponse]
+	categories        *memoryTable[category.CategoryResponse]
+	publishers        *memoryTable[publisher.PublisherResponse]
+	series            *memoryTable[series.SeriesResponse]
+	works             *memoryTable[work.WorkResponse]
+	members           *memoryTable[member.MemberResponse]
+	reviews           *memoryTable[review.ReviewResponse]
+	collections       *memoryTable[collection.CollectionResponse]
+	collectionEntries *memoryTable[collection.CollectionEntryInput]
+	webhooks          *memoryTable[webhookRow]
+	webhookDeliveries *memoryTable[webhook.WebhookDeliveryResponse]
+	outboxEvents      *memoryTable[outbox.OutboxInput]
+	idempotencyKeys   map[idempotencyID]idempotency.IdempotencyRecord
+	outboxLease       memoryLease
+}
+
+type idempotencyID struct {
+	scope string
+	key   string
+}
+
+type memoryLease struct {
+	owner     string
+	expiresAt time.Time
+}
+
+type memoryTransaction struct {
+	undo []func()
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		transactions:      map[*gorm.DB]*memoryTransaction{},
+		books:             newMemoryTable[book.BookResponse](),
+		authors:           newMemoryTable[author.AuthorResponse](),
+		categories:        newMemoryTable[category.CategoryResponse](),
+		publishers:        newMemoryTable[publisher.PublisherResponse](),
+		series:            newMemoryTable[series.SeriesResponse](),
+		works:             newMemoryTable[work.WorkResponse](),
+		members:           newMemoryTable[member.MemberResponse](),
+		reviews:           newMemoryTable[review.ReviewResponse](),
+		collections:       newMemoryTable[collection.CollectionResponse](),
+		collectionEntries: newMemoryTable[collection.CollectionEntryInput](),
+		webhooks:          newMemoryTable[webhookRow](),
+		webhookDeliveries: newMemoryTable[webhook.WebhookDeliveryResponse](),
+		outboxEvents:      newMemoryTable[outbox.OutboxInput](),
+		idempotencyKeys:   map[idempotencyID]idempotency.IdempotencyRecord{},
+	}
+}
+
+// write applies change under the write lock. Inside trx the undo function change returns is
+// kept for a rollback; without trx the change is final, and waits for an open transaction.
+func (s *MemoryStore) write(trx *gorm.DB, change func() (undo func())) error {
+	if trx == nil {
+		s.tx.Lock()
+		defer s.tx.Unlock()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if trx == nil {
+		change()
+		return nil
+	}
+
+	tx, ok := s.transactions[trx]
+	if !ok {
+		return gorm.ErrInvalidTransaction
+	}
+
+	tx.undo = append(tx.undo, change())
+
+	return nil
+}
+
+// undoAll undoes changes in reverse order.
+func undoAll(changes []func()) func() {
+	return func() {
+		for i := len(changes) - 1; i >= 0; i-- {
+			changes[i]()
+		}
+	}
+}
+
+// memoryTable is one table of the store. Ids come from a sequence that, like a Postgres one,
+// does not go back on rollback.
+type memoryTable[T any] struct {
+	rows   map[int64]T
+	nextID int64
+}
+
+func newMemoryTable[T any]() *memoryTable[T] {
+	return &memoryTable[T]{rows: map[int64]T{}}
+}
+
+func (t *memoryTable[T]) get(id int64) (row T, ok bool) {
+	row, ok = t.rows[id]
+	return row, ok
+}
+
+// all returns the rows in id order.
+func (t *memoryTable[T]) all() []T {
+	ids := make([]int64, 0, len(t.rows))
+	for id := range t.rows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	rows := make([]T, 0, len(ids))
+	for _, id := range ids {
+		rows = append(rows, t.rows[id])
+	}
+
+	return rows
+}
+
+// find returns the first row in id order that match accepts.
+func (t *memoryTable[T]) find(match func(T) bool) (row T, ok bool) {
+	for _, candidate := range t.all() {
+		if match(candidate) {
+			return candidate, true
+		}
+	}
+
+	return row, false
+}
+
+func (t *memoryTable[T]) insert(newRow func(id int64) T) (id int64, undo func()) {
+	t.nextID++
+	id = t.nextID
+	t.rows[id] = newRow(id)
+
+	return id, func() { delete(t.rows, id) }
+}
+
+// update changes the row with id in place; a missing row is left alone, like an UPDATE
+// matching nothing.
+func (t *memoryTable[T]) update(id int64, change func(row *T)) (undo func()) {
+	old, ok := t.rows[id]
+	if !ok {
+		return func() {}
+	}
+
+	row := old
+	change(&row)
+	t.rows[id] = row
+
+	return func() { t.rows[id] = old }
+}
+
+func (t *memoryTable[T]) delete(id int64) (undo func()) {
+	old, ok := t.rows[id]
+	if !ok {
+		return func() {}
+	}
+
+	delete(t.rows, id)
+
+	return func() { t.rows[id] = old }
+}
+
+// deleteWhere deletes every row match accepts.
+func (t *memoryTable[T]) deleteWhere(match func(T) bool) (undo func()) {
+	changes := []func(){}
+	for id, row := range t.rows {
+		if match(row) {
+			changes = append(changes, t.delete(id))
+		}
+	}
+
+	return undoAll(changes)
+}
+
+type MemoryTransactionRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryTransactionRepository(store *MemoryStore) TransactionRepositoryI {
+	return MemoryTransactionRepository{store: store}
+}
+
+// BeginTransaction implements TransactionRepositoryI.
+// It waits until no other transaction is open. The returned handle only identifies the
+// transaction to the in-memory repositories.
+func (m MemoryTransactionRepository) BeginTransaction(ctx context.Context) *gorm.DB {
+	trx := &gorm.DB{Config: &gorm.Config{}, Statement: &gorm.Statement{Context: ctx}}
+
+	m.store.tx.Lock()
+	m.store.mu.Lock()
+	m.store.transactions[trx] = &memoryTransaction{}
+	m.store.mu.Unlock()
+
+	return trx
+}
+
+// RollBackTransaction implements TransactionRepositoryI.
+func (m MemoryTransactionRepository) RollBackTransaction(ctx context.Context, trx *gorm.DB) *gorm.DB {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	tx, ok := m.store.transactions[trx]
+	if !ok {
+		return &gorm.DB{Error: gorm.ErrInvalidTransaction}
+	}
+
+	undoAll(tx.undo)()
+	delete(m.store.transactions, trx)
+	m.store.tx.Unlock()
+
+	return &gorm.DB{}
+}
+
+// CommitTransaction implements TransactionRepositoryI.
+func (m MemoryTransactionRepository) CommitTransaction(ctx context.Context, trx *gorm.DB) *gorm.DB {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	if _, ok := m.store.transactions[trx]; !ok {
+		return &gorm.DB{Error: gorm.ErrInvalidTransaction}
+	}
+
+	delete(m.store.transactions, trx)
+	m.store.tx.Unlock()
+
+	return &gorm.DB{}
+}
+
+// containsFold is the in-memory ILIKE '%substr%'.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// trigramSimilarity follows pg_trgm's similarity(): the share of trigrams the two strings
+// have in common, where each lower-cased word is padded with two spaces in front and one after.
+func trigramSimilarity(a, b string) float64 {
+	left, right := trigrams(a), trigrams(b)
+	if len(left) == 0 || len(right) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for trigram := range left {
+		if right[trigram] {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(left)+len(right)-shared)
+}
+
+func trigrams(s string) map[string]bool {
+	set := map[string]bool{}
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+
+	return set
+}
+
+// writesColumn tells whether an update limited to columns writes column; no columns means all.
+func writesColumn(columns []string, column string) bool {
+	if len(columns) == 0 {
+		return true
+	}
+
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterByName is the name search of the GetAll methods: a case-insensitive substring, or with
+// a similarity threshold the trigram matches, best first.
+func filterByName[T any](rows []T, name string, similarity float64, nameOf func(T) string) []T {
+	if name == "" {
+		return rows
+	}
+
+	type scored struct {
+		row   T
+		score float64
+	}
+
+	matches := []scored{}
+	for _, row := range rows {
+		switch {
+		case similarity > 0:
+			if score := trigramSimilarity(nameOf(row), name); score >= similarity {
+				matches = append(matches, scored{row, score})
+			}
+		case containsFold(nameOf(row), name):
+			matches = append(matches, scored{row, 0})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	filtered := make([]T, 0, len(matches))
+	for _, match := range matches {
+		filtered = append(filtered, match.row)
+	}
+
+	return filtered
+}
+
+func containsID(ids []int64, id int64) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/book-library/entity/author"
+	"gorm.io/gorm"
+)
+
+// waitsFor runs write and reports whether it was still blocked after a moment; the returned
+// channel is closed once write is done.
+func waitsFor(write func()) (blocked bool, done <-chan struct{}) {
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		write()
+	}()
+
+	select {
+	case <-finished:
+		return false, finished
+	case <-time.After(20 * time.Millisecond):
+		return true, finished
+	}
+}
+
+func TestMemoryRollbackKeepsOtherWrites(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	authorRepo := NewMemoryAuthorRepository(store)
+	trRepo := NewMemoryTransactionRepository(store)
+
+	id, _ := authorRepo.CreateAuthor(ctx, nil, author.AuthorInput{Name: "Ann Leckie", Email: "ann@example.com"})
+
+	first := trRepo.BeginTransaction(ctx)
+	authorRepo.UpdateAuthor(ctx, first, id, author.AuthorInput{Name: "Rolled Back", Email: "ann@example.com"})
+
+	tests := []struct {
+		name  string
+		write func()
+	}{
+		{name: "transaction", write: func() {
+			second := trRepo.BeginTransaction(ctx)
+			authorRepo.UpdateAuthor(ctx, second, id, author.AuthorInput{Name: "Committed", Email: "ann@example.com"})
+			trRepo.CommitTransaction(ctx, second)
+		}},
+		{name: "write without a transaction", write: func() {
+			authorRepo.UpdateAuthor(ctx, nil, id, author.AuthorInput{Name: "Committed", Email: "ann@example.com"})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocked, done := waitsFor(tt.write)
+			if !blocked {
+				t.Fatal("the write went through while another transaction was open")
+			}
+
+			trRepo.RollBackTransaction(ctx, first)
+			<-done
+
+			resp, _ := authorRepo.GetAuthorById(ctx, nil, id, "")
+			if resp.Name != "Committed" {
+				t.Errorf("name = %s, want the committed write kept after the rollback", resp.Name)
+			}
+
+			first = trRepo.BeginTransaction(ctx)
+			authorRepo.UpdateAuthor(ctx, first, id, author.AuthorInput{Name: "Rolled Back", Email: "ann@example.com"})
+		})
+	}
+
+	trRepo.RollBackTransaction(ctx, first)
+}
+
+func TestMemoryTransactionEndsOnce(t *testing.T) {
+	ctx := context.Background()
+	trRepo := NewMemoryTransactionRepository(NewMemoryStore())
+
+	trx := trRepo.BeginTransaction(ctx)
+	if err := trRepo.CommitTransaction(ctx, trx).Error; err != nil {
+		t.Fatalf("CommitTransaction: %v", err)
+	}
+
+	if err := trRepo.RollBackTransaction(ctx, trx).Error; err != gorm.ErrInvalidTransaction {
+		t.Errorf("RollBackTransaction after the commit = %v, want %v", err, gorm.ErrInvalidTransaction)
+	}
+
+	if blocked, _ := waitsFor(func() { trRepo.RollBackTransaction(ctx, trRepo.BeginTransaction(ctx)) }); blocked {
+		t.Error("BeginTransaction waited after the last transaction ended")
+	}
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/book-library/entity/suggest"
+)
+
+type MemorySuggestRepository struct {
+	store *MemoryStore
+}
+
+func NewMemorySuggestRepository(store *MemoryStore) SuggestRepositoryI {
+	return MemorySuggestRepository{store: store}
+}
+
+// GetSuggestions implements SuggestRepositoryI.
+// It returns up to limit published book titles, author names and category names starting with
+// prefix, ignoring case.
+func (m MemorySuggestRepository) GetSuggestions(ctx context.Context, prefix string, limit int) (resp []suggest.Suggestion, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	prefix = strings.ToLower(prefix)
+	resp = []suggest.Suggestion{}
+
+	books := []suggest.Suggestion{}
+	for _, b := range m.store.books.all() {
+		if b.PublishedFlag {
+			books = append(books, suggest.Suggestion{Type: suggest.TypeBook, ID: b.ID, Label: b.Title})
+		}
+	}
+	resp = append(resp, prefixSuggestions(books, prefix, limit)...)
+
+	authors := []suggest.Suggestion{}
+	for _, a := range m.store.authors.all() {
+		authors = append(authors, suggest.Suggestion{Type: suggest.TypeAuthor, ID: a.ID, Label: a.Name})
+	}
+	resp = append(resp, prefixSuggestions(authors, prefix, limit)...)
+
+	categories := []suggest.Suggestion{}
+	for _, c := range m.store.categories.all() {
+		categories = append(categories, suggest.Suggestion{Type: suggest.TypeCategory, ID: c.ID, Label: c.Name})
+	}
+	resp = append(resp, prefixSuggestions(categories, prefix, limit)...)
+
+	return resp, nil
+}
+
+// prefixSuggestions keeps the first limit candidates, by lower-cased label, that start with prefix.
+func prefixSuggestions(candidates []suggest.Suggestion, prefix string, limit int) []suggest.Suggestion {
+	matches := []suggest.Suggestion{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate.Label), prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return strings.ToLower(matches[i].Label) < strings.ToLower(matches[j].Label)
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/book-library/entity/webhook"
+	"gorm.io/gorm"
+)
+
+type MemoryWebhookRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryWebhookRepository(store *MemoryStore) WebhookRepositoryI {
+	return MemoryWebhookRepository{store: store}
+}
+
+// CreateWebhook implements WebhookRepositoryI.
+func (m MemoryWebhookRepository) CreateWebhook(ctx context.Context, trx *gorm.DB, input webhook.WebhookInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		_, undo = m.store.webhooks.insert(func(id int64) webhookRow {
+			row := webhookRow{ID: id, CreatedAt: time.Now()}
+			setWebhookColumns(&row, input)
+			return row
+		})
+		return undo
+	})
+}
+
+func setWebhookColumns(row *webhookRow, input webhook.WebhookInput) {
+	row.TargetURL, row.Secret = input.TargetURL, input.Secret
+	row.EventTypes = joinEventTypes(input.EventTypes)
+	row.ActiveFlag = input.ActiveFlag != nil && *input.ActiveFlag
+}
+
+// DeleteWebhook implements WebhookRepositoryI.
+// Its deliveries go with it, like the cascade of tb_webhook_delivery.
+func (m MemoryWebhookRepository) DeleteWebhook(ctx context.Context, trx *gorm.DB, id int64) error {
+	return m.store.write(trx, func() (undo func()) {
+		return undoAll([]func(){
+			m.store.webhookDeliveries.deleteWhere(func(d webhook.WebhookDeliveryResponse) bool { return d.WebhookID == id }),
+			m.store.webhooks.delete(id),
+		})
+	})
+}
+
+// GetAllWebhooks implements WebhookRepositoryI.
+func (m MemoryWebhookRepository) GetAllWebhooks(ctx context.Context) (resp []webhook.WebhookResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp = []webhook.WebhookResponse{}
+	for _, row := range m.store.webhooks.all() {
+		resp = append(resp, webhook.WebhookResponse{
+			ID:         row.ID,
+			TargetURL:  row.TargetURL,
+			EventTypes: splitEventTypes(row.EventTypes),
+			ActiveFlag: row.ActiveFlag,
+			CreatedAt:  row.CreatedAt,
+			UpdatedAt:  row.UpdatedAt,
+		})
+	}
+
+	return resp, nil
+}
+
+// GetWebhookById implements WebhookRepositoryI.
+func (m MemoryWebhookRepository) GetWebhookById(ctx context.Context, id int64) (resp webhook.WebhookSubscription, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	row, ok := m.store.webhooks.get(id)
+	if !ok {
+		return resp, nil
+	}
+
+	return row.subscription(), nil
+}
+
+// GetWebhooksByEvent implements WebhookRepositoryI.
+func (m MemoryWebhookRepository) GetWebhooksByEvent(ctx context.Context, eventType string) (resp []webhook.WebhookSubscription, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	for _, row := range m.store.webhooks.all() {
+		if row.ActiveFlag && strings.Contains(row.EventTypes, ","+eventType+",") {
+			resp = append(resp, row.subscription())
+		}
+	}
+
+	return resp, nil
+}
+
+// UpdateWebhook implements WebhookRepositoryI.
+func (m MemoryWebhookRepository) UpdateWebhook(ctx context.Context, trx *gorm.DB, id int64, input webhook.WebhookInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.webhooks.update(id, func(row *webhookRow) {
+			now := time.Now()
+			row.UpdatedAt = &now
+			setWebhookColumns(row, input)
+		})
+	})
+}
+
+// CreateWebhookDelivery implements WebhookRepositoryI.
+func (m MemoryWebhookRepository) CreateWebhookDelivery(ctx context.Context, input webhook.WebhookDeliveryInput) (id int64, err error) {
+	err = m.store.write(nil, func() (undo func()) {
+		id, undo = m.store.webhookDeliveries.insert(func(id int64) webhook.WebhookDeliveryResponse {
+			return webhook.WebhookDeliveryResponse{
+				ID: id, WebhookID: input.WebhookID, EventID: input.EventID, EventType: input.EventType,
+				Payload: input.Payload, Status: input.Status, Attempts: input.Attempts, ResponseCode: input.ResponseCode,
+				LastError: input.LastError, CreatedAt: time.Now(), DeliveredAt: input.DeliveredAt,
+				NextAttemptAt: input.NextAttemptAt,
+			}
+		})
+		return undo
+	})
+
+	return id, err
+}
+
+// UpdateWebhookDelivery implements WebhookRepositoryI.
+func (m MemoryWebhookRepository) UpdateWebhookDelivery(ctx context.Context, input webhook.WebhookDeliveryInput) (err error) {
+	return m.store.write(nil, func() (undo func()) {
+		return m.store.webhookDeliveries.update(input.ID, func(d *webhook.WebhookDeliveryResponse) {
+			d.Status, d.Attempts, d.ResponseCode = input.Status, input.Attempts, input.ResponseCode
+			d.LastError, d.DeliveredAt, d.NextAttemptAt = input.LastError, input.DeliveredAt, input.NextAttemptAt
+		})
+	})
+}
+
+// ClaimDueWebhookDeliveries implements WebhookRepositoryI.
+func (m MemoryWebhookRepository) ClaimDueWebhookDeliveries(ctx context.Context, lease time.Duration, limit int) (resp []webhook.WebhookDeliveryInput, err error) {
+	now := time.Now()
+
+	err = m.store.write(nil, func() (undo func()) {
+		due := []webhook.WebhookDeliveryResponse{}
+		for _, d := range m.store.webhookDeliveries.all() {
+			if d.Status == webhook.DeliveryStatusPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) {
+				due = append(due, d)
+			}
+		}
+		sort.SliceStable(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt) })
+
+		changes := []func(){}
+		for i := 0; i < len(due) && i < limit; i++ {
+			resp = append(resp, webhook.WebhookDeliveryInput(due[i]))
+			changes = append(changes, m.store.webhookDeliveries.update(due[i].ID, func(d *webhook.WebhookDeliveryResponse) {
+				leaseEnd := now.Add(lease)
+				d.NextAttemptAt = &leaseEnd
+			}))
+		}
+
+		return undoAll(changes)
+	})
+
+	return resp, err
+}
+
+// GetWebhookDeliveries implements WebhookRepositoryI.
+func (m MemoryWebhookRepository) GetWebhookDeliveries(ctx context.Context, webhookID int64) (resp []webhook.WebhookDeliveryResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp = []webhook.WebhookDeliveryResponse{}
+	rows := m.store.webhookDeliveries.all()
+	for i := len(rows) - 1; i >= 0 && len(resp) < 100; i-- {
+		if rows[i].WebhookID == webhookID {
+			resp = append(resp, rows[i])
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/book-library/entity/work"
+	"gorm.io/gorm"
+)
+
+type MemoryWorkRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryWorkRepository(store *MemoryStore) WorkRepositoryI {
+	return MemoryWorkRepository{store: store}
+}
+
+// CreateWork implements WorkRepositoryI.
+func (m MemoryWorkRepository) CreateWork(ctx context.Context, trx *gorm.DB, input work.WorkInput) (id int64, err error) {
+	err = m.store.write(trx, func() (undo func()) {
+		id, undo = m.store.works.insert(func(id int64) work.WorkResponse {
+			row := work.WorkResponse{ID: id, CreatedAt: time.Now()}
+			setWorkColumns(&row, input)
+			return row
+		})
+		return undo
+	})
+
+	return id, err
+}
+
+func setWorkColumns(row *work.WorkResponse, input work.WorkInput) {
+	row.Title, row.Description = input.Title, input.Description
+	row.AuthorID, row.CategoryID = input.AuthorID, input.CategoryID
+	row.OriginalLanguage, row.FirstPublicationYear = input.OriginalLanguage, input.FirstPublicationYear
+}
+
+// DeleteWork implements WorkRepositoryI.
+func (m MemoryWorkRepository) DeleteWork(ctx context.Context, trx *gorm.DB, id int64) error {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.works.delete(id)
+	})
+}
+
+// GetAllWorks implements WorkRepositoryI.
+func (m MemoryWorkRepository) GetAllWorks(ctx context.Context, trx *gorm.DB, title string) (resp []work.WorkResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	return filterByName(m.store.works.all(), title, 0, func(w work.WorkResponse) string { return w.Title }), nil
+}
+
+// GetWorkById implements WorkRepositoryI.
+func (m MemoryWorkRepository) GetWorkById(ctx context.Context, trx *gorm.DB, id int64) (resp work.WorkResponse, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp, _ = m.store.works.find(func(w work.WorkResponse) bool { return id == 0 || w.ID == id })
+
+	return resp, nil
+}
+
+// UpdateWork implements WorkRepositoryI.
+func (m MemoryWorkRepository) UpdateWork(ctx context.Context, trx *gorm.DB, id int64, input work.WorkInput) (err error) {
+	return m.store.write(trx, func() (undo func()) {
+		return m.store.works.update(id, func(w *work.WorkResponse) {
+			now := time.Now()
+			w.UpdatedAt = &now
+			setWorkColumns(w, input)
+		})
+	})
+}
+
+// GetWorkEditions implements WorkRepositoryI.
+func (m MemoryWorkRepository) GetWorkEditions(ctx context.Context, trx *gorm.DB, id int64) (resp []work.WorkEdition, err error) {
+	m.store.mu.RLock()
+	defer m.store.mu.RUnlock()
+
+	resp = []work.WorkEdition{}
+	for _, b := range m.store.books.all() {
+		if b.WorkID == id {
+			resp = append(resp, work.WorkEdition{
+				BookID: b.ID, Title: b.Title, ISBN: b.ISBN, Edition: b.Edition, Language: b.Language,
+				PublicationYear: b.PublicationYear, PublishedFlag: b.PublishedFlag,
+			})
+		}
+	}
+	sort.SliceStable(resp, func(i, j int) bool { return resp[i].PublicationYear < resp[j].PublicationYear })
+
+	return resp, nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	_db "github.com/book-library/app/helper"
+	"github.com/book-library/entity/outbox"
+	"gorm.io/gorm"
+)
+
+type OutboxRepositoryI interface {
+	CreateOutboxEvent(ctx context.Context, trx *gorm.DB, input outbox.OutboxInput) (err error)
+	AcquireOutboxLease(ctx context.Context, owner string, ttl time.Duration) (acquired bool, err error)
+	ReleaseOutboxLease(ctx context.Context, owner string) (err error)
+	GetPendingOutboxEvents(ctx context.Context, limit int) (resp []outbox.OutboxEvent, err error)
+	MarkOutboxEventPublished(ctx context.Context, id int64) (err error)
+	MarkOutboxEventFailed(ctx context.Context, id int64, lastError string) (err error)
+	GetPublishedOutboxEventsAfter(ctx context.Context, afterSeq int64, aggregateTypes []string, limit int) (resp []outbox.OutboxEvent, err error)
+	GetLastPublishedOutboxSeq(ctx context.Context) (seq int64, err error)
+}
+
+// outboxRelayLease names the row of tb_outbox_lease the relays compete for.
+const outboxRelayLease = "relay"
+
+type OutboxRepository struct {
+	conn *gorm.DB
+}
+
+func NewOutboxRepository(conn *gorm.DB) OutboxRepositoryI {
+	return OutboxRepository{conn: conn}
+}
+
+// CreateOutboxEvent implements OutboxRepositoryI.
+// It must be given the usecase transaction so the event is stored only if the change is.
+func (o OutboxRepository) CreateOutboxEvent(ctx context.Context, trx *gorm.DB, input outbox.OutboxInput) (err error) {
+	if trx == nil {
+		trx = o.conn.WithContext(ctx)
+	}
+
+	input.CreatedAt = time.Now()
+	input.PublishedAt = nil
+
+	// published_seq stays NULL until the event is published; the unique index allows many NULLs.
+	sql := trx.Table(_db.OutboxTableName).Omit("published_seq").Create(&input)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// AcquireOutboxLease implements OutboxRepositoryI.
+// The lease is taken when it ran out or already belongs to owner, and then runs for ttl from now.
+// A single UPDATE decides, so two relays never both get it.
+func (o OutboxRepository) AcquireOutboxLease(ctx context.Context, owner string, ttl time.Duration) (acquired bool, err error) {
+	now := time.Now()
+	updateLease := map[string]interface{}{
+		"owner":      owner,
+		"expires_at": now.Add(ttl),
+	}
+
+	sql := o.conn.WithContext(ctx).Table(_db.OutboxLeaseTableName).
+		Where("name = ? AND (owner = ? OR expires_at < ?)", outboxRelayLease, owner, now).
+		Updates(updateLease)
+	if sql.Error != nil {
+		return false, sql.Error
+	}
+
+	return sql.RowsAffected == 1, nil
+}
+
+// ReleaseOutboxLease implements OutboxRepositoryI.
+// It ends the lease now if owner holds it, so another relay takes over without waiting it out.
+func (o OutboxRepository) ReleaseOutboxLease(ctx context.Context, owner string) (err error) {
+	sql := o.conn.WithContext(ctx).Table(_db.OutboxLeaseTableName).
+		Where("name = ? AND owner = ?", outboxRelayLease, owner).
+		Update("expires_at", time.Unix(0, 0))
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// GetPendingOutboxEvents implements OutboxRepositoryI.
+// Only the holder of the relay lease may publish what it returns.
+func (o OutboxRepository) GetPendingOutboxEvents(ctx context.Context, limit int) (resp []outbox.OutboxEvent, err error) {
+	query := `
+		SELECT
+			id, aggregate_type, aggregate_id, event_type, payload, attempts, created_at
+		FROM
+			` + _db.OutboxTableName + `
+		WHERE
+			published_at IS NULL
+		ORDER BY
+			id ASC
+		LIMIT ?`
+
+	sql := o.conn.WithContext(ctx).Raw(query, limit).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// MarkOutboxEventPublished implements OutboxRepositoryI.
+// The event takes the next publish sequence. Only the lease holder publishes, so the sequence
+// has no gaps and is committed in order; the unique index turns a stale relay racing it into
+// an error.
+func (o OutboxRepository) MarkOutboxEventPublished(ctx context.Context, id int64) (err error) {
+	now := time.Now()
+	updateOutbox := map[string]interface{}{
+		"attempts":      gorm.Expr("attempts + 1"),
+		"last_error":    "",
+		"published_at":  &now,
+		"published_seq": gorm.Expr(`(SELECT COALESCE(MAX(published_seq), 0) + 1 FROM ` + _db.OutboxTableName + `)`),
+	}
+
+	sql := o.conn.WithContext(ctx).Table(_db.OutboxTableName).Where("id = ?", id).Updates(updateOutbox)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// MarkOutboxEventFailed implements OutboxRepositoryI.
+func (o OutboxRepository) MarkOutboxEventFailed(ctx context.Context, id int64, lastError string) (err error) {
+	updateOutbox := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": lastError,
+	}
+
+	sql := o.conn.WithContext(ctx).Table(_db.OutboxTableName).Where("id = ?", id).Updates(updateOutbox)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// GetPublishedOutboxEventsAfter implements OutboxRepositoryI.
+// Events come in publish order, starting after afterSeq.
+func (o OutboxRepository) GetPublishedOutboxEventsAfter(ctx context.Context, afterSeq int64, aggregateTypes []string, limit int) (resp []outbox.OutboxEvent, err error) {
+	query := `
+		SELECT
+			id, aggregate_type, aggregate_id, event_type, payload, attempts, created_at, published_seq
+		FROM
+			` + _db.OutboxTableName + `
+		WHERE
+			published_seq > ?`
+	params := []interface{}{afterSeq}
+
+	if len(aggregateTypes) > 0 {
+		query += ` AND aggregate_type IN ?`
+		params = append(params, aggregateTypes)
+	}
+
+	query += ` ORDER BY published_seq ASC LIMIT ?`
+	params = append(params, limit)
+
+	sql := o.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// GetLastPublishedOutboxSeq implements OutboxRepositoryI.
+// It is 0 while nothing is published.
+func (o OutboxRepository) GetLastPublishedOutboxSeq(ctx context.Context) (seq int64, err error) {
+	query := `SELECT COALESCE(MAX(published_seq), 0) FROM ` + _db.OutboxTableName
+
+	sql := o.conn.WithContext(ctx).Raw(query).Scan(&seq)
+	if sql.Error != nil {
+		return seq, sql.Error
+	}
+
+	return seq, err
+}
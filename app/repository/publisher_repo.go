@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	_db "github.com/book-library/app/helper"
+	"github.com/book-library/entity/publisher"
+	"gorm.io/gorm"
+)
+
+type PublisherRepositoryI interface {
+	CreatePublisher(ctx context.Context, trx *gorm.DB, input publisher.PublisherInput) (id int64, err error)
+	GetAllPublishers(ctx context.Context, name string) (resp []publisher.PublisherResponse, err error)
+	GetPublisherById(ctx context.Context, trx *gorm.DB, id int64, name string) (resp publisher.PublisherResponse, err error)
+	UpdatePublisher(ctx context.Context, trx *gorm.DB, id int64, input publisher.PublisherInput) (err error)
+	DeletePublisher(ctx context.Context, trx *gorm.DB, id int64) error
+}
+
+type PublisherRepository struct {
+	conn *gorm.DB
+}
+
+func NewPublisherRepository(conn *gorm.DB) PublisherRepositoryI {
+	return PublisherRepository{conn: conn}
+}
+
+// CreatePublisher implements PublisherRepositoryI.
+func (c PublisherRepository) CreatePublisher(ctx context.Context, trx *gorm.DB, input publisher.PublisherInput) (id int64, err error) {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+	now := time.Now()
+
+	input.CreatedAt = now
+	input.UpdatedAt = nil
+
+	sql := trx.Table(_db.PublisherTableName).Create(&input)
+	if sql.Error != nil {
+		return id, sql.Error
+	}
+
+	return input.ID, nil
+}
+
+// DeletePublisher implements PublisherRepositoryI.
+func (c PublisherRepository) DeletePublisher(ctx context.Context, trx *gorm.DB, id int64) error {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
+	sql := trx.Table(_db.PublisherTableName).Where("id = ?", id).Delete(&publisher.PublisherInput{})
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// GetAllPublishers implements PublisherRepositoryI.
+func (c PublisherRepository) GetAllPublishers(ctx context.Context, name string) (resp []publisher.PublisherResponse, err error) {
+	query := `SELECT id, name, address, website, created_at, updated_at FROM ` + _db.PublisherTableName
+
+	params := []interface{}{}
+	if name != "" {
+		query += ` WHERE lower(name) ` + ilike(c.conn) + ` ?`
+		params = append(params, fmt.Sprintf("%%%s%%", strings.ToLower(name)))
+	}
+
+	query += ` ORDER BY id ASC`
+
+	sql := c.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// GetPublisherById implements PublisherRepositoryI.
+func (c PublisherRepository) GetPublisherById(ctx context.Context, trx *gorm.DB, id int64, name string) (resp publisher.PublisherResponse, err error) {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
+	params := []interface{}{}
+	where := []string{}
+	query := `SELECT id, name, address, website, created_at, updated_at FROM ` + _db.PublisherTableName
+
+	if id != 0 {
+		where = append(where, `id = ?`)
+		params = append(params, id)
+	}
+
+	if name != "" {
+		where = append(where, `lower(name) = ?`)
+		params = append(params, strings.ToLower(name))
+	}
+
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+
+	query += ` LIMIT 1`
+
+	sql := trx.Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// UpdatePublisher implements PublisherRepositoryI.
+func (c PublisherRepository) UpdatePublisher(ctx context.Context, trx *gorm.DB, id int64, input publisher.PublisherInput) (err error) {
+	if trx == nil {
+		trx = c.conn.WithContext(ctx)
+	}
+
+	now := time.Now()
+	updatePublisher := map[string]interface{}{
+		"name":       input.Name,
+		"address":    input.Address,
+		"website":    input.Website,
+		"updated_at": &now,
+	}
+
+	sql := trx.Table(_db.PublisherTableName).Where("id = ?", id).Updates(updatePublisher)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
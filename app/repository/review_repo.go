@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	_db "github.com/book-library/app/helper"
+	"github.com/book-library/entity/review"
+	"gorm.io/gorm"
+)
+
+type ReviewRepositoryI interface {
+	CreateReview(ctx context.Context, trx *gorm.DB, input review.ReviewInput) (err error)
+	GetAllReviews(ctx context.Context, search review.ReviewSearch) (resp []review.ReviewResponse, err error)
+	GetReviewById(ctx context.Context, id, bookID, memberID int64) (resp review.ReviewResponse, err error)
+	UpdateReview(ctx context.Context, trx *gorm.DB, id int64, input review.ReviewInput) (err error)
+	UpdateReviewStatus(ctx context.Context, trx *gorm.DB, id int64, status string) (err error)
+	DeleteReview(ctx context.Context, trx *gorm.DB, id int64) error
+}
+
+type ReviewRepository struct {
+	conn *gorm.DB
+}
+
+func NewReviewRepository(conn *gorm.DB) ReviewRepositoryI {
+	return ReviewRepository{conn: conn}
+}
+
+// CreateReview implements ReviewRepositoryI.
+func (r ReviewRepository) CreateReview(ctx context.Context, trx *gorm.DB, input review.ReviewInput) (err error) {
+	if trx == nil {
+		trx = r.conn.WithContext(ctx)
+	}
+
+	now := time.Now()
+
+	input.CreatedAt = now
+	input.UpdatedAt = nil
+
+	sql := trx.Table(_db.ReviewTableName).Create(&input)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// DeleteReview implements ReviewRepositoryI.
+func (r ReviewRepository) DeleteReview(ctx context.Context, trx *gorm.DB, id int64) error {
+	if trx == nil {
+		trx = r.conn.WithContext(ctx)
+	}
+
+	sql := trx.Table(_db.ReviewTableName).Where("id = ?", id).Delete(&review.ReviewInput{})
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// GetAllReviews implements ReviewRepositoryI.
+func (r ReviewRepository) GetAllReviews(ctx context.Context, search review.ReviewSearch) (resp []review.ReviewResponse, err error) {
+	query := `
+		SELECT
+			tbr.id, tbr.book_id, tbb.title as book_title, tbr.member_id, tbm.name as member_name,
+			tbr.rating, tbr.review, tbr.status, tbr.created_at, tbr.updated_at
+		FROM
+			tb_review tbr
+		JOIN
+			tb_book tbb on tbr.book_id = tbb.id
+		JOIN
+			tb_member tbm on tbr.member_id = tbm.id
+		WHERE
+			1 = 1
+	`
+
+	params := []interface{}{}
+	if search.BookID != 0 {
+		query += ` AND tbr.book_id = ?`
+		params = append(params, search.BookID)
+	}
+
+	if len(search.BookIDs) > 0 {
+		query += ` AND tbr.book_id IN ?`
+		params = append(params, search.BookIDs)
+	}
+
+	if search.MemberID != 0 {
+		query += ` AND tbr.member_id = ?`
+		params = append(params, search.MemberID)
+	}
+
+	if search.Status != "" {
+		query += ` AND tbr.status = ?`
+		params = append(params, search.Status)
+	}
+
+	query += ` ORDER BY tbr.id DESC`
+
+	sql := r.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// GetReviewById implements ReviewRepositoryI.
+func (r ReviewRepository) GetReviewById(ctx context.Context, id, bookID, memberID int64) (resp review.ReviewResponse, err error) {
+	query := `SELECT id, book_id, member_id, rating, review, status, created_at, updated_at FROM ` + _db.ReviewTableName + ` WHERE 1 = 1`
+
+	params := []interface{}{}
+	if id != 0 {
+		query += ` AND id = ?`
+		params = append(params, id)
+	}
+
+	if bookID != 0 {
+		query += ` AND book_id = ?`
+		params = append(params, bookID)
+	}
+
+	if memberID != 0 {
+		query += ` AND member_id = ?`
+		params = append(params, memberID)
+	}
+
+	query += ` LIMIT 1`
+
+	sql := r.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// UpdateReview implements ReviewRepositoryI.
+func (r ReviewRepository) UpdateReview(ctx context.Context, trx *gorm.DB, id int64, input review.ReviewInput) (err error) {
+	if trx == nil {
+		trx = r.conn.WithContext(ctx)
+	}
+
+	now := time.Now()
+	updateReview := map[string]interface{}{
+		"rating":     input.Rating,
+		"review":     input.Review,
+		"status":     input.Status,
+		"updated_at": &now,
+	}
+
+	sql := trx.Table(_db.ReviewTableName).Where("id = ?", id).Updates(updateReview)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// UpdateReviewStatus implements ReviewRepositoryI.
+func (r ReviewRepository) UpdateReviewStatus(ctx context.Context, trx *gorm.DB, id int64, status string) (err error) {
+	if trx == nil {
+		trx = r.conn.WithContext(ctx)
+	}
+
+	now := time.Now()
+	updateReview := map[string]interface{}{
+		"status":     status,
+		"updated_at": &now,
+	}
+
+	sql := trx.Table(_db.ReviewTableName).Where("id = ?", id).Updates(updateReview)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	_db "github.com/book-library/app/helper"
+	"github.com/book-library/entity/series"
+	"gorm.io/gorm"
+)
+
+type SeriesRepositoryI interface {
+	CreateSeries(ctx context.Context, trx *gorm.DB, input series.SeriesInput) (id int64, err error)
+	GetAllSeries(ctx context.Context, name string) (resp []series.SeriesResponse, err error)
+	GetSeriesById(ctx context.Context, trx *gorm.DB, id int64, name string) (resp series.SeriesResponse, err error)
+	UpdateSeries(ctx context.Context, trx *gorm.DB, id int64, input series.SeriesInput) (err error)
+	DeleteSeries(ctx context.Context, trx *gorm.DB, id int64) error
+	GetSeriesVolumes(ctx context.Context, id int64) (resp []series.SeriesVolume, err error)
+}
+
+type SeriesRepository struct {
+	conn *gorm.DB
+}
+
+func NewSeriesRepository(conn *gorm.DB) SeriesRepositoryI {
+	return SeriesRepository{conn: conn}
+}
+
+// CreateSeries implements SeriesRepositoryI.
+func (s SeriesRepository) CreateSeries(ctx context.Context, trx *gorm.DB, input series.SeriesInput) (id int64, err error) {
+	if trx == nil {
+		trx = s.conn.WithContext(ctx)
+	}
+	now := time.Now()
+
+	input.CreatedAt = now
+	input.UpdatedAt = nil
+
+	sql := trx.Table(_db.SeriesTableName).Create(&input)
+	if sql.Error != nil {
+		return id, sql.Error
+	}
+
+	return input.ID, nil
+}
+
+// DeleteSeries implements SeriesRepositoryI.
+func (s SeriesRepository) DeleteSeries(ctx context.Context, trx *gorm.DB, id int64) error {
+	if trx == nil {
+		trx = s.conn.WithContext(ctx)
+	}
+
+	sql := trx.Table(_db.SeriesTableName).Where("id = ?", id).Delete(&series.SeriesInput{})
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// GetAllSeries implements SeriesRepositoryI.
+func (s SeriesRepository) GetAllSeries(ctx context.Context, name string) (resp []series.SeriesResponse, err error) {
+	query := `SELECT id, name, description, created_at, updated_at FROM ` + _db.SeriesTableName
+
+	params := []interface{}{}
+	if name != "" {
+		query += ` WHERE lower(name) ` + ilike(s.conn) + ` ?`
+		params = append(params, fmt.Sprintf("%%%s%%", strings.ToLower(name)))
+	}
+
+	query += ` ORDER BY id ASC`
+
+	sql := s.conn.WithContext(ctx).Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// GetSeriesById implements SeriesRepositoryI.
+func (s SeriesRepository) GetSeriesById(ctx context.Context, trx *gorm.DB, id int64, name string) (resp series.SeriesResponse, err error) {
+	if trx == nil {
+		trx = s.conn.WithContext(ctx)
+	}
+
+	params := []interface{}{}
+	where := []string{}
+	query := `SELECT id, name, description, created_at, updated_at FROM ` + _db.SeriesTableName
+
+	if id != 0 {
+		where = append(where, `id = ?`)
+		params = append(params, id)
+	}
+
+	if name != "" {
+		where = append(where, `lower(name) = ?`)
+		params = append(params, strings.ToLower(name))
+	}
+
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+
+	query += ` LIMIT 1`
+
+	sql := trx.Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// UpdateSeries implements SeriesRepositoryI.
+func (s SeriesRepository) UpdateSeries(ctx context.Context, trx *gorm.DB, id int64, input series.SeriesInput) (err error) {
+	if trx == nil {
+		trx = s.conn.WithContext(ctx)
+	}
+
+	now := time.Now()
+	updateSeries := map[string]interface{}{
+		"name":        input.Name,
+		"description": input.Description,
+		"updated_at":  &now,
+	}
+
+	sql := trx.Table(_db.SeriesTableName).Where("id = ?", id).Updates(updateSeries)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// GetSeriesVolumes implements SeriesRepositoryI.
+func (s SeriesRepository) GetSeriesVolumes(ctx context.Context, id int64) (resp []series.SeriesVolume, err error) {
+	query := `
+		SELECT
+			tbb.id as book_id, tbb.title, tbb.isbn, tbb.series_volume as volume, tbb.publication_year, tbb.published_flag
+		FROM
+			tb_book tbb
+		WHERE
+			tbb.series_id = ?
+		ORDER BY
+			tbb.series_volume ASC, tbb.id ASC
+	`
+
+	sql := s.conn.WithContext(ctx).Raw(query, id).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
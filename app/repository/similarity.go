@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// withSimilarityThreshold runs fn with the pg_trgm % operator matching at threshold. The
+// setting is local to a transaction, so fn gets one when threshold is set and the plain
+// connection otherwise. Matching with % rather than comparing similarity() keeps the trigram
+// indexes usable. SQLite has no setting, trigramMatch puts the threshold in the query there.
+func withSimilarityThreshold(ctx context.Context, conn *gorm.DB, threshold float64, fn func(db *gorm.DB) error) error {
+	if threshold <= 0 || isSQLite(conn) {
+		return fn(conn.WithContext(ctx))
+	}
+
+	return conn.WithContext(ctx).Transaction(func(trx *gorm.DB) error {
+		err := trx.Exec(`SELECT set_config('pg_trgm.similarity_threshold', ?, true)`, strconv.FormatFloat(threshold, 'f', -1, 64)).Error
+		if err != nil {
+			return err
+		}
+
+		return fn(trx)
+	})
+}
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/book-library/entity/author"
+	"github.com/book-library/entity/book"
+	"github.com/book-library/entity/category"
+	"github.com/book-library/migration"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// newTestSQLite opens a migrated SQLite file with the DSN options the server uses.
+func newTestSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.New(sqlite.Config{
+		DriverName: SQLiteDriverName,
+		DSN:        "file:" + filepath.Join(t.TempDir(), "library.db") + "?_foreign_keys=on&_busy_timeout=5000&_txlock=immediate",
+	}), &gorm.Config{Logger: gormLogger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	if err = migration.Migrate(db); err != nil {
+		t.Fatalf("migration.Migrate: %v", err)
+	}
+
+	return db
+}
+
+func TestSQLiteDialect(t *testing.T) {
+	db := newTestSQLite(t)
+
+	if !isSQLite(db) {
+		t.Fatalf("isSQLite = false for dialect %s", db.Dialector.Name())
+	}
+
+	if dialect := NewHealthRepository(db).Dialect(); dialect != DriverSQLite {
+		t.Errorf("Dialect = %s, want %s", dialect, DriverSQLite)
+	}
+
+	var largest int
+	if err := db.Raw(`SELECT ` + greatest(db) + `(1, 3, 2)`).Scan(&largest).Error; err != nil || largest != 3 {
+		t.Errorf("%s(1, 3, 2) = %d, %v, want 3", greatest(db), largest, err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		pattern string
+		matches bool
+	}{
+		{name: "underscore is literal", value: "978_1", pattern: escapeLike("978_") + "%", matches: true},
+		{name: "underscore matches no other character", value: "97801", pattern: escapeLike("978_") + "%", matches: false},
+		{name: "percent is literal", value: "50% off", pattern: "%" + escapeLike("50%") + "%", matches: true},
+		{name: "percent matches no other text", value: "500 days", pattern: "%" + escapeLike("50%") + "%", matches: false},
+		{name: "backslash is literal", value: `a\b`, pattern: escapeLike(`a\b`), matches: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var matches bool
+			err := db.Raw(`SELECT ? LIKE ?`+likeEscape(db), tt.value, tt.pattern).Scan(&matches).Error
+			if err != nil || matches != tt.matches {
+				t.Errorf("%q LIKE %q = %v, %v, want %v", tt.value, tt.pattern, matches, err, tt.matches)
+			}
+		})
+	}
+
+	condition, params := trigramMatch(db, "?", "Ann Leckie", 0.3)
+	for value, want := range map[string]bool{"Ann Lecke": true, "Ursula K. Le Guin": false} {
+		var matches bool
+		err := db.Raw(`SELECT `+condition, append([]interface{}{value}, params...)...).Scan(&matches).Error
+		if err != nil || matches != want {
+			t.Errorf("trigram match of %q = %v, %v, want %v", value, matches, err, want)
+		}
+	}
+
+	err := db.Transaction(func(trx *gorm.DB) error {
+		return lockForUpdate(trx, "tb_book", 1)
+	})
+	if err != nil {
+		t.Errorf("lockForUpdate: %v", err)
+	}
+
+	if clause := skipLocked(db); clause != "" {
+		t.Errorf("skipLocked = %q, want none on SQLite", clause)
+	}
+}
+
+func TestSQLiteAuthorRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAuthorRepository(newTestSQLite(t))
+
+	for _, input := range []author.AuthorInput{
+		{Name: "Ann Leckie", Email: "Ann@Example.com"},
+		{Name: "Ursula K. Le Guin", Email: "ursula@example.com"},
+	} {
+		if _, err := repo.CreateAuthor(ctx, nil, input); err != nil {
+			t.Fatalf("CreateAuthor: %v", err)
+		}
+	}
+
+	byEmail, err := repo.GetAuthorById(ctx, nil, 0, "ann@example.com")
+	if err != nil || byEmail.Name != "Ann Leckie" {
+		t.Fatalf("GetAuthorById by email = %+v, %v, want Ann Leckie", byEmail, err)
+	}
+
+	tests := []struct {
+		name       string
+		search     string
+		similarity float64
+		names      []string
+	}{
+		{name: "all", names: []string{"Ann Leckie", "Ursula K. Le Guin"}},
+		{name: "substring ignores case", search: "LE GUIN", names: []string{"Ursula K. Le Guin"}},
+		{name: "trigram", search: "Ann Lecke", similarity: 0.3, names: []string{"Ann Leckie"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authors, err := repo.GetAllAuthors(ctx, tt.search, tt.similarity)
+			if err != nil {
+				t.Fatalf("GetAllAuthors: %v", err)
+			}
+
+			names := []string{}
+			for _, a := range authors {
+				names = append(names, a.Name)
+			}
+
+			if !reflect.DeepEqual(names, tt.names) {
+				t.Errorf("names = %v, want %v", names, tt.names)
+			}
+		})
+	}
+
+	if err = repo.UpdateAuthor(ctx, nil, byEmail.ID, author.AuthorInput{Name: "Ann Leckie (Imperial Radch)"}, "name"); err != nil {
+		t.Fatalf("UpdateAuthor: %v", err)
+	}
+
+	updated, _ := repo.GetAuthorById(ctx, nil, byEmail.ID, "")
+	if updated.Name != "Ann Leckie (Imperial Radch)" || updated.Email != "Ann@Example.com" || updated.UpdatedAt == nil {
+		t.Errorf("updated author = %+v, want only the name changed", updated)
+	}
+
+	if err = repo.DeleteAuthor(ctx, nil, byEmail.ID); err != nil {
+		t.Fatalf("DeleteAuthor: %v", err)
+	}
+
+	if deleted, _ := repo.GetAuthorById(ctx, nil, byEmail.ID, ""); deleted.ID != 0 {
+		t.Errorf("GetAuthorById after DeleteAuthor = %+v, want none", deleted)
+	}
+}
+
+func TestSQLiteCategoryRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCategoryRepository(newTestSQLite(t))
+
+	for _, input := range []category.CategoryInput{
+		{Name: "Science Fiction", Description: "Spaceships"},
+		{Name: "Fantasy", Description: "Dragons"},
+	} {
+		if _, err := repo.CreateCategory(ctx, nil, input); err != nil {
+			t.Fatalf("CreateCategory: %v", err)
+		}
+	}
+
+	byName, err := repo.GetCategoryById(ctx, nil, 0, "science fiction")
+	if err != nil || byName.Description != "Spaceships" {
+		t.Fatalf("GetCategoryById by name = %+v, %v, want Science Fiction", byName, err)
+	}
+
+	if substring, _ := repo.GetAllCategories(ctx, "FANT", 0); len(substring) != 1 || substring[0].Name != "Fantasy" {
+		t.Errorf("GetAllCategories(FANT) = %+v, want Fantasy", substring)
+	}
+
+	if fuzzy, _ := repo.GetAllCategories(ctx, "Sience Fiction", 0.3); len(fuzzy) != 1 || fuzzy[0].Name != "Science Fiction" {
+		t.Errorf("GetAllCategories(Sience Fiction, 0.3) = %+v, want Science Fiction", fuzzy)
+	}
+
+	trx := newTestTransaction(t, repo.(CategoryRepository).conn)
+	if err = repo.UpdateCategory(ctx, trx, byName.ID, category.CategoryInput{Name: "SF", Description: "Spaceships"}); err != nil {
+		t.Fatalf("UpdateCategory: %v", err)
+	}
+
+	if inside, _ := repo.GetCategoryById(ctx, trx, byName.ID, ""); inside.Name != "SF" {
+		t.Errorf("name read in the transaction = %s, want its own update", inside.Name)
+	}
+	trx.Rollback()
+
+	if outside, _ := repo.GetCategoryById(ctx, nil, byName.ID, ""); outside.Name != "Science Fiction" {
+		t.Errorf("name after the rollback = %s, want Science Fiction", outside.Name)
+	}
+}
+
+func TestSQLiteBookRepository(t *testing.T) {
+	ctx := context.Background()
+	db := newTestSQLite(t)
+	repo := NewBookLibraryRepository(db)
+
+	err := db.Exec(`INSERT INTO tb_author (name, email) VALUES ('Ann Leckie', 'ann@example.com');
+		INSERT INTO tb_category (name) VALUES ('Science Fiction');
+		INSERT INTO tb_member (name, email) VALUES ('Reader', 'reader@example.com'), ('Critic', 'critic@example.com');`).Error
+	if err != nil {
+		t.Fatalf("seeding: %v", err)
+	}
+
+	published := true
+	for _, input := range []book.BookInput{
+		{Title: "Ancillary Justice", ISBN: "978_0316246620"},
+		{Title: "Ancillary Sword", ISBN: "9780316246637"},
+		{Title: "50% of Provenance", ISBN: "9780316388672"},
+		{Title: "500 Translation State", ISBN: "9780316289719"},
+	} {
+		input.AuthorID, input.CategoryID, input.PublishedFlag = 1, 1, &published
+		if _, err := repo.CreateBookLibrary(ctx, nil, input); err != nil {
+			t.Fatalf("CreateBookLibrary: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		search book.BookSearch
+		titles []string
+	}{
+		{name: "isbn prefix with an underscore", search: book.BookSearch{ISBNPrefix: "978_"}, titles: []string{"Ancillary Justice"}},
+		{name: "title with a percent", search: book.BookSearch{TitleContains: "50%"}, titles: []string{"50% of Provenance"}},
+		{name: "title ignores case", search: book.BookSearch{TitleContains: "SWORD"}, titles: []string{"Ancillary Sword"}},
+		{name: "trigram best match first", search: book.BookSearch{Search: "Ancilary Sword", Similarity: 0.3}, titles: []string{"Ancillary Sword", "Ancillary Justice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			books, err := repo.GetAllBookLibraries(ctx, tt.search)
+			if err != nil {
+				t.Fatalf("GetAllBookLibraries: %v", err)
+			}
+
+			titles := []string{}
+			for _, b := range books {
+				titles = append(titles, b.Title)
+			}
+
+			if !reflect.DeepEqual(titles, tt.titles) {
+				t.Errorf("titles = %v, want %v", titles, tt.titles)
+			}
+		})
+	}
+
+	err = db.Exec(`INSERT INTO tb_review (book_id, member_id, rating, status) VALUES (1, 1, 5, 'approved'), (1, 2, 2, 'approved')`).Error
+	if err != nil {
+		t.Fatalf("seeding reviews: %v", err)
+	}
+
+	trx := newTestTransaction(t, db)
+	if err = repo.UpdateBookRating(ctx, trx, 1); err != nil {
+		t.Fatalf("UpdateBookRating: %v", err)
+	}
+	trx.Commit()
+
+	rated, err := repo.GetBookLibraryById(ctx, nil, 1, 0, 0, 0)
+	if err != nil || rated.RatingAverage != 3.5 || rated.RatingCount != 2 {
+		t.Errorf("rating = %v over %d, %v, want 3.5 over 2", rated.RatingAverage, rated.RatingCount, err)
+	}
+
+	if err = repo.DeleteBookLibrary(ctx, nil, 1); err != nil {
+		t.Fatalf("DeleteBookLibrary: %v", err)
+	}
+
+	if total, _ := repo.CountBooks(ctx); total != 3 {
+		t.Errorf("CountBooks after a delete = %d, want 3", total)
+	}
+}
+
+func newTestTransaction(t *testing.T, db *gorm.DB) *gorm.DB {
+	t.Helper()
+
+	trx := NewTransactionRepository(db).BeginTransaction(context.Background())
+	if trx.Error != nil {
+		t.Fatalf("BeginTransaction: %v", trx.Error)
+	}
+
+	return trx
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	_db "github.com/book-library/app/helper"
+	"github.com/book-library/entity/suggest"
+	"gorm.io/gorm"
+)
+
+type SuggestRepositoryI interface {
+	GetSuggestions(ctx context.Context, prefix string, limit int) (resp []suggest.Suggestion, err error)
+}
+
+type SuggestRepository struct {
+	conn *gorm.DB
+}
+
+func NewSuggestRepository(conn *gorm.DB) SuggestRepositoryI {
+	return SuggestRepository{conn: conn}
+}
+
+// GetSuggestions implements SuggestRepositoryI.
+// It returns up to limit published book titles, author names and category names starting with
+// prefix, ignoring case. Each part walks a lower(...) index in order (text_pattern_ops on
+// Postgres), so the query stays cheap however large the tables get.
+func (s SuggestRepository) GetSuggestions(ctx context.Context, prefix string, limit int) (resp []suggest.Suggestion, err error) {
+	pattern := escapeLike(strings.ToLower(prefix)) + "%"
+	like := `LIKE ?` + likeEscape(s.conn)
+
+	query := `
+		SELECT * FROM (SELECT '` + suggest.TypeBook + `' as type, id, title as label FROM ` + _db.BookTableName + `
+			WHERE published_flag = true AND lower(title) ` + like + ` ORDER BY lower(title) LIMIT ?) books
+		UNION ALL
+		SELECT * FROM (SELECT '` + suggest.TypeAuthor + `' as type, id, name as label FROM ` + _db.AuthorTableName + `
+			WHERE lower(name) ` + like + ` ORDER BY lower(name) LIMIT ?) authors
+		UNION ALL
+		SELECT * FROM (SELECT '` + suggest.TypeCategory + `' as type, id, name as label FROM ` + _db.CategoryTableName + `
+			WHERE lower(name) ` + like + ` ORDER BY lower(name) LIMIT ?) categories
+	`
+
+	sql := s.conn.WithContext(ctx).Raw(query, pattern, limit, pattern, limit, pattern, limit).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
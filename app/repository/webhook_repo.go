@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	_db "github.com/book-library/app/helper"
+	"github.com/book-library/entity/webhook"
+	"gorm.io/gorm"
+)
+
+type WebhookRepositoryI interface {
+	CreateWebhook(ctx context.Context, trx *gorm.DB, input webhook.WebhookInput) (err error)
+	GetAllWebhooks(ctx context.Context) (resp []webhook.WebhookResponse, err error)
+	GetWebhookById(ctx context.Context, id int64) (resp webhook.WebhookSubscription, err error)
+	GetWebhooksByEvent(ctx context.Context, eventType string) (resp []webhook.WebhookSubscription, err error)
+	UpdateWebhook(ctx context.Context, trx *gorm.DB, id int64, input webhook.WebhookInput) (err error)
+	DeleteWebhook(ctx context.Context, trx *gorm.DB, id int64) error
+
+	CreateWebhookDelivery(ctx context.Context, input webhook.WebhookDeliveryInput) (id int64, err error)
+	UpdateWebhookDelivery(ctx context.Context, input webhook.WebhookDeliveryInput) (err error)
+	ClaimDueWebhookDeliveries(ctx context.Context, lease time.Duration, limit int) (resp []webhook.WebhookDeliveryInput, err error)
+	GetWebhookDeliveries(ctx context.Context, webhookID int64) (resp []webhook.WebhookDeliveryResponse, err error)
+}
+
+type WebhookRepository struct {
+	conn *gorm.DB
+}
+
+// webhookRow is how a subscription is stored. Event types are kept as ",a,b," so
+// a single LIKE finds the subscribers of an event.
+type webhookRow struct {
+	ID         int64
+	TargetURL  string
+	Secret     string
+	EventTypes string
+	ActiveFlag bool
+	CreatedAt  time.Time
+	UpdatedAt  *time.Time
+}
+
+func NewWebhookRepository(conn *gorm.DB) WebhookRepositoryI {
+	return WebhookRepository{conn: conn}
+}
+
+func joinEventTypes(eventTypes []string) string {
+	return "," + strings.Join(eventTypes, ",") + ","
+}
+
+func splitEventTypes(eventTypes string) []string {
+	return strings.Split(strings.Trim(eventTypes, ","), ",")
+}
+
+func (row webhookRow) subscription() webhook.WebhookSubscription {
+	return webhook.WebhookSubscription{
+		ID:         row.ID,
+		TargetURL:  row.TargetURL,
+		Secret:     row.Secret,
+		EventTypes: splitEventTypes(row.EventTypes),
+		ActiveFlag: row.ActiveFlag,
+	}
+}
+
+// CreateWebhook implements WebhookRepositoryI.
+func (w WebhookRepository) CreateWebhook(ctx context.Context, trx *gorm.DB, input webhook.WebhookInput) (err error) {
+	if trx == nil {
+		trx = w.conn.WithContext(ctx)
+	}
+
+	createWebhook := map[string]interface{}{
+		"target_url":  input.TargetURL,
+		"secret":      input.Secret,
+		"event_types": joinEventTypes(input.EventTypes),
+		"active_flag": input.ActiveFlag,
+		"created_at":  time.Now(),
+	}
+
+	sql := trx.Table(_db.WebhookTableName).Create(createWebhook)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// DeleteWebhook implements WebhookRepositoryI.
+func (w WebhookRepository) DeleteWebhook(ctx context.Context, trx *gorm.DB, id int64) error {
+	if trx == nil {
+		trx = w.conn.WithContext(ctx)
+	}
+
+	sql := trx.Table(_db.WebhookTableName).Where("id = ?", id).Delete(&webhookRow{})
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// GetAllWebhooks implements WebhookRepositoryI.
+func (w WebhookRepository) GetAllWebhooks(ctx context.Context) (resp []webhook.WebhookResponse, err error) {
+	rows := []webhookRow{}
+	query := `SELECT id, target_url, secret, event_types, active_flag, created_at, updated_at FROM ` + _db.WebhookTableName + ` ORDER BY id ASC`
+
+	sql := w.conn.WithContext(ctx).Raw(query).Scan(&rows)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	resp = []webhook.WebhookResponse{}
+	for _, row := range rows {
+		resp = append(resp, webhook.WebhookResponse{
+			ID:         row.ID,
+			TargetURL:  row.TargetURL,
+			EventTypes: splitEventTypes(row.EventTypes),
+			ActiveFlag: row.ActiveFlag,
+			CreatedAt:  row.CreatedAt,
+			UpdatedAt:  row.UpdatedAt,
+		})
+	}
+
+	return resp, err
+}
+
+// GetWebhookById implements WebhookRepositoryI.
+func (w WebhookRepository) GetWebhookById(ctx context.Context, id int64) (resp webhook.WebhookSubscription, err error) {
+	row := webhookRow{}
+	query := `SELECT id, target_url, secret, event_types, active_flag FROM ` + _db.WebhookTableName + ` WHERE id = ? LIMIT 1`
+
+	sql := w.conn.WithContext(ctx).Raw(query, id).Scan(&row)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	if row.ID == 0 {
+		return resp, err
+	}
+
+	return row.subscription(), err
+}
+
+// GetWebhooksByEvent implements WebhookRepositoryI.
+func (w WebhookRepository) GetWebhooksByEvent(ctx context.Context, eventType string) (resp []webhook.WebhookSubscription, err error) {
+	rows := []webhookRow{}
+	query := `SELECT id, target_url, secret, event_types, active_flag FROM ` + _db.WebhookTableName + ` WHERE active_flag = true AND event_types LIKE ? ORDER BY id ASC`
+
+	sql := w.conn.WithContext(ctx).Raw(query, "%,"+eventType+",%").Scan(&rows)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	for _, row := range rows {
+		resp = append(resp, row.subscription())
+	}
+
+	return resp, err
+}
+
+// UpdateWebhook implements WebhookRepositoryI.
+func (w WebhookRepository) UpdateWebhook(ctx context.Context, trx *gorm.DB, id int64, input webhook.WebhookInput) (err error) {
+	if trx == nil {
+		trx = w.conn.WithContext(ctx)
+	}
+
+	now := time.Now()
+	updateWebhook := map[string]interface{}{
+		"target_url":  input.TargetURL,
+		"secret":      input.Secret,
+		"event_types": joinEventTypes(input.EventTypes),
+		"active_flag": input.ActiveFlag,
+		"updated_at":  &now,
+	}
+
+	sql := trx.Table(_db.WebhookTableName).Where("id = ?", id).Updates(updateWebhook)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// CreateWebhookDelivery implements WebhookRepositoryI.
+func (w WebhookRepository) CreateWebhookDelivery(ctx context.Context, input webhook.WebhookDeliveryInput) (id int64, err error) {
+	input.CreatedAt = time.Now()
+
+	sql := w.conn.WithContext(ctx).Table(_db.WebhookDeliveryTableName).Create(&input)
+	if sql.Error != nil {
+		return id, sql.Error
+	}
+
+	return input.ID, nil
+}
+
+// UpdateWebhookDelivery implements WebhookRepositoryI.
+func (w WebhookRepository) UpdateWebhookDelivery(ctx context.Context, input webhook.WebhookDeliveryInput) (err error) {
+	updateDelivery := map[string]interface{}{
+		"status":          input.Status,
+		"attempts":        input.Attempts,
+		"response_code":   input.ResponseCode,
+		"last_error":      input.LastError,
+		"delivered_at":    input.DeliveredAt,
+		"next_attempt_at": input.NextAttemptAt,
+	}
+
+	sql := w.conn.WithContext(ctx).Table(_db.WebhookDeliveryTableName).Where("id = ?", input.ID).Updates(updateDelivery)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// GetWebhookDeliveries implements WebhookRepositoryI.
+func (w WebhookRepository) GetWebhookDeliveries(ctx context.Context, webhookID int64) (resp []webhook.WebhookDeliveryResponse, err error) {
+	query := `
+		SELECT
+			id, webhook_id, event_id, event_type, payload, status, attempts, response_code, last_error, created_at, delivered_at, next_attempt_at
+		FROM
+			` + _db.WebhookDeliveryTableName + `
+		WHERE
+			webhook_id = ?
+		ORDER BY
+			id DESC
+		LIMIT 100
+	`
+
+	sql := w.conn.WithContext(ctx).Raw(query, webhookID).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// ClaimDueWebhookDeliveries implements WebhookRepositoryI.
+// The pending deliveries whose next attempt is due are pushed lease into the future before they
+// are returned, so no other worker claims them while they are being sent. A worker that dies
+// mid-send leaves them to be claimed again once the lease runs out.
+func (w WebhookRepository) ClaimDueWebhookDeliveries(ctx context.Context, lease time.Duration, limit int) (resp []webhook.WebhookDeliveryInput, err error) {
+	now := time.Now()
+
+	err = w.conn.WithContext(ctx).Transaction(func(trx *gorm.DB) error {
+		query := `
+			SELECT
+				id, webhook_id, event_id, event_type, payload, status, attempts, response_code, last_error, created_at, delivered_at, next_attempt_at
+			FROM
+				` + _db.WebhookDeliveryTableName + `
+			WHERE
+				status = ? AND next_attempt_at <= ?
+			ORDER BY
+				next_attempt_at ASC, id ASC
+			LIMIT ?` + skipLocked(trx)
+
+		if err := trx.Raw(query, webhook.DeliveryStatusPending, now, limit).Scan(&resp).Error; err != nil {
+			return err
+		}
+
+		if len(resp) == 0 {
+			return nil
+		}
+
+		ids := make([]int64, 0, len(resp))
+		for _, delivery := range resp {
+			ids = append(ids, delivery.ID)
+		}
+
+		return trx.Table(_db.WebhookDeliveryTableName).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+
+	return resp, err
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	_db "github.com/book-library/app/helper"
+	"github.com/book-library/entity/work"
+	"gorm.io/gorm"
+)
+
+type WorkRepositoryI interface {
+	CreateWork(ctx context.Context, trx *gorm.DB, input work.WorkInput) (id int64, err error)
+	GetAllWorks(ctx context.Context, trx *gorm.DB, title string) (resp []work.WorkResponse, err error)
+	GetWorkById(ctx context.Context, trx *gorm.DB, id int64) (resp work.WorkResponse, err error)
+	UpdateWork(ctx context.Context, trx *gorm.DB, id int64, input work.WorkInput) (err error)
+	DeleteWork(ctx context.Context, trx *gorm.DB, id int64) error
+	GetWorkEditions(ctx context.Context, trx *gorm.DB, id int64) (resp []work.WorkEdition, err error)
+}
+
+type WorkRepository struct {
+	conn *gorm.DB
+}
+
+func NewWorkRepository(conn *gorm.DB) WorkRepositoryI {
+	return WorkRepository{conn: conn}
+}
+
+// CreateWork implements WorkRepositoryI.
+func (w WorkRepository) CreateWork(ctx context.Context, trx *gorm.DB, input work.WorkInput) (id int64, err error) {
+	if trx == nil {
+		trx = w.conn.WithContext(ctx)
+	}
+	now := time.Now()
+
+	input.CreatedAt = now
+	input.UpdatedAt = nil
+
+	sql := trx.Table(_db.WorkTableName).Create(&input)
+	if sql.Error != nil {
+		return id, sql.Error
+	}
+
+	return input.ID, nil
+}
+
+// DeleteWork implements WorkRepositoryI.
+func (w WorkRepository) DeleteWork(ctx context.Context, trx *gorm.DB, id int64) error {
+	if trx == nil {
+		trx = w.conn.WithContext(ctx)
+	}
+
+	sql := trx.Table(_db.WorkTableName).Where("id = ?", id).Delete(&work.WorkInput{})
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return nil
+}
+
+// GetAllWorks implements WorkRepositoryI.
+func (w WorkRepository) GetAllWorks(ctx context.Context, trx *gorm.DB, title string) (resp []work.WorkResponse, err error) {
+	if trx == nil {
+		trx = w.conn.WithContext(ctx)
+	}
+
+	query := `SELECT id, title, description, author_id, category_id, original_language, first_publication_year, created_at, updated_at FROM ` + _db.WorkTableName
+
+	params := []interface{}{}
+	if title != "" {
+		query += ` WHERE lower(title) ` + ilike(w.conn) + ` ?`
+		params = append(params, fmt.Sprintf("%%%s%%", strings.ToLower(title)))
+	}
+
+	query += ` ORDER BY id ASC`
+
+	sql := trx.Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// GetWorkById implements WorkRepositoryI.
+func (w WorkRepository) GetWorkById(ctx context.Context, trx *gorm.DB, id int64) (resp work.WorkResponse, err error) {
+	if trx == nil {
+		trx = w.conn.WithContext(ctx)
+	}
+
+	params := []interface{}{}
+	query := `SELECT id, title, description, author_id, category_id, original_language, first_publication_year, created_at, updated_at FROM ` + _db.WorkTableName
+
+	if id != 0 {
+		query += ` WHERE id = ?`
+		params = append(params, id)
+	}
+
+	query += ` LIMIT 1`
+
+	sql := trx.Raw(query, params...).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
+
+// UpdateWork implements WorkRepositoryI.
+func (w WorkRepository) UpdateWork(ctx context.Context, trx *gorm.DB, id int64, input work.WorkInput) (err error) {
+	if trx == nil {
+		trx = w.conn.WithContext(ctx)
+	}
+
+	now := time.Now()
+	updateWork := map[string]interface{}{
+		"title":                  input.Title,
+		"description":            input.Description,
+		"author_id":              input.AuthorID,
+		"category_id":            input.CategoryID,
+		"original_language":      input.OriginalLanguage,
+		"first_publication_year": input.FirstPublicationYear,
+		"updated_at":             &now,
+	}
+
+	sql := trx.Table(_db.WorkTableName).Where("id = ?", id).Updates(updateWork)
+	if sql.Error != nil {
+		return sql.Error
+	}
+
+	return err
+}
+
+// GetWorkEditions implements WorkRepositoryI.
+func (w WorkRepository) GetWorkEditions(ctx context.Context, trx *gorm.DB, id int64) (resp []work.WorkEdition, err error) {
+	if trx == nil {
+		trx = w.conn.WithContext(ctx)
+	}
+
+	query := `
+		SELECT
+			tbb.id as book_id, tbb.title, tbb.isbn, tbb.edition, tbb.language, tbb.publication_year, tbb.published_flag
+		FROM
+			tb_book tbb
+		WHERE
+			tbb.work_id = ?
+		ORDER BY
+			tbb.publication_year ASC, tbb.id ASC
+	`
+
+	sql := trx.Raw(query, id).Scan(&resp)
+	if sql.Error != nil {
+		return resp, sql.Error
+	}
+
+	return resp, err
+}
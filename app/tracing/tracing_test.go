@@ -0,0 +1,128 @@
+package tracing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/book-library/app/delivery"
+	_http "github.com/book-library/app/http"
+	"github.com/book-library/app/repository"
+	"github.com/book-library/app/tracing"
+	"github.com/book-library/app/usecase"
+	"github.com/book-library/migration"
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+func TestRequestSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracing.Install(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	db, err := gorm.Open(sqlite.New(sqlite.Config{
+		DriverName: repository.SQLiteDriverName,
+		DSN:        "file:" + filepath.Join(t.TempDir(), "library.db") + "?_foreign_keys=on",
+	}), &gorm.Config{Logger: gormLogger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	if err = migration.Migrate(db); err != nil {
+		t.Fatalf("migration.Migrate: %v", err)
+	}
+
+	err = db.Exec(`INSERT INTO tb_author (name, email) VALUES ('Ann Leckie', 'ann@example.com');
+		INSERT INTO tb_category (name) VALUES ('Science Fiction');
+		INSERT INTO tb_book (title, author_id, isbn, category_id) VALUES ('Ancillary Justice', 1, '9780316246620', 1);`).Error
+	if err != nil {
+		t.Fatalf("seeding: %v", err)
+	}
+
+	if err = db.Use(tracing.GormPlugin{}); err != nil {
+		t.Fatalf("db.Use: %v", err)
+	}
+
+	bookUC := usecase.NewbookLibraryService(repository.NewBookLibraryRepository(db), repository.NewTransactionRepository(db),
+		repository.NewAuthorRepository(db), repository.NewCategoryRepository(db), repository.NewPublisherRepository(db),
+		repository.NewSeriesRepository(db), repository.NewWorkRepository(db), repository.NewOutboxRepository(db))
+
+	r := chi.NewRouter()
+	r.Use(tracing.Middleware)
+	_http.BookPath(r, delivery.NewBookHandler(bookUC))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/book/1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/v1/book/1 = %d: %s", rec.Code, rec.Body)
+	}
+
+	spans := exporter.GetSpans()
+	byName := map[string]tracetest.SpanStub{}
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+
+	server, ok := byName["GET /api/v1/book/{id}"]
+	if !ok {
+		t.Fatalf("no span for the route among %v", spanNames(spans))
+	}
+
+	if server.SpanKind != trace.SpanKindServer || server.Parent.IsValid() {
+		t.Errorf("route span is a %v with parent %v, want a root server span", server.SpanKind, server.Parent.SpanID())
+	}
+
+	usecaseSpan, ok := byName["GetBookByID"]
+	if !ok {
+		t.Fatalf("no usecase span among %v", spanNames(spans))
+	}
+
+	if usecaseSpan.Parent.SpanID() != server.SpanContext.SpanID() || usecaseSpan.SpanContext.TraceID() != server.SpanContext.TraceID() {
+		t.Errorf("usecase span is not a child of the route span")
+	}
+
+	queries := 0
+	for _, span := range spans {
+		if span.Name != "gorm.raw" && span.Name != "gorm.query" && span.Name != "gorm.row" {
+			continue
+		}
+
+		queries++
+		if span.SpanKind != trace.SpanKindClient || span.Parent.SpanID() != usecaseSpan.SpanContext.SpanID() {
+			t.Errorf("%s span is a %v with parent %v, want a client span under the usecase", span.Name, span.SpanKind, span.Parent.SpanID())
+		}
+
+		if system := spanAttribute(span, semconv.DBSystemKey); system != "sqlite" {
+			t.Errorf("%s span has db.system %q, want sqlite", span.Name, system)
+		}
+	}
+
+	if queries == 0 {
+		t.Errorf("no gorm spans among %v", spanNames(spans))
+	}
+}
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) string {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+
+	return ""
+}
+
+func spanNames(spans tracetest.SpanStubs) (names []string) {
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+
+	return names
+}
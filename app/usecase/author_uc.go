@@ -4,50 +4,93 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	_track "github.com/book-library/app/helper"
 	_r "github.com/book-library/app/repository"
 	"github.com/book-library/entity/author"
+	"github.com/book-library/entity/batch"
+	"github.com/book-library/entity/outbox"
+	"github.com/book-library/entity/webhook"
 	_l "github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
 type AuthorServiceI interface {
 	CreateAuthor(ctx context.Context, input author.AuthorInput) (err error)
 	UpdateAuthor(ctx context.Context, id int64, input author.AuthorInput) (err error)
+	PatchAuthor(ctx context.Context, id int64, patch []byte) (err error)
 	GetAuthorByID(ctx context.Context, id int64) (resp author.AuthorResponse, err error)
-	GetAllAuthors(ctx context.Context, name string) (resp []author.AuthorResponse, err error)
+	GetAllAuthors(ctx context.Context, name string, similarity float64) (resp []author.AuthorResponse, err error)
+	GetAuthorsByIDs(ctx context.Context, ids []int64) (resp []author.AuthorResponse, err error)
 	DeleteAuthorByID(ctx context.Context, id int64) (err error)
+	BatchAuthors(ctx context.Context, input author.AuthorBatchInput) (resp batch.BatchResponse, err error)
 }
 
 type AuthorService struct {
 	authorRepo _r.AuthorRepositoryI
 	trRepo     _r.TransactionRepositoryI
 	bookRepo   _r.BookLibraryRepositoryI
+	outboxRepo _r.OutboxRepositoryI
 }
 
-func NewAuthorService(authorRepo _r.AuthorRepositoryI, trRepo _r.TransactionRepositoryI, bookRepo _r.BookLibraryRepositoryI) AuthorServiceI {
+func NewAuthorService(authorRepo _r.AuthorRepositoryI, trRepo _r.TransactionRepositoryI, bookRepo _r.BookLibraryRepositoryI, outboxRepo _r.OutboxRepositoryI) AuthorServiceI {
 	return AuthorService{
 		authorRepo: authorRepo,
 		trRepo:     trRepo,
 		bookRepo:   bookRepo,
+		outboxRepo: outboxRepo,
 	}
 }
 
 // CreateAuthor implements AuthorServiceI.
 func (a AuthorService) CreateAuthor(ctx context.Context, input author.AuthorInput) (err error) {
-	defer _track.TimeTrack(time.Now(), "CreateAuthor")
+	ctx, end := _track.Track(ctx, "CreateAuthor")
+	defer end()
 
+	trx := a.trRepo.BeginTransaction(ctx)
+
+	_, err = a.createAuthor(ctx, trx, input)
+	if err != nil {
+		a.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	a.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// createAuthor validates input and stores it with its outbox event in trx.
+func (a AuthorService) createAuthor(ctx context.Context, trx *gorm.DB, input author.AuthorInput) (id int64, err error) {
 	if a.validationInput(input); err != nil {
 		_l.Error().Err(err).Msg("a.validationInput got an error on AuthorService.CreateAuthor")
-		return err
+		return id, err
 	}
 
+	id, err = a.authorRepo.CreateAuthor(ctx, trx, input)
+	if err != nil {
+		_l.Error().Err(err).Msg("a.authorRepo.CreateAuthor got an error on AuthorService.CreateAuthor")
+		return id, err
+	}
+
+	err = recordOutboxEvent(ctx, a.outboxRepo, trx, outbox.AggregateAuthor, id, webhook.EventAuthorCreated, webhook.ResourcePayload{ID: id, Attributes: input})
+	if err != nil {
+		_l.Error().Err(err).Msg("recordOutboxEvent got an error on AuthorService.CreateAuthor")
+		return id, err
+	}
+
+	return id, err
+}
+
+// DeleteAuthorByID implements AuthorServiceI.
+func (a AuthorService) DeleteAuthorByID(ctx context.Context, id int64) (err error) {
+	ctx, end := _track.Track(ctx, "DeleteAuthorByID")
+	defer end()
+
 	trx := a.trRepo.BeginTransaction(ctx)
 
-	err = a.authorRepo.CreateAuthor(ctx, trx, input)
+	err = a.deleteAuthor(ctx, trx, id)
 	if err != nil {
-		_l.Error().Err(err).Msg("a.authorRepo.CreateAuthor got an error on AuthorService.CreateAuthor")
 		a.trRepo.RollBackTransaction(ctx, trx)
 		return err
 	}
@@ -57,12 +100,23 @@ func (a AuthorService) CreateAuthor(ctx context.Context, input author.AuthorInpu
 	return err
 }
 
-// DeleteAuthorByID implements AuthorServiceI.
-func (a AuthorService) DeleteAuthorByID(ctx context.Context, id int64) (err error) {
-	defer _track.TimeTrack(time.Now(), "DeleteAuthorByID")
+// deleteAuthor refuses to remove an author that still has books, otherwise deletes it and
+// records its outbox event in trx.
+func (a AuthorService) deleteAuthor(ctx context.Context, trx *gorm.DB, id int64) (err error) {
 	_log := _l.Ctx(ctx)
 
-	bookByID, err := a.bookRepo.GetBookLibraryById(ctx, 0, id, 0)
+	authorById, err := a.authorRepo.GetAuthorById(ctx, trx, id, "")
+	if err != nil {
+		_log.Error().Err(err).Msg("a.authorRepo.GetAuthorById got an error on AuthorService.DeleteAuthorByID")
+		return err
+	}
+
+	if authorById.ID == 0 {
+		_log.Error().Msg("Author not found on AuthorService.DeleteAuthorByID")
+		return errors.New("Author not found")
+	}
+
+	bookByID, err := a.bookRepo.GetBookLibraryById(ctx, trx, 0, id, 0, 0)
 	if err != nil {
 		_log.Error().Err(err).Msg("c.bookRepo.GetBookLibraryById got an error on AuthorService.DeleteAuthorByID")
 		return err
@@ -73,26 +127,33 @@ func (a AuthorService) DeleteAuthorByID(ctx context.Context, id int64) (err erro
 		return fmt.Errorf("There is book(%s) using this author and delete the book(%s) first before delete author", bookByID.Title, bookByID.Title)
 	}
 
-	trx := a.trRepo.BeginTransaction(ctx)
-
 	err = a.authorRepo.DeleteAuthor(ctx, trx, id)
 	if err != nil {
 		_log.Error().Err(err).Msg("c.authorRepo.DeleteAuthor got an error on AuthorService.DeleteAuthorByID")
-		a.trRepo.RollBackTransaction(ctx, trx)
 		return err
 	}
 
-	a.trRepo.CommitTransaction(ctx, trx)
+	err = recordOutboxEvent(ctx, a.outboxRepo, trx, outbox.AggregateAuthor, id, webhook.EventAuthorDeleted, webhook.ResourcePayload{ID: id})
+	if err != nil {
+		_log.Error().Err(err).Msg("recordOutboxEvent got an error on AuthorService.DeleteAuthorByID")
+		return err
+	}
 
 	return err
 }
 
 // GetAllAuthors implements AuthorServiceI.
-func (a AuthorService) GetAllAuthors(ctx context.Context, name string) (resp []author.AuthorResponse, err error) {
-	defer _track.TimeTrack(time.Now(), "GetAllAuthors")
+func (a AuthorService) GetAllAuthors(ctx context.Context, name string, similarity float64) (resp []author.AuthorResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetAllAuthors")
+	defer end()
 	_log := _l.Ctx(ctx)
 
-	categories, err := a.authorRepo.GetAllAuthors(ctx, name)
+	if err = validateSimilarity(similarity); err != nil {
+		_log.Error().Err(err).Msg("validateSimilarity got an error on AuthorService.GetAllAuthors")
+		return resp, err
+	}
+
+	categories, err := a.authorRepo.GetAllAuthors(ctx, name, similarity)
 	if err != nil {
 		_log.Error().Err(err).Msg("c.authorRepo.GetAllAuthors got an error on AuthorService.GetAllAuthors")
 		return resp, err
@@ -103,7 +164,8 @@ func (a AuthorService) GetAllAuthors(ctx context.Context, name string) (resp []a
 
 // GetAuthorByID implements AuthorServiceI.
 func (a AuthorService) GetAuthorByID(ctx context.Context, id int64) (resp author.AuthorResponse, err error) {
-	defer _track.TimeTrack(time.Now(), "GetAuthorByID")
+	ctx, end := _track.Track(ctx, "GetAuthorByID")
+	defer end()
 	_log := _l.Ctx(ctx)
 
 	if id == 0 {
@@ -111,7 +173,7 @@ func (a AuthorService) GetAuthorByID(ctx context.Context, id int64) (resp author
 		return resp, errors.New("AuthorID cannot be nol")
 	}
 
-	authorById, err := a.authorRepo.GetAuthorById(ctx, id, "")
+	authorById, err := a.authorRepo.GetAuthorById(ctx, nil, id, "")
 	if err != nil {
 		_log.Error().Err(err).Msg("c.authorRepo.GetAuthorById got an error on AuthorService.GetAuthorByID")
 		return resp, err
@@ -127,7 +189,25 @@ func (a AuthorService) GetAuthorByID(ctx context.Context, id int64) (resp author
 
 // UpdateAuthor implements AuthorServiceI.
 func (a AuthorService) UpdateAuthor(ctx context.Context, id int64, input author.AuthorInput) (err error) {
-	defer _track.TimeTrack(time.Now(), "UpdateAuthor")
+	ctx, end := _track.Track(ctx, "UpdateAuthor")
+	defer end()
+
+	trx := a.trRepo.BeginTransaction(ctx)
+
+	err = a.updateAuthor(ctx, trx, id, input)
+	if err != nil {
+		a.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	a.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// updateAuthor fills the fields input leaves empty from the stored author and writes it with
+// its outbox event in trx.
+func (a AuthorService) updateAuthor(ctx context.Context, trx *gorm.DB, id int64, input author.AuthorInput) (err error) {
 	_log := _l.Ctx(ctx)
 
 	if id == 0 {
@@ -135,7 +215,7 @@ func (a AuthorService) UpdateAuthor(ctx context.Context, id int64, input author.
 		return errors.New("AuthorID cannot be nol")
 	}
 
-	authorById, err := a.authorRepo.GetAuthorById(ctx, id, "")
+	authorById, err := a.authorRepo.GetAuthorById(ctx, trx, id, "")
 	if err != nil {
 		_log.Error().Err(err).Msg("c.GetAuthorByID.GetAuthorById got an error on AuthorService.UpdateAuthor")
 		return err
@@ -154,11 +234,34 @@ func (a AuthorService) UpdateAuthor(ctx context.Context, id int64, input author.
 		input.Email = authorById.Email
 	}
 
-	trx := a.trRepo.BeginTransaction(ctx)
-
 	err = a.authorRepo.UpdateAuthor(ctx, trx, id, input)
 	if err != nil {
 		_log.Error().Err(err).Msg("c.authorRepo.UpdateAuthor got an error on AuthorService.UpdateAuthor")
+		return err
+	}
+
+	err = recordOutboxEvent(ctx, a.outboxRepo, trx, outbox.AggregateAuthor, id, webhook.EventAuthorUpdated, webhook.ResourcePayload{ID: id, Attributes: input})
+	if err != nil {
+		_log.Error().Err(err).Msg("recordOutboxEvent got an error on AuthorService.UpdateAuthor")
+		return err
+	}
+
+	return err
+}
+
+// authorPatchFields are the AuthorInput fields, and tb_author columns, a merge patch may change.
+var authorPatchFields = []string{"name", "email"}
+
+// PatchAuthor implements AuthorServiceI.
+// The merged author is validated as a whole and only the columns that changed are written.
+func (a AuthorService) PatchAuthor(ctx context.Context, id int64, patch []byte) (err error) {
+	ctx, end := _track.Track(ctx, "PatchAuthor")
+	defer end()
+
+	trx := a.trRepo.BeginTransaction(ctx)
+
+	err = a.patchAuthor(ctx, trx, id, patch)
+	if err != nil {
 		a.trRepo.RollBackTransaction(ctx, trx)
 		return err
 	}
@@ -168,6 +271,109 @@ func (a AuthorService) UpdateAuthor(ctx context.Context, id int64, input author.
 	return err
 }
 
+// patchAuthor merges patch into the author read in trx and writes the columns that changed
+// with its outbox event in trx.
+func (a AuthorService) patchAuthor(ctx context.Context, trx *gorm.DB, id int64, patch []byte) (err error) {
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("AuthorID cannot be nol on AuthorService.PatchAuthor")
+		return errors.New("AuthorID cannot be nol")
+	}
+
+	authorById, err := a.authorRepo.GetAuthorById(ctx, trx, id, "")
+	if err != nil {
+		_log.Error().Err(err).Msg("a.authorRepo.GetAuthorById got an error on AuthorService.PatchAuthor")
+		return err
+	}
+
+	if authorById.ID == 0 {
+		_log.Error().Msg("Author not found on AuthorService.PatchAuthor")
+		return errors.New("Author not found")
+	}
+
+	var input author.AuthorInput
+	changed, err := applyMergePatch(authorById, patch, authorPatchFields, &input)
+	if err != nil {
+		_log.Error().Err(err).Msg("applyMergePatch got an error on AuthorService.PatchAuthor")
+		return err
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if err = a.validationInput(input); err != nil {
+		_log.Error().Err(err).Msg("a.validationInput got an error on AuthorService.PatchAuthor")
+		return err
+	}
+
+	err = a.authorRepo.UpdateAuthor(ctx, trx, id, input, changed...)
+	if err != nil {
+		_log.Error().Err(err).Msg("a.authorRepo.UpdateAuthor got an error on AuthorService.PatchAuthor")
+		return err
+	}
+
+	err = recordOutboxEvent(ctx, a.outboxRepo, trx, outbox.AggregateAuthor, id, webhook.EventAuthorUpdated, webhook.ResourcePayload{ID: id, Attributes: input})
+	if err != nil {
+		_log.Error().Err(err).Msg("recordOutboxEvent got an error on AuthorService.PatchAuthor")
+		return err
+	}
+
+	return err
+}
+
+// BatchAuthors implements AuthorServiceI.
+func (a AuthorService) BatchAuthors(ctx context.Context, input author.AuthorBatchInput) (resp batch.BatchResponse, err error) {
+	ctx, end := _track.Track(ctx, "BatchAuthors")
+	defer end()
+
+	steps := make([]batchStep, 0, len(input.Operations))
+	for _, operation := range input.Operations {
+		step := batchStep{op: operation.Op}
+
+		switch operation.Op {
+		case batch.OpCreate:
+			step.run = func(ctx context.Context, trx *gorm.DB) (int64, error) {
+				return a.createAuthor(ctx, trx, operation.Data)
+			}
+		case batch.OpUpdate:
+			step.run = func(ctx context.Context, trx *gorm.DB) (int64, error) {
+				return operation.ID, a.updateAuthor(ctx, trx, operation.ID, operation.Data)
+			}
+		case batch.OpDelete:
+			step.run = func(ctx context.Context, trx *gorm.DB) (int64, error) {
+				return operation.ID, a.deleteAuthor(ctx, trx, operation.ID)
+			}
+		default:
+			step.run = unsupportedBatchOp(operation.Op)
+		}
+
+		steps = append(steps, step)
+	}
+
+	return runBatch(ctx, a.trRepo, input.Mode, steps)
+}
+
+// GetAuthorsByIDs implements AuthorServiceI.
+func (a AuthorService) GetAuthorsByIDs(ctx context.Context, ids []int64) (resp []author.AuthorResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetAuthorsByIDsUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if len(ids) == 0 {
+		return resp, nil
+	}
+
+	authors, err := a.authorRepo.GetAuthorsByIDs(ctx, ids)
+	if err != nil {
+		_log.Error().Err(err).Msg("a.authorRepo.GetAuthorsByIDs got an error on AuthorService.GetAuthorsByIDs")
+		return resp, err
+	}
+
+	return authors, err
+}
+
 func (a AuthorService) validationInput(input author.AuthorInput) (err error) {
 	if input.Name == "" {
 		return errors.New("Name can not be empty")
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/batch"
+	_l "github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// batchStep is one operation of a batch. run validates the operation and writes it with trx,
+// returning the id of the row it touched.
+type batchStep struct {
+	op  string
+	run func(ctx context.Context, trx *gorm.DB) (id int64, err error)
+}
+
+// runBatch applies steps in order. In atomic mode they share one transaction and the first
+// failure rolls everything back; in best-effort mode every step commits on its own.
+func runBatch(ctx context.Context, trRepo _r.TransactionRepositoryI, mode string, steps []batchStep) (resp batch.BatchResponse, err error) {
+	_log := _l.Ctx(ctx)
+
+	if mode == "" {
+		mode = batch.ModeAtomic
+	}
+
+	if mode != batch.ModeAtomic && mode != batch.ModeBestEffort {
+		return resp, fmt.Errorf("Mode %s is not supported, use %s or %s", mode, batch.ModeAtomic, batch.ModeBestEffort)
+	}
+
+	if len(steps) == 0 {
+		return resp, errors.New("Operations can not be empty")
+	}
+
+	if len(steps) > batch.MaxOperations {
+		return resp, fmt.Errorf("A batch can not have more than %d operations", batch.MaxOperations)
+	}
+
+	resp = batch.BatchResponse{Mode: mode, Results: make([]batch.BatchResult, len(steps))}
+	for i, step := range steps {
+		resp.Results[i] = batch.BatchResult{Index: i, Op: step.op}
+	}
+
+	if mode == batch.ModeBestEffort {
+		for i, step := range steps {
+			trx := trRepo.BeginTransaction(ctx)
+
+			id, err := step.run(ctx, trx)
+			if err == nil {
+				err = trRepo.CommitTransaction(ctx, trx).Error
+			} else {
+				trRepo.RollBackTransaction(ctx, trx)
+			}
+
+			if err != nil {
+				_log.Error().Err(err).Int("index", i).Msg("step.run got an error on runBatch")
+				resp.Results[i].Status = batch.StatusFailed
+				resp.Results[i].Error = err.Error()
+				resp.Failed++
+				continue
+			}
+
+			resp.Results[i].ID = id
+			resp.Results[i].Status = batch.StatusSuccess
+			resp.Succeeded++
+		}
+
+		return resp, nil
+	}
+
+	trx := trRepo.BeginTransaction(ctx)
+
+	for i, step := range steps {
+		id, err := step.run(ctx, trx)
+		if err != nil {
+			_log.Error().Err(err).Int("index", i).Msg("step.run got an error on runBatch")
+			trRepo.RollBackTransaction(ctx, trx)
+
+			return failAtomicBatch(resp, i, err), fmt.Errorf("Operation %d failed and the batch was rolled back: %w", i, err)
+		}
+
+		resp.Results[i].ID = id
+	}
+
+	if err = trRepo.CommitTransaction(ctx, trx).Error; err != nil {
+		_log.Error().Err(err).Msg("trRepo.CommitTransaction got an error on runBatch")
+		return failAtomicBatch(resp, -1, err), err
+	}
+
+	for i := range resp.Results {
+		resp.Results[i].Status = batch.StatusSuccess
+	}
+	resp.Succeeded = len(steps)
+
+	return resp, nil
+}
+
+// failAtomicBatch marks the operation at failed (-1 when the commit failed) and reports the
+// ones before it as rolled back and the ones after it as skipped.
+func failAtomicBatch(resp batch.BatchResponse, failed int, err error) batch.BatchResponse {
+	for i := range resp.Results {
+		resp.Results[i].ID = 0
+
+		switch {
+		case i == failed:
+			resp.Results[i].Status = batch.StatusFailed
+			resp.Results[i].Error = err.Error()
+		case failed == -1 || i < failed:
+			resp.Results[i].Status = batch.StatusRolledBack
+		default:
+			resp.Results[i].Status = batch.StatusSkipped
+		}
+	}
+
+	resp.Failed = 1
+	if failed == -1 {
+		resp.Failed = len(resp.Results)
+	}
+
+	return resp
+}
+
+func unsupportedBatchOp(op string) func(ctx context.Context, trx *gorm.DB) (int64, error) {
+	return func(ctx context.Context, trx *gorm.DB) (int64, error) {
+		return 0, fmt.Errorf("Op %s is not supported, use %s, %s or %s", op, batch.OpCreate, batch.OpUpdate, batch.OpDelete)
+	}
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/batch"
+	"github.com/book-library/entity/book"
+	"github.com/book-library/migration"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+func newTestSQLiteBookService(t *testing.T) BookLibraryServiceI {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.New(sqlite.Config{
+		DriverName: _r.SQLiteDriverName,
+		DSN:        "file:" + filepath.Join(t.TempDir(), "library.db") + "?_foreign_keys=on&_busy_timeout=1000",
+	}), &gorm.Config{Logger: gormLogger.Discard})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	if err = migration.Migrate(db); err != nil {
+		t.Fatalf("migration.Migrate: %v", err)
+	}
+
+	err = db.Exec(`INSERT INTO tb_author (name, email) VALUES ('Ann Leckie', 'ann@example.com');
+		INSERT INTO tb_category (name) VALUES ('Science Fiction');
+		INSERT INTO tb_work (title, author_id, category_id, original_language) VALUES ('Ancillary Justice', 1, 1, 'en');`).Error
+	if err != nil {
+		t.Fatalf("seeding: %v", err)
+	}
+
+	return NewbookLibraryService(_r.NewBookLibraryRepository(db), _r.NewTransactionRepository(db),
+		_r.NewAuthorRepository(db), _r.NewCategoryRepository(db), _r.NewPublisherRepository(db),
+		_r.NewSeriesRepository(db), _r.NewWorkRepository(db), _r.NewOutboxRepository(db))
+}
+
+func TestAtomicBatchUpdatesABookItCreated(t *testing.T) {
+	ctx := context.Background()
+	service := newTestSQLiteBookService(t)
+
+	resp, err := service.BatchBooks(ctx, book.BookBatchInput{
+		Mode: batch.ModeAtomic,
+		Operations: []book.BookBatchOperation{
+			{Op: batch.OpCreate, Data: book.BookInput{Title: "Ancillary Justice", Description: "Breq", AuthorID: 1, CategoryID: 1, ISBN: "9780316246620"}},
+			{Op: batch.OpUpdate, ID: 1, Data: book.BookInput{Title: "Ancillary Sword"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BatchBooks = %+v, %v", resp, err)
+	}
+
+	if resp.Succeeded != 2 {
+		t.Fatalf("succeeded = %d, want 2: %+v", resp.Succeeded, resp.Results)
+	}
+
+	stored, err := service.GetBookByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("GetBookByID: %v", err)
+	}
+
+	if stored.Title != "Ancillary Sword" || stored.ISBN != "9780316246620" || stored.PublishedFlag {
+		t.Errorf("stored book = %s (%s, published %v), want the created, unpublished book with the new title", stored.Title, stored.ISBN, stored.PublishedFlag)
+	}
+}
+
+func TestBestEffortBatchReportsMissingRowsAsFailed(t *testing.T) {
+	ctx := context.Background()
+	service := newTestSQLiteBookService(t)
+
+	resp, err := service.BatchBooks(ctx, book.BookBatchInput{
+		Mode: batch.ModeBestEffort,
+		Operations: []book.BookBatchOperation{
+			{Op: batch.OpCreate, Data: book.BookInput{Title: "Ancillary Justice", Description: "Breq", AuthorID: 1, CategoryID: 1, ISBN: "9780316246620"}},
+			{Op: batch.OpDelete, ID: 999},
+			{Op: batch.OpUpdate, ID: 999, Data: book.BookInput{Title: "Ancillary Sword"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BatchBooks = %+v, %v", resp, err)
+	}
+
+	statuses := []string{}
+	for _, result := range resp.Results {
+		statuses = append(statuses, result.Status)
+	}
+
+	want := []string{batch.StatusSuccess, batch.StatusFailed, batch.StatusFailed}
+	if !reflect.DeepEqual(statuses, want) || resp.Succeeded != 1 || resp.Failed != 2 {
+		t.Errorf("statuses = %v (%d succeeded, %d failed), want %v", statuses, resp.Succeeded, resp.Failed, want)
+	}
+}
@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	_track "github.com/book-library/app/helper"
+	"github.com/book-library/entity/author"
+	"github.com/book-library/entity/book"
+	"github.com/book-library/entity/category"
+	"github.com/book-library/entity/outbox"
+	"github.com/book-library/entity/publisher"
+	"github.com/book-library/entity/series"
+	"github.com/book-library/entity/webhook"
+	"github.com/book-library/entity/work"
+	_l "github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// ExportBooks implements BookLibraryServiceI.
+// Every book is exported, published or not, in id order.
+func (b BookLibraryService) ExportBooks(ctx context.Context) (resp book.BookExport, err error) {
+	ctx, end := _track.Track(ctx, "ExportBooks")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	books, err := b.bookRepo.GetAllBookLibraries(ctx, book.BookSearch{Published: book.PublishedAny})
+	if err != nil {
+		_log.Error().Err(err).Msg("b.bookRepo.GetAllBookLibraries got an error on BookLibraryService.ExportBooks")
+		return resp, err
+	}
+
+	resp.Books = []book.BookRecord{}
+	for _, v := range books {
+		resp.Books = append(resp.Books, book.BookRecord{
+			Title:               v.Title,
+			Description:         v.BoookDescription,
+			ISBN:                v.ISBN,
+			PublishedFlag:       v.PublishedFlag,
+			PublicationYear:     v.PublicationYear,
+			Edition:             v.Edition,
+			PageCount:           v.PageCount,
+			Language:            v.Language,
+			AuthorName:          v.AuthorName,
+			AuthorEmail:         v.AuthorEmail,
+			CategoryName:        v.CategoryName,
+			CategoryDescription: v.CategoryDescription,
+			PublisherName:       v.PublisherName,
+			SeriesName:          v.SeriesName,
+			SeriesVolume:        v.SeriesVolume,
+			WorkTitle:           v.WorkTitle,
+		})
+	}
+
+	return resp, err
+}
+
+// bookImportRefs remembers the ids an import resolved, by lower-cased name. Rows the import
+// creates are only visible inside its transaction, so later records find them here.
+type bookImportRefs struct {
+	authors    map[string]int64
+	categories map[string]int64
+	publishers map[string]int64
+	series     map[string]int64
+	works      map[string]int64
+	isbns      map[string]bool
+}
+
+// ImportBooks implements BookLibraryServiceI.
+// A record whose ISBN is already in the library overwrites that book, any other record is
+// created. Authors, categories, publishers, series and works missing from the library are
+// created on the way. The import is one transaction: a failing record leaves the library as it
+// was.
+func (b BookLibraryService) ImportBooks(ctx context.Context, input book.BookExport) (resp book.BookImportResponse, err error) {
+	ctx, end := _track.Track(ctx, "ImportBooks")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if len(input.Books) == 0 {
+		_log.Error().Msg("Books can not be empty on BookLibraryService.ImportBooks")
+		return resp, errors.New("Books can not be empty")
+	}
+
+	refs := bookImportRefs{
+		authors:    map[string]int64{},
+		categories: map[string]int64{},
+		publishers: map[string]int64{},
+		series:     map[string]int64{},
+		works:      map[string]int64{},
+		isbns:      map[string]bool{},
+	}
+
+	trx := b.trRepo.BeginTransaction(ctx)
+
+	for i, record := range input.Books {
+		created, err := b.importBook(ctx, trx, refs, record)
+		if err != nil {
+			_log.Error().Err(err).Msgf("b.importBook got an error on book %d on BookLibraryService.ImportBooks", i)
+			b.trRepo.RollBackTransaction(ctx, trx)
+			return book.BookImportResponse{}, fmt.Errorf("Book %d: %w", i, err)
+		}
+
+		if created {
+			resp.Created++
+		} else {
+			resp.Updated++
+		}
+	}
+
+	b.trRepo.CommitTransaction(ctx, trx)
+
+	return resp, nil
+}
+
+// importBook writes record in trx and tells whether it created a new book.
+func (b BookLibraryService) importBook(ctx context.Context, trx *gorm.DB, refs bookImportRefs, record book.BookRecord) (created bool, err error) {
+	if record.ISBN == "" {
+		return false, errors.New("ISBN can not be empty")
+	}
+
+	if refs.isbns[record.ISBN] {
+		return false, fmt.Errorf("ISBN %s appears more than once", record.ISBN)
+	}
+	refs.isbns[record.ISBN] = true
+
+	input := book.BookInput{
+		Title:           record.Title,
+		Description:     record.Description,
+		ISBN:            record.ISBN,
+		PublishedFlag:   &record.PublishedFlag,
+		PublicationYear: record.PublicationYear,
+		Edition:         record.Edition,
+		PageCount:       record.PageCount,
+		Language:        record.Language,
+		SeriesVolume:    record.SeriesVolume,
+	}
+
+	if input.AuthorID, err = b.importAuthor(ctx, trx, refs, record); err != nil {
+		return false, err
+	}
+
+	if input.CategoryID, err = b.importCategory(ctx, trx, refs, record); err != nil {
+		return false, err
+	}
+
+	if record.PublisherName != "" {
+		input.PublisherID = new(int64)
+		if *input.PublisherID, err = b.importPublisher(ctx, trx, refs, record); err != nil {
+			return false, err
+		}
+	}
+
+	if record.SeriesName != "" {
+		input.SeriesID = new(int64)
+		if *input.SeriesID, err = b.importSeries(ctx, trx, refs, record); err != nil {
+			return false, err
+		}
+	}
+
+	if record.WorkTitle != "" {
+		input.WorkID = new(int64)
+		if *input.WorkID, err = b.importWork(ctx, trx, refs, record, input); err != nil {
+			return false, err
+		}
+	}
+
+	// The record is complete, so unlike createBook nothing is inherited from its work.
+	if err = b.validationInput(input); err != nil {
+		return false, err
+	}
+
+	existing, err := b.bookRepo.GetBookLibraryByISBN(ctx, trx, record.ISBN)
+	if err != nil {
+		return false, err
+	}
+
+	if existing.ID != 0 {
+		if err = b.bookRepo.UpdateBookLibrary(ctx, trx, existing.ID, input); err != nil {
+			return false, err
+		}
+
+		return false, recordOutboxEvent(ctx, b.outboxRepo, trx, outbox.AggregateBook, existing.ID, webhook.EventBookUpdated, webhook.ResourcePayload{ID: existing.ID, Attributes: input})
+	}
+
+	id, err := b.bookRepo.CreateBookLibrary(ctx, trx, input)
+	if err != nil {
+		return true, err
+	}
+
+	return true, recordOutboxEvent(ctx, b.outboxRepo, trx, outbox.AggregateBook, id, webhook.EventBookCreated, webhook.ResourcePayload{ID: id, Attributes: input})
+}
+
+// resolveImportRef returns the id remembered under key, or finds it with find, creating it
+// with create when find returns 0, and remembers it.
+func resolveImportRef(refs map[string]int64, key string, find func() (int64, error), create func() (int64, error)) (id int64, err error) {
+	key = strings.ToLower(key)
+	if id, ok := refs[key]; ok {
+		return id, nil
+	}
+
+	if id, err = find(); err != nil {
+		return id, err
+	}
+
+	if id == 0 {
+		if id, err = create(); err != nil {
+			return id, err
+		}
+	}
+
+	refs[key] = id
+
+	return id, nil
+}
+
+func (b BookLibraryService) importAuthor(ctx context.Context, trx *gorm.DB, refs bookImportRefs, record book.BookRecord) (id int64, err error) {
+	if record.AuthorName == "" || record.AuthorEmail == "" {
+		return id, errors.New("AuthorName and AuthorEmail can not be empty")
+	}
+
+	return resolveImportRef(refs.authors, record.AuthorEmail, func() (int64, error) {
+		byEmail, err := b.authorRepo.GetAuthorById(ctx, trx, 0, strings.ToLower(record.AuthorEmail))
+		return byEmail.ID, err
+	}, func() (id int64, err error) {
+		input := author.AuthorInput{Name: record.AuthorName, Email: record.AuthorEmail}
+		if id, err = b.authorRepo.CreateAuthor(ctx, trx, input); err != nil {
+			return id, err
+		}
+
+		return id, recordOutboxEvent(ctx, b.outboxRepo, trx, outbox.AggregateAuthor, id, webhook.EventAuthorCreated, webhook.ResourcePayload{ID: id, Attributes: input})
+	})
+}
+
+func (b BookLibraryService) importCategory(ctx context.Context, trx *gorm.DB, refs bookImportRefs, record book.BookRecord) (id int64, err error) {
+	if record.CategoryName == "" {
+		return id, errors.New("CategoryName can not be empty")
+	}
+
+	return resolveImportRef(refs.categories, record.CategoryName, func() (int64, error) {
+		byName, err := b.categoryRepo.GetCategoryById(ctx, trx, 0, record.CategoryName)
+		return byName.ID, err
+	}, func() (id int64, err error) {
+		input := category.CategoryInput{Name: record.CategoryName, Description: record.CategoryDescription}
+		if id, err = b.categoryRepo.CreateCategory(ctx, trx, input); err != nil {
+			return id, err
+		}
+
+		return id, recordOutboxEvent(ctx, b.outboxRepo, trx, outbox.AggregateCategory, id, webhook.EventCategoryCreated, webhook.ResourcePayload{ID: id, Attributes: input})
+	})
+}
+
+func (b BookLibraryService) importPublisher(ctx context.Context, trx *gorm.DB, refs bookImportRefs, record book.BookRecord) (id int64, err error) {
+	return resolveImportRef(refs.publishers, record.PublisherName, func() (int64, error) {
+		byName, err := b.publisherRepo.GetPublisherById(ctx, trx, 0, record.PublisherName)
+		return byName.ID, err
+	}, func() (int64, error) {
+		return b.publisherRepo.CreatePublisher(ctx, trx, publisher.PublisherInput{Name: record.PublisherName})
+	})
+}
+
+func (b BookLibraryService) importSeries(ctx context.Context, trx *gorm.DB, refs bookImportRefs, record book.BookRecord) (id int64, err error) {
+	return resolveImportRef(refs.series, record.SeriesName, func() (int64, error) {
+		byName, err := b.seriesRepo.GetSeriesById(ctx, trx, 0, strings.ToLower(record.SeriesName))
+		return byName.ID, err
+	}, func() (int64, error) {
+		return b.seriesRepo.CreateSeries(ctx, trx, series.SeriesInput{Name: record.SeriesName})
+	})
+}
+
+// importWork finds the work by its exact title. A new work takes the author and category of the
+// book that introduces it.
+func (b BookLibraryService) importWork(ctx context.Context, trx *gorm.DB, refs bookImportRefs, record book.BookRecord, input book.BookInput) (id int64, err error) {
+	return resolveImportRef(refs.works, record.WorkTitle, func() (int64, error) {
+		works, err := b.workRepo.GetAllWorks(ctx, trx, record.WorkTitle)
+		for _, v := range works {
+			if strings.EqualFold(v.Title, record.WorkTitle) {
+				return v.ID, err
+			}
+		}
+
+		return 0, err
+	}, func() (int64, error) {
+		return b.workRepo.CreateWork(ctx, trx, work.WorkInput{
+			Title:            record.WorkTitle,
+			AuthorID:         input.AuthorID,
+			CategoryID:       input.CategoryID,
+			OriginalLanguage: record.Language,
+		})
+	})
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/book-library/entity/book"
+)
+
+func TestExportImportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	service := newTestSQLiteBookService(t)
+
+	export := book.BookExport{Books: []book.BookRecord{
+		{
+			Title: "Ancillary Justice", Description: "Breq", ISBN: "9780316246620", PublishedFlag: true, PublicationYear: 2013,
+			Edition: "1st", PageCount: 386, Language: "en", AuthorName: "Ann Leckie", AuthorEmail: "ann@example.com",
+			CategoryName: "Science Fiction", PublisherName: "Orbit", SeriesName: "Imperial Radch", SeriesVolume: 1, WorkTitle: "Ancillary Justice",
+		},
+		{
+			Title: "Ancillary Sword", Description: "Athoek", ISBN: "9780316246651", PublicationYear: 2014,
+			Edition: "1st", PageCount: 356, Language: "en", AuthorName: "Ann Leckie", AuthorEmail: "ann@example.com",
+			CategoryName: "Science Fiction", PublisherName: "Orbit", SeriesName: "Imperial Radch", SeriesVolume: 2, WorkTitle: "Ancillary Sword",
+		},
+	}}
+
+	imported, err := service.ImportBooks(ctx, export)
+	if err != nil || imported.Created != 2 {
+		t.Fatalf("ImportBooks = %+v, %v, want 2 created", imported, err)
+	}
+
+	exported, err := service.ExportBooks(ctx)
+	if err != nil {
+		t.Fatalf("ExportBooks: %v", err)
+	}
+
+	if !reflect.DeepEqual(exported, export) {
+		t.Errorf("exported = %+v\nwant the imported %+v", exported, export)
+	}
+
+	// Importing the export again finds every book by its ISBN.
+	imported, err = service.ImportBooks(ctx, exported)
+	if err != nil || imported.Created != 0 || imported.Updated != 2 {
+		t.Errorf("ImportBooks of the export = %+v, %v, want 2 updated", imported, err)
+	}
+}
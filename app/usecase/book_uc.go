@@ -3,55 +3,125 @@ package usecase
 import (
 	"context"
 	"errors"
-	"time"
+	"fmt"
+	"slices"
+	"sort"
 
 	_track "github.com/book-library/app/helper"
 	_r "github.com/book-library/app/repository"
 	"github.com/book-library/entity/author"
+	"github.com/book-library/entity/batch"
 	"github.com/book-library/entity/book"
 	"github.com/book-library/entity/category"
+	"github.com/book-library/entity/outbox"
+	"github.com/book-library/entity/publisher"
+	"github.com/book-library/entity/series"
+	"github.com/book-library/entity/webhook"
+	"github.com/book-library/entity/work"
 	_l "github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
 type BookLibraryServiceI interface {
 	CreateBook(ctx context.Context, input book.BookInput) (err error)
 	UpdateBook(ctx context.Context, id int64, input book.BookInput) (err error)
+	PatchBook(ctx context.Context, id int64, patch []byte) (err error)
 	GetBookByID(ctx context.Context, id int64) (resp book.BookResponseDetail, err error)
-	GetAllBooks(ctx context.Context, search string) (resp []book.BookResponseDetail, err error)
+	GetAllBooks(ctx context.Context, search book.BookSearch) (resp []book.BookResponseDetail, err error)
+	GetAllBooksByWork(ctx context.Context, search book.BookSearch, expand bool) (resp []book.BookWorkGroup, err error)
+	SearchBooks(ctx context.Context, search book.BookSearch) (resp book.BookSearchResponse, err error)
+	GetSimilarBooks(ctx context.Context, id int64, limit int) (resp []book.SimilarBookResponse, err error)
 	DeleteBookByID(ctx context.Context, id int64) (err error)
+	BatchBooks(ctx context.Context, input book.BookBatchInput) (resp batch.BatchResponse, err error)
+	ExportBooks(ctx context.Context) (resp book.BookExport, err error)
+	ImportBooks(ctx context.Context, input book.BookExport) (resp book.BookImportResponse, err error)
 }
 
 type BookLibraryService struct {
-	bookRepo     _r.BookLibraryRepositoryI
-	trRepo       _r.TransactionRepositoryI
-	authorRepo   _r.AuthorRepositoryI
-	categoryRepo _r.CategoryRepositoryI
+	bookRepo      _r.BookLibraryRepositoryI
+	trRepo        _r.TransactionRepositoryI
+	authorRepo    _r.AuthorRepositoryI
+	categoryRepo  _r.CategoryRepositoryI
+	publisherRepo _r.PublisherRepositoryI
+	seriesRepo    _r.SeriesRepositoryI
+	workRepo      _r.WorkRepositoryI
+	outboxRepo    _r.OutboxRepositoryI
 }
 
-func NewbookLibraryService(bookRepo _r.BookLibraryRepositoryI, trRepo _r.TransactionRepositoryI, authorRepo _r.AuthorRepositoryI, categoryRepo _r.CategoryRepositoryI) BookLibraryServiceI {
+func NewbookLibraryService(bookRepo _r.BookLibraryRepositoryI, trRepo _r.TransactionRepositoryI, authorRepo _r.AuthorRepositoryI, categoryRepo _r.CategoryRepositoryI, publisherRepo _r.PublisherRepositoryI, seriesRepo _r.SeriesRepositoryI, workRepo _r.WorkRepositoryI, outboxRepo _r.OutboxRepositoryI) BookLibraryServiceI {
 	return BookLibraryService{
-		bookRepo:     bookRepo,
-		trRepo:       trRepo,
-		authorRepo:   authorRepo,
-		categoryRepo: categoryRepo,
+		bookRepo:      bookRepo,
+		trRepo:        trRepo,
+		authorRepo:    authorRepo,
+		categoryRepo:  categoryRepo,
+		publisherRepo: publisherRepo,
+		seriesRepo:    seriesRepo,
+		workRepo:      workRepo,
+		outboxRepo:    outboxRepo,
 	}
 }
 
 // CreateBook implements BookLibraryServiceI.
 func (b BookLibraryService) CreateBook(ctx context.Context, input book.BookInput) (err error) {
-	now := time.Now()
-	defer _track.TimeTrack(now, "CreateBookUC")
+	ctx, end := _track.Track(ctx, "CreateBookUC")
+	defer end()
 
-	if b.validationInput(input); err != nil {
-		_l.Error().Err(err).Msg("b.validationInput got an error on BookLibraryService.CreateBook")
+	trx := b.trRepo.BeginTransaction(ctx)
+
+	_, err = b.createBook(ctx, trx, input)
+	if err != nil {
+		b.trRepo.RollBackTransaction(ctx, trx)
 		return err
 	}
 
-	trx := b.trRepo.BeginTransaction(ctx)
+	b.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// createBook validates input and stores it with its outbox event in trx.
+// A book is created unpublished unless input says otherwise.
+func (b BookLibraryService) createBook(ctx context.Context, trx *gorm.DB, input book.BookInput) (id int64, err error) {
+	if input.PublishedFlag == nil {
+		published := false
+		input.PublishedFlag = &published
+	}
+
+	input, err = b.inheritFromWork(ctx, trx, input)
+	if err != nil {
+		_l.Error().Err(err).Msg("b.inheritFromWork got an error on BookLibraryService.CreateBook")
+		return id, err
+	}
+
+	if err = b.validationInput(input); err != nil {
+		_l.Error().Err(err).Msg("b.validationInput got an error on BookLibraryService.CreateBook")
+		return id, err
+	}
 
-	err = b.bookRepo.CreateBookLibrary(ctx, trx, input)
+	id, err = b.bookRepo.CreateBookLibrary(ctx, trx, input)
 	if err != nil {
 		_l.Error().Err(err).Msg("b.repo.CreateBookLibrary got an error on BookLibraryService.CreateBook")
+		return id, err
+	}
+
+	err = recordOutboxEvent(ctx, b.outboxRepo, trx, outbox.AggregateBook, id, webhook.EventBookCreated, webhook.ResourcePayload{ID: id, Attributes: input})
+	if err != nil {
+		_l.Error().Err(err).Msg("recordOutboxEvent got an error on BookLibraryService.CreateBook")
+		return id, err
+	}
+
+	return id, err
+}
+
+// UpdateBook implements BookLibraryServiceI.
+func (b BookLibraryService) UpdateBook(ctx context.Context, id int64, input book.BookInput) (err error) {
+	ctx, end := _track.Track(ctx, "UpdateBook")
+	defer end()
+
+	trx := b.trRepo.BeginTransaction(ctx)
+
+	err = b.updateBook(ctx, trx, id, input)
+	if err != nil {
 		b.trRepo.RollBackTransaction(ctx, trx)
 		return err
 	}
@@ -61,9 +131,9 @@ func (b BookLibraryService) CreateBook(ctx context.Context, input book.BookInput
 	return err
 }
 
-// UpdateBook implements BookLibraryServiceI.
-func (b BookLibraryService) UpdateBook(ctx context.Context, id int64, input book.BookInput) (err error) {
-	defer _track.TimeTrack(time.Now(), "UpdateBook")
+// updateBook fills the fields input leaves empty from the stored book, validates the result
+// and writes it with its outbox event in trx.
+func (b BookLibraryService) updateBook(ctx context.Context, trx *gorm.DB, id int64, input book.BookInput) (err error) {
 	_log := _l.Ctx(ctx)
 
 	if id == 0 {
@@ -71,7 +141,7 @@ func (b BookLibraryService) UpdateBook(ctx context.Context, id int64, input book
 		return errors.New("BookID cannot be nol")
 	}
 
-	bookById, err := b.bookRepo.GetBookLibraryById(ctx, id, 0, 0)
+	bookById, err := b.bookRepo.GetBookLibraryById(ctx, trx, id, 0, 0, 0)
 	if err != nil {
 		_log.Error().Err(err).Msg("c.bookRepo.GetBookLibraryById got an error on BookLibraryService.UpdateBook")
 		return err
@@ -106,11 +176,81 @@ func (b BookLibraryService) UpdateBook(ctx context.Context, id int64, input book
 		input.PublishedFlag = &bookById.PublishedFlag
 	}
 
-	trx := b.trRepo.BeginTransaction(ctx)
+	if input.PublisherID == nil && bookById.PublisherID != 0 {
+		input.PublisherID = &bookById.PublisherID
+	}
+
+	if input.SeriesID == nil && bookById.SeriesID != 0 {
+		input.SeriesID = &bookById.SeriesID
+	}
+
+	if input.SeriesVolume == 0 {
+		input.SeriesVolume = bookById.SeriesVolume
+	}
+
+	if input.PublicationYear == 0 {
+		input.PublicationYear = bookById.PublicationYear
+	}
+
+	if input.Edition == "" {
+		input.Edition = bookById.Edition
+	}
+
+	if input.PageCount == 0 {
+		input.PageCount = bookById.PageCount
+	}
+
+	if input.Language == "" {
+		input.Language = bookById.Language
+	}
+
+	if input.WorkID == nil && bookById.WorkID != 0 {
+		input.WorkID = &bookById.WorkID
+	}
+
+	input, err = b.inheritFromWork(ctx, trx, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("b.inheritFromWork got an error on BookLibraryService.UpdateBook")
+		return err
+	}
+
+	if err = b.validationInput(input); err != nil {
+		_log.Error().Err(err).Msg("b.validationInput got an error on BookLibraryService.UpdateBook")
+		return err
+	}
 
 	err = b.bookRepo.UpdateBookLibrary(ctx, trx, id, input)
 	if err != nil {
 		_log.Error().Err(err).Msg("c.bookRepo.UpdateBookLibrary got an error on BookLibraryService.UpdateBook")
+		return err
+	}
+
+	err = recordOutboxEvent(ctx, b.outboxRepo, trx, outbox.AggregateBook, id, webhook.EventBookUpdated, webhook.ResourcePayload{ID: id, Attributes: input})
+	if err != nil {
+		_log.Error().Err(err).Msg("recordOutboxEvent got an error on BookLibraryService.UpdateBook")
+		return err
+	}
+
+	return err
+}
+
+// bookPatchFields are the BookInput fields, and tb_book columns, a merge patch may change.
+var bookPatchFields = []string{
+	"title", "author_id", "description", "isbn", "published_flag", "category_id", "publisher_id",
+	"series_id", "series_volume", "work_id", "publication_year", "edition", "page_count", "language",
+}
+
+// PatchBook implements BookLibraryServiceI.
+// Unlike UpdateBook, zero values in the patch are written as given and null clears a field.
+// The merged book is validated as a whole and only the columns that changed are written.
+func (b BookLibraryService) PatchBook(ctx context.Context, id int64, patch []byte) (err error) {
+	ctx, end := _track.Track(ctx, "PatchBook")
+	defer end()
+
+	trx := b.trRepo.BeginTransaction(ctx)
+
+	err = b.patchBook(ctx, trx, id, patch)
+	if err != nil {
 		b.trRepo.RollBackTransaction(ctx, trx)
 		return err
 	}
@@ -120,11 +260,113 @@ func (b BookLibraryService) UpdateBook(ctx context.Context, id int64, input book
 	return err
 }
 
+// patchBook merges patch into the book read in trx and writes the columns that changed
+// with its outbox event in trx.
+func (b BookLibraryService) patchBook(ctx context.Context, trx *gorm.DB, id int64, patch []byte) (err error) {
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("BookID cannot be nol on BookLibraryService.PatchBook")
+		return errors.New("BookID cannot be nol")
+	}
+
+	bookById, err := b.bookRepo.GetBookLibraryById(ctx, trx, id, 0, 0, 0)
+	if err != nil {
+		_log.Error().Err(err).Msg("b.bookRepo.GetBookLibraryById got an error on BookLibraryService.PatchBook")
+		return err
+	}
+
+	if bookById.ID == 0 {
+		_log.Error().Msg("Book not found on BookLibraryService.PatchBook")
+		return errors.New("Book not found")
+	}
+
+	var input book.BookInput
+	changed, err := applyMergePatch(bookInputFromResponse(bookById), patch, bookPatchFields, &input)
+	if err != nil {
+		_log.Error().Err(err).Msg("applyMergePatch got an error on BookLibraryService.PatchBook")
+		return err
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if input.PublishedFlag == nil {
+		published := false
+		input.PublishedFlag = &published
+	}
+
+	merged := input
+	input, err = b.inheritFromWork(ctx, trx, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("b.inheritFromWork got an error on BookLibraryService.PatchBook")
+		return err
+	}
+	changed = appendInheritedColumns(changed, merged, input)
+
+	if err = b.validationInput(input); err != nil {
+		_log.Error().Err(err).Msg("b.validationInput got an error on BookLibraryService.PatchBook")
+		return err
+	}
+
+	err = b.bookRepo.UpdateBookLibrary(ctx, trx, id, input, changed...)
+	if err != nil {
+		_log.Error().Err(err).Msg("b.bookRepo.UpdateBookLibrary got an error on BookLibraryService.PatchBook")
+		return err
+	}
+
+	err = recordOutboxEvent(ctx, b.outboxRepo, trx, outbox.AggregateBook, id, webhook.EventBookUpdated, webhook.ResourcePayload{ID: id, Attributes: input})
+	if err != nil {
+		_log.Error().Err(err).Msg("recordOutboxEvent got an error on BookLibraryService.PatchBook")
+		return err
+	}
+
+	return err
+}
+
+// bookInputFromResponse is the stored book as an input, with unset references left nil.
+func bookInputFromResponse(resp book.BookResponse) book.BookInput {
+	input := book.BookInput{
+		Title:           resp.Title,
+		AuthorID:        resp.AuthorID,
+		Description:     resp.BoookDescription,
+		ISBN:            resp.ISBN,
+		PublishedFlag:   &resp.PublishedFlag,
+		CategoryID:      resp.CategoryID,
+		SeriesVolume:    resp.SeriesVolume,
+		PublicationYear: resp.PublicationYear,
+		Edition:         resp.Edition,
+		PageCount:       resp.PageCount,
+		Language:        resp.Language,
+	}
+
+	if resp.PublisherID != 0 {
+		input.PublisherID = &resp.PublisherID
+	}
+
+	if resp.SeriesID != 0 {
+		input.SeriesID = &resp.SeriesID
+	}
+
+	if resp.WorkID != 0 {
+		input.WorkID = &resp.WorkID
+	}
+
+	return input
+}
+
 // GetAllBooks implements BookLibraryServiceI.
-func (b BookLibraryService) GetAllBooks(ctx context.Context, search string) (resp []book.BookResponseDetail, err error) {
-	defer _track.TimeTrack(time.Now(), "GetAllBooks")
+func (b BookLibraryService) GetAllBooks(ctx context.Context, search book.BookSearch) (resp []book.BookResponseDetail, err error) {
+	ctx, end := _track.Track(ctx, "GetAllBooks")
+	defer end()
 	_log := _l.Ctx(ctx)
 
+	if err = validateBookSearch(search); err != nil {
+		_log.Error().Err(err).Msg("validateBookSearch got an error on BookLibraryService.GetAllBooks")
+		return resp, err
+	}
+
 	books, err := b.bookRepo.GetAllBookLibraries(ctx, search)
 	if err != nil {
 		_log.Error().Err(err).Msg("c.bookRepo.GetAllBookLibraries got an error on BookLibraryService.GetAllBooks")
@@ -134,11 +376,17 @@ func (b BookLibraryService) GetAllBooks(ctx context.Context, search string) (res
 	booksResp := []book.BookResponseDetail{}
 	for _, v := range books {
 		bookResp := book.BookResponseDetail{
-			ID:            v.ID,
-			Title:         v.Title,
-			Description:   v.BoookDescription,
-			ISBN:          v.ISBN,
-			PublishedFlag: v.PublishedFlag,
+			ID:              v.ID,
+			Title:           v.Title,
+			Description:     v.BoookDescription,
+			ISBN:            v.ISBN,
+			PublishedFlag:   v.PublishedFlag,
+			PublicationYear: v.PublicationYear,
+			Edition:         v.Edition,
+			PageCount:       v.PageCount,
+			Language:        v.Language,
+			RatingAverage:   v.RatingAverage,
+			RatingCount:     v.RatingCount,
 			Author: author.AuthorResponseJoin{
 				ID:    v.AuthorID,
 				Name:  v.AuthorName,
@@ -149,6 +397,19 @@ func (b BookLibraryService) GetAllBooks(ctx context.Context, search string) (res
 				Name:        v.CategoryName,
 				Description: v.CategoryDescription,
 			},
+			Publisher: publisher.PublisherResponseJoin{
+				ID:   v.PublisherID,
+				Name: v.PublisherName,
+			},
+			Series: series.SeriesResponseJoin{
+				ID:     v.SeriesID,
+				Name:   v.SeriesName,
+				Volume: v.SeriesVolume,
+			},
+			Work: work.WorkResponseJoin{
+				ID:    v.WorkID,
+				Title: v.WorkTitle,
+			},
 			CreatedAt: v.CreatedAt,
 			UpdatedAt: v.UpdatedAt,
 		}
@@ -159,9 +420,70 @@ func (b BookLibraryService) GetAllBooks(ctx context.Context, search string) (res
 	return booksResp, err
 }
 
+// SearchBooks implements BookLibraryServiceI.
+// It returns the books matching search with their category, author, publication year and
+// availability facets.
+func (b BookLibraryService) SearchBooks(ctx context.Context, search book.BookSearch) (resp book.BookSearchResponse, err error) {
+	ctx, end := _track.Track(ctx, "SearchBooks")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	books, err := b.GetAllBooks(ctx, search)
+	if err != nil {
+		_log.Error().Err(err).Msg("b.GetAllBooks got an error on BookLibraryService.SearchBooks")
+		return resp, err
+	}
+
+	facets, err := b.bookRepo.GetBookFacets(ctx, search)
+	if err != nil {
+		_log.Error().Err(err).Msg("b.bookRepo.GetBookFacets got an error on BookLibraryService.SearchBooks")
+		return resp, err
+	}
+
+	return book.BookSearchResponse{Books: books, Facets: facets}, nil
+}
+
+func validateBookSearch(search book.BookSearch) error {
+	switch search.Published {
+	case "", book.PublishedTrue, book.PublishedFalse, book.PublishedAny:
+	default:
+		return fmt.Errorf("Published %s is not supported, use %s, %s or %s", search.Published, book.PublishedTrue, book.PublishedFalse, book.PublishedAny)
+	}
+
+	if err := validateSimilarity(search.Similarity); err != nil {
+		return err
+	}
+
+	if search.CreatedFrom != nil && search.CreatedTo != nil && !search.CreatedFrom.Before(*search.CreatedTo) {
+		return errors.New("created_from must be before created_to")
+	}
+
+	if search.UpdatedFrom != nil && search.UpdatedTo != nil && !search.UpdatedFrom.Before(*search.UpdatedTo) {
+		return errors.New("updated_from must be before updated_to")
+	}
+
+	return nil
+}
+
+// GetAllBooksByWork implements BookLibraryServiceI.
+func (b BookLibraryService) GetAllBooksByWork(ctx context.Context, search book.BookSearch, expand bool) (resp []book.BookWorkGroup, err error) {
+	ctx, end := _track.Track(ctx, "GetAllBooksByWork")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	books, err := b.GetAllBooks(ctx, search)
+	if err != nil {
+		_log.Error().Err(err).Msg("b.GetAllBooks got an error on BookLibraryService.GetAllBooksByWork")
+		return resp, err
+	}
+
+	return groupBooksByWork(books, expand), err
+}
+
 // GetBookByID implements BookLibraryServiceI.
 func (b BookLibraryService) GetBookByID(ctx context.Context, id int64) (resp book.BookResponseDetail, err error) {
-	defer _track.TimeTrack(time.Now(), "GetAuthorByID")
+	ctx, end := _track.Track(ctx, "GetBookByID")
+	defer end()
 	_log := _l.Ctx(ctx)
 
 	if id == 0 {
@@ -169,7 +491,7 @@ func (b BookLibraryService) GetBookByID(ctx context.Context, id int64) (resp boo
 		return resp, errors.New("BookID cannot be nol")
 	}
 
-	bookById, err := b.bookRepo.GetBookLibraryById(ctx, id, 0, 0)
+	bookById, err := b.bookRepo.GetBookLibraryById(ctx, nil, id, 0, 0, 0)
 	if err != nil {
 		_log.Error().Err(err).Msg("b.bookRepo.GetBookLibraryById got an error on BookLibraryService.GetBookByID")
 		return resp, err
@@ -180,7 +502,7 @@ func (b BookLibraryService) GetBookByID(ctx context.Context, id int64) (resp boo
 		return resp, errors.New("Book not found")
 	}
 
-	authorById, err := b.authorRepo.GetAuthorById(ctx, bookById.AuthorID, "")
+	authorById, err := b.authorRepo.GetAuthorById(ctx, nil, bookById.AuthorID, "")
 	if err != nil {
 		_log.Error().Err(err).Msg("b.authorRepo.GetAuthorById got an error on BookLibraryService.GetBookByID")
 		return resp, err
@@ -191,7 +513,7 @@ func (b BookLibraryService) GetBookByID(ctx context.Context, id int64) (resp boo
 		return resp, errors.New("Author not found")
 	}
 
-	categoryById, err := b.categoryRepo.GetCategoryById(ctx, bookById.CategoryID, "")
+	categoryById, err := b.categoryRepo.GetCategoryById(ctx, nil, bookById.CategoryID, "")
 	if err != nil {
 		_log.Error().Err(err).Msg("b.categoryRepo.GetCategoryById got an error on BookLibraryService.GetBookByID")
 		return resp, err
@@ -202,12 +524,45 @@ func (b BookLibraryService) GetBookByID(ctx context.Context, id int64) (resp boo
 		return resp, errors.New("Category not found")
 	}
 
+	var publisherById publisher.PublisherResponse
+	if bookById.PublisherID != 0 {
+		publisherById, err = b.publisherRepo.GetPublisherById(ctx, nil, bookById.PublisherID, "")
+		if err != nil {
+			_log.Error().Err(err).Msg("b.publisherRepo.GetPublisherById got an error on BookLibraryService.GetBookByID")
+			return resp, err
+		}
+	}
+
+	var seriesById series.SeriesResponse
+	if bookById.SeriesID != 0 {
+		seriesById, err = b.seriesRepo.GetSeriesById(ctx, nil, bookById.SeriesID, "")
+		if err != nil {
+			_log.Error().Err(err).Msg("b.seriesRepo.GetSeriesById got an error on BookLibraryService.GetBookByID")
+			return resp, err
+		}
+	}
+
+	var workById work.WorkResponse
+	if bookById.WorkID != 0 {
+		workById, err = b.workRepo.GetWorkById(ctx, nil, bookById.WorkID)
+		if err != nil {
+			_log.Error().Err(err).Msg("b.workRepo.GetWorkById got an error on BookLibraryService.GetBookByID")
+			return resp, err
+		}
+	}
+
 	resp = book.BookResponseDetail{
-		ID:            bookById.ID,
-		Title:         bookById.Title,
-		Description:   bookById.BoookDescription,
-		ISBN:          bookById.ISBN,
-		PublishedFlag: bookById.PublishedFlag,
+		ID:              bookById.ID,
+		Title:           bookById.Title,
+		Description:     bookById.BoookDescription,
+		ISBN:            bookById.ISBN,
+		PublishedFlag:   bookById.PublishedFlag,
+		PublicationYear: bookById.PublicationYear,
+		Edition:         bookById.Edition,
+		PageCount:       bookById.PageCount,
+		Language:        bookById.Language,
+		RatingAverage:   bookById.RatingAverage,
+		RatingCount:     bookById.RatingCount,
 		Author: author.AuthorResponseJoin{
 			ID:    authorById.ID,
 			Name:  authorById.Name,
@@ -218,6 +573,19 @@ func (b BookLibraryService) GetBookByID(ctx context.Context, id int64) (resp boo
 			Name:        categoryById.Name,
 			Description: categoryById.Description,
 		},
+		Publisher: publisher.PublisherResponseJoin{
+			ID:   publisherById.ID,
+			Name: publisherById.Name,
+		},
+		Series: series.SeriesResponseJoin{
+			ID:     seriesById.ID,
+			Name:   seriesById.Name,
+			Volume: bookById.SeriesVolume,
+		},
+		Work: work.WorkResponseJoin{
+			ID:    workById.ID,
+			Title: workById.Title,
+		},
 		CreatedAt: bookById.CreatedAt,
 		UpdatedAt: bookById.UpdatedAt,
 	}
@@ -225,9 +593,57 @@ func (b BookLibraryService) GetBookByID(ctx context.Context, id int64) (resp boo
 	return resp, err
 }
 
+// GetSimilarBooks implements BookLibraryServiceI.
+func (b BookLibraryService) GetSimilarBooks(ctx context.Context, id int64, limit int) (resp []book.SimilarBookResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetSimilarBooks")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("BookID cannot be nol on BookLibraryService.GetSimilarBooks")
+		return resp, errors.New("BookID cannot be nol")
+	}
+
+	bookById, err := b.bookRepo.GetBookLibraryById(ctx, nil, id, 0, 0, 0)
+	if err != nil {
+		_log.Error().Err(err).Msg("b.bookRepo.GetBookLibraryById got an error on BookLibraryService.GetSimilarBooks")
+		return resp, err
+	}
+
+	if bookById.ID == 0 {
+		_log.Error().Msg("Book not found on BookLibraryService.GetSimilarBooks")
+		return resp, errors.New("Book not found")
+	}
+
+	candidates, err := b.bookRepo.GetSimilarBookCandidates(ctx, bookById)
+	if err != nil {
+		_log.Error().Err(err).Msg("b.bookRepo.GetSimilarBookCandidates got an error on BookLibraryService.GetSimilarBooks")
+		return resp, err
+	}
+
+	return rankSimilarBooks(bookById, candidates, limit), err
+}
+
 // DeleteBookByID implements BookLibraryServiceI.
 func (b BookLibraryService) DeleteBookByID(ctx context.Context, id int64) (err error) {
-	defer _track.TimeTrack(time.Now(), "DeleteBookByID")
+	ctx, end := _track.Track(ctx, "DeleteBookByID")
+	defer end()
+
+	trx := b.trRepo.BeginTransaction(ctx)
+
+	err = b.deleteBook(ctx, trx, id)
+	if err != nil {
+		b.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	b.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// deleteBook removes the book, which must exist, and records its outbox event in trx.
+func (b BookLibraryService) deleteBook(ctx context.Context, trx *gorm.DB, id int64) (err error) {
 	_log := _l.Ctx(ctx)
 
 	if id == 0 {
@@ -235,20 +651,233 @@ func (b BookLibraryService) DeleteBookByID(ctx context.Context, id int64) (err e
 		return errors.New("BookID cannot be nol")
 	}
 
-	trx := b.trRepo.BeginTransaction(ctx)
+	bookById, err := b.bookRepo.GetBookLibraryById(ctx, trx, id, 0, 0, 0)
+	if err != nil {
+		_log.Error().Err(err).Msg("b.bookRepo.GetBookLibraryById got an error on BookLibraryService.DeleteBookByID")
+		return err
+	}
+
+	if bookById.ID == 0 {
+		_log.Error().Msg("Book not found on BookLibraryService.DeleteBookByID")
+		return errors.New("Book not found")
+	}
 
 	err = b.bookRepo.DeleteBookLibrary(ctx, trx, id)
 	if err != nil {
 		_log.Error().Err(err).Msg("c.bookRepo.DeleteBookLibrary got an error on BookLibraryService.DeleteBookByID")
-		b.trRepo.RollBackTransaction(ctx, trx)
 		return err
 	}
 
-	b.trRepo.CommitTransaction(ctx, trx)
+	err = recordOutboxEvent(ctx, b.outboxRepo, trx, outbox.AggregateBook, id, webhook.EventBookDeleted, webhook.ResourcePayload{ID: id})
+	if err != nil {
+		_log.Error().Err(err).Msg("recordOutboxEvent got an error on BookLibraryService.DeleteBookByID")
+		return err
+	}
 
 	return err
 }
 
+// BatchBooks implements BookLibraryServiceI.
+func (b BookLibraryService) BatchBooks(ctx context.Context, input book.BookBatchInput) (resp batch.BatchResponse, err error) {
+	ctx, end := _track.Track(ctx, "BatchBooks")
+	defer end()
+
+	steps := make([]batchStep, 0, len(input.Operations))
+	for _, operation := range input.Operations {
+		step := batchStep{op: operation.Op}
+
+		switch operation.Op {
+		case batch.OpCreate:
+			step.run = func(ctx context.Context, trx *gorm.DB) (int64, error) {
+				return b.createBook(ctx, trx, operation.Data)
+			}
+		case batch.OpUpdate:
+			step.run = func(ctx context.Context, trx *gorm.DB) (int64, error) {
+				return operation.ID, b.updateBook(ctx, trx, operation.ID, operation.Data)
+			}
+		case batch.OpDelete:
+			step.run = func(ctx context.Context, trx *gorm.DB) (int64, error) {
+				return operation.ID, b.deleteBook(ctx, trx, operation.ID)
+			}
+		default:
+			step.run = unsupportedBatchOp(operation.Op)
+		}
+
+		steps = append(steps, step)
+	}
+
+	return runBatch(ctx, b.trRepo, input.Mode, steps)
+}
+
+// inheritFromWork fills the fields an edition leaves empty with the shared metadata of its work.
+func (b BookLibraryService) inheritFromWork(ctx context.Context, trx *gorm.DB, input book.BookInput) (book.BookInput, error) {
+	if input.WorkID == nil {
+		return input, nil
+	}
+
+	workById, err := b.workRepo.GetWorkById(ctx, trx, *input.WorkID)
+	if err != nil {
+		return input, err
+	}
+
+	if workById.ID == 0 {
+		return input, errors.New("Work not found")
+	}
+
+	if input.Title == "" {
+		input.Title = workById.Title
+	}
+
+	if input.Description == "" {
+		input.Description = workById.Description
+	}
+
+	if input.AuthorID == 0 {
+		input.AuthorID = workById.AuthorID
+	}
+
+	if input.CategoryID == 0 {
+		input.CategoryID = workById.CategoryID
+	}
+
+	if input.Language == "" {
+		input.Language = workById.OriginalLanguage
+	}
+
+	return input, nil
+}
+
+// appendInheritedColumns adds the columns inheritFromWork filled in after to changed, so a patch
+// writes the metadata it picked up from the work.
+func appendInheritedColumns(changed []string, before, after book.BookInput) []string {
+	inherited := map[string]bool{
+		"title":       before.Title != after.Title,
+		"description": before.Description != after.Description,
+		"author_id":   before.AuthorID != after.AuthorID,
+		"category_id": before.CategoryID != after.CategoryID,
+		"language":    before.Language != after.Language,
+	}
+
+	for _, column := range bookPatchFields {
+		if inherited[column] && !slices.Contains(changed, column) {
+			changed = append(changed, column)
+		}
+	}
+
+	return changed
+}
+
+// groupBooksByWork keeps the order of the first edition found for every work.
+// Without expand only that first edition is returned for each group.
+func groupBooksByWork(books []book.BookResponseDetail, expand bool) []book.BookWorkGroup {
+	groups := []book.BookWorkGroup{}
+	groupIndex := map[int64]int{}
+
+	for _, v := range books {
+		idx, ok := groupIndex[v.Work.ID]
+		if !ok || v.Work.ID == 0 {
+			groups = append(groups, book.BookWorkGroup{
+				WorkID:    v.Work.ID,
+				WorkTitle: v.Work.Title,
+				Edition:   v,
+			})
+			idx = len(groups) - 1
+
+			if v.Work.ID != 0 {
+				groupIndex[v.Work.ID] = idx
+			}
+		}
+
+		groups[idx].EditionCount++
+		if expand {
+			groups[idx].Editions = append(groups[idx].Editions, v)
+		}
+	}
+
+	return groups
+}
+
+const (
+	similarAuthorWeight   = 3.0
+	similarSeriesWeight   = 2.5
+	similarCategoryWeight = 2.0
+	similarRatingWeight   = 0.1
+
+	defaultSimilarLimit = 10
+	maxSimilarLimit     = 50
+)
+
+// similarityScore is a pure function of the two books so the ranking can be checked offline.
+// Shared series and author weigh more than a shared category; the rating only breaks near ties.
+// Shared tags and co-borrowing are not scored: the schema has no tags and no loans to read them from.
+func similarityScore(target, candidate book.BookResponse) (score float64, reasons []string) {
+	reasons = []string{}
+
+	if candidate.AuthorID != 0 && candidate.AuthorID == target.AuthorID {
+		score += similarAuthorWeight
+		reasons = append(reasons, "same_author")
+	}
+
+	if candidate.SeriesID != 0 && candidate.SeriesID == target.SeriesID {
+		score += similarSeriesWeight
+		reasons = append(reasons, "same_series")
+	}
+
+	if candidate.CategoryID != 0 && candidate.CategoryID == target.CategoryID {
+		score += similarCategoryWeight
+		reasons = append(reasons, "same_category")
+	}
+
+	score += candidate.RatingAverage * similarRatingWeight
+
+	return score, reasons
+}
+
+// rankSimilarBooks orders candidates by score, then by id so equal scores always come back the same way.
+func rankSimilarBooks(target book.BookResponse, candidates []book.BookResponse, limit int) []book.SimilarBookResponse {
+	if limit <= 0 {
+		limit = defaultSimilarLimit
+	}
+
+	if limit > maxSimilarLimit {
+		limit = maxSimilarLimit
+	}
+
+	ranked := []book.SimilarBookResponse{}
+	for _, v := range candidates {
+		score, reasons := similarityScore(target, v)
+		if len(reasons) == 0 {
+			continue
+		}
+
+		ranked = append(ranked, book.SimilarBookResponse{
+			ID:            v.ID,
+			Title:         v.Title,
+			AuthorID:      v.AuthorID,
+			AuthorName:    v.AuthorName,
+			CategoryID:    v.CategoryID,
+			CategoryName:  v.CategoryName,
+			RatingAverage: v.RatingAverage,
+			Score:         score,
+			Reasons:       reasons,
+		})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+
+		return ranked[i].ID < ranked[j].ID
+	})
+
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	return ranked
+}
+
 func (b BookLibraryService) validationInput(input book.BookInput) (err error) {
 	if input.AuthorID == 0 {
 		return errors.New("AuthorID can not be zero / 0")
@@ -270,5 +899,17 @@ func (b BookLibraryService) validationInput(input book.BookInput) (err error) {
 		return errors.New("ISBN can not be empty")
 	}
 
+	if input.PublicationYear < 0 {
+		return errors.New("PublicationYear can not be negative")
+	}
+
+	if input.PageCount < 0 {
+		return errors.New("PageCount can not be negative")
+	}
+
+	if input.SeriesID != nil && input.SeriesVolume <= 0 {
+		return errors.New("SeriesVolume must be greater than zero / 0 when SeriesID is set")
+	}
+
 	return nil
 }
@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/book-library/entity/book"
+)
+
+func TestSimilarityScore(t *testing.T) {
+	target := book.BookResponse{ID: 1, AuthorID: 10, SeriesID: 20, CategoryID: 30}
+
+	tests := []struct {
+		name      string
+		candidate book.BookResponse
+		score     float64
+		reasons   []string
+	}{
+		{
+			name:      "nothing shared",
+			candidate: book.BookResponse{ID: 2, AuthorID: 11, SeriesID: 21, CategoryID: 31},
+			score:     0,
+			reasons:   []string{},
+		},
+		{
+			name:      "same author",
+			candidate: book.BookResponse{ID: 2, AuthorID: 10},
+			score:     similarAuthorWeight,
+			reasons:   []string{"same_author"},
+		},
+		{
+			name:      "same series",
+			candidate: book.BookResponse{ID: 2, SeriesID: 20},
+			score:     similarSeriesWeight,
+			reasons:   []string{"same_series"},
+		},
+		{
+			name:      "same category",
+			candidate: book.BookResponse{ID: 2, CategoryID: 30},
+			score:     similarCategoryWeight,
+			reasons:   []string{"same_category"},
+		},
+		{
+			name:      "everything shared with a rating",
+			candidate: book.BookResponse{ID: 2, AuthorID: 10, SeriesID: 20, CategoryID: 30, RatingAverage: 4},
+			score:     similarAuthorWeight + similarSeriesWeight + similarCategoryWeight + 4*similarRatingWeight,
+			reasons:   []string{"same_author", "same_series", "same_category"},
+		},
+		{
+			name:      "rating alone is no reason",
+			candidate: book.BookResponse{ID: 2, RatingAverage: 5},
+			score:     5 * similarRatingWeight,
+			reasons:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, reasons := similarityScore(target, tt.candidate)
+			if math.Abs(score-tt.score) > 1e-9 {
+				t.Errorf("score = %v, want %v", score, tt.score)
+			}
+
+			if !reflect.DeepEqual(reasons, tt.reasons) {
+				t.Errorf("reasons = %v, want %v", reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestSimilarityScoreUnsetReferencesNeverMatch(t *testing.T) {
+	target := book.BookResponse{ID: 1, AuthorID: 10}
+
+	_, reasons := similarityScore(target, book.BookResponse{ID: 2, AuthorID: 10, SeriesID: 0, CategoryID: 0})
+	if !reflect.DeepEqual(reasons, []string{"same_author"}) {
+		t.Errorf("reasons = %v, want only same_author", reasons)
+	}
+}
+
+func TestRankSimilarBooks(t *testing.T) {
+	target := book.BookResponse{ID: 1, AuthorID: 10, SeriesID: 20, CategoryID: 30}
+
+	candidates := []book.BookResponse{
+		{ID: 7, CategoryID: 30},
+		{ID: 6, AuthorID: 10},
+		{ID: 5, SeriesID: 20},
+		{ID: 4, AuthorID: 10},
+		{ID: 3, AuthorID: 11},
+		{ID: 2, CategoryID: 30, RatingAverage: 4},
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		ids   []int64
+	}{
+		{
+			// Author beats series beats category, equal scores go by id and a rating lifts a
+			// book above its equals. Book 3 shares nothing and is left out.
+			name:  "default limit",
+			limit: 0,
+			ids:   []int64{4, 6, 5, 2, 7},
+		},
+		{
+			name:  "limit",
+			limit: 2,
+			ids:   []int64{4, 6},
+		},
+		{
+			name:  "limit above the maximum",
+			limit: maxSimilarLimit + 1,
+			ids:   []int64{4, 6, 5, 2, 7},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranked := rankSimilarBooks(target, candidates, tt.limit)
+
+			ids := []int64{}
+			for _, v := range ranked {
+				ids = append(ids, v.ID)
+			}
+
+			if !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("ids = %v, want %v", ids, tt.ids)
+			}
+		})
+	}
+}
+
+func TestRankSimilarBooksTiesAreStable(t *testing.T) {
+	target := book.BookResponse{ID: 1, AuthorID: 10}
+	candidates := []book.BookResponse{{ID: 9, AuthorID: 10}, {ID: 3, AuthorID: 10}, {ID: 5, AuthorID: 10}}
+
+	for i := 0; i < 10; i++ {
+		ranked := rankSimilarBooks(target, candidates, 0)
+		if ranked[0].ID != 3 || ranked[1].ID != 5 || ranked[2].ID != 9 {
+			t.Fatalf("ranked = %v, want ids 3, 5, 9", ranked)
+		}
+	}
+}
+
+func TestRankSimilarBooksCapsTheLimit(t *testing.T) {
+	target := book.BookResponse{ID: 1, AuthorID: 10}
+
+	candidates := []book.BookResponse{}
+	for i := int64(2); i < maxSimilarLimit+10; i++ {
+		candidates = append(candidates, book.BookResponse{ID: i, AuthorID: 10})
+	}
+
+	if got := len(rankSimilarBooks(target, candidates, 0)); got != defaultSimilarLimit {
+		t.Errorf("len with the default limit = %d, want %d", got, defaultSimilarLimit)
+	}
+
+	if got := len(rankSimilarBooks(target, candidates, maxSimilarLimit+5)); got != maxSimilarLimit {
+		t.Errorf("len above the maximum = %d, want %d", got, maxSimilarLimit)
+	}
+}
+
+func TestUpdatesKeepTheStoredDescription(t *testing.T) {
+	ctx := context.Background()
+	service := newTestSQLiteBookService(t)
+	published := true
+
+	err := service.CreateBook(ctx, book.BookInput{Title: "Ancillary Justice", Description: "Breq", AuthorID: 1, CategoryID: 1, ISBN: "9780316246620", PublishedFlag: &published})
+	if err != nil {
+		t.Fatalf("CreateBook: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		update func() error
+	}{
+		{name: "update", update: func() error { return service.UpdateBook(ctx, 1, book.BookInput{Edition: "3rd"}) }},
+		{name: "patch", update: func() error { return service.PatchBook(ctx, 1, []byte(`{"edition":"4th"}`)) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.update(); err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+
+			stored, err := service.GetBookByID(ctx, 1)
+			if err != nil || stored.Description != "Breq" {
+				t.Errorf("description = %q, %v, want the stored Breq", stored.Description, err)
+			}
+		})
+	}
+}
+
+func TestPatchInheritsFromTheWork(t *testing.T) {
+	ctx := context.Background()
+	service := newTestSQLiteBookService(t)
+	published := true
+
+	err := service.CreateBook(ctx, book.BookInput{Title: "Ancillary Justice", Description: "Breq", AuthorID: 1, CategoryID: 1, ISBN: "9780316246620", PublishedFlag: &published})
+	if err != nil {
+		t.Fatalf("CreateBook: %v", err)
+	}
+
+	if err = service.PatchBook(ctx, 1, []byte(`{"work_id":1}`)); err != nil {
+		t.Fatalf("PatchBook: %v", err)
+	}
+
+	stored, err := service.GetBookByID(ctx, 1)
+	if err != nil || stored.Work.ID != 1 || stored.Language != "en" {
+		t.Errorf("patched book = work %d in %q, %v, want work 1 in its language en", stored.Work.ID, stored.Language, err)
+	}
+}
@@ -5,62 +5,107 @@ import (
 	"errors"
 	"fmt"
 	"strings"
-	"time"
 
 	_track "github.com/book-library/app/helper"
 	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/batch"
 	"github.com/book-library/entity/category"
+	"github.com/book-library/entity/outbox"
+	"github.com/book-library/entity/webhook"
 	_l "github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
 type CategoryServiceI interface {
 	CreateCategory(ctx context.Context, input category.CategoryInput) (err error)
 	UpdateCategory(ctx context.Context, id int64, input category.CategoryInput) (err error)
+	PatchCategory(ctx context.Context, id int64, patch []byte) (err error)
 	GetCategoryByID(ctx context.Context, id int64) (resp category.CategoryResponse, err error)
-	GetAllCategories(ctx context.Context, name string) (resp []category.CategoryResponse, err error)
+	GetAllCategories(ctx context.Context, name string, similarity float64) (resp []category.CategoryResponse, err error)
+	GetCategoriesByIDs(ctx context.Context, ids []int64) (resp []category.CategoryResponse, err error)
 	DeleteCategoryByID(ctx context.Context, id int64) (err error)
+	BatchCategories(ctx context.Context, input category.CategoryBatchInput) (resp batch.BatchResponse, err error)
 }
 
 type CategoryService struct {
 	categoryRepo _r.CategoryRepositoryI
 	trRepo       _r.TransactionRepositoryI
 	bookRepo     _r.BookLibraryRepositoryI
+	outboxRepo   _r.OutboxRepositoryI
 }
 
-func NewCategoryService(categoryRepo _r.CategoryRepositoryI, trRepo _r.TransactionRepositoryI, bookRepo _r.BookLibraryRepositoryI) CategoryServiceI {
+func NewCategoryService(categoryRepo _r.CategoryRepositoryI, trRepo _r.TransactionRepositoryI, bookRepo _r.BookLibraryRepositoryI, outboxRepo _r.OutboxRepositoryI) CategoryServiceI {
 	return CategoryService{
 		categoryRepo: categoryRepo,
 		trRepo:       trRepo,
 		bookRepo:     bookRepo,
+		outboxRepo:   outboxRepo,
 	}
 }
 
 // CreateCategory implements CategoryServiceI.
 func (c CategoryService) CreateCategory(ctx context.Context, input category.CategoryInput) (err error) {
-	defer _track.TimeTrack(time.Now(), "CreateCategoryUC")
+	ctx, end := _track.Track(ctx, "CreateCategoryUC")
+	defer end()
+
+	trx := c.trRepo.BeginTransaction(ctx)
+
+	_, err = c.createCategory(ctx, trx, input)
+	if err != nil {
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	c.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// createCategory validates input, rejects a duplicate name and stores it with its outbox
+// event in trx.
+func (c CategoryService) createCategory(ctx context.Context, trx *gorm.DB, input category.CategoryInput) (id int64, err error) {
 	_log := _l.Ctx(ctx)
 
 	if c.validationInput(input); err != nil {
 		_log.Error().Err(err).Msg("c.validationInput got an error on CategoryService.CreateCategory")
-		return err
+		return id, err
 	}
 
-	byID, err := c.categoryRepo.GetCategoryById(ctx, 0, strings.ToLower(input.Name))
+	byID, err := c.categoryRepo.GetCategoryById(ctx, trx, 0, strings.ToLower(input.Name))
 	if err != nil {
 		_log.Error().Err(err).Msg("c.categoryRepo.GetCategoryById got an error on CategoryService.CreateCategory")
-		return err
+		return id, err
 	}
 
 	if byID.ID != 0 {
 		_log.Error().Err(err).Msgf("Category %s is already exist", byID.Name)
-		return fmt.Errorf("Category %s is already exist", byID.Name)
+		return id, fmt.Errorf("Category %s is already exist", byID.Name)
+	}
+
+	id, err = c.categoryRepo.CreateCategory(ctx, trx, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.categoryRepo.CreateCategory got an error on CategoryService.CreateCategory")
+		return id, err
+	}
+
+	err = recordOutboxEvent(ctx, c.outboxRepo, trx, outbox.AggregateCategory, id, webhook.EventCategoryCreated, webhook.ResourcePayload{ID: id, Attributes: input})
+	if err != nil {
+		_log.Error().Err(err).Msg("recordOutboxEvent got an error on CategoryService.CreateCategory")
+		return id, err
 	}
 
+	return id, err
+}
+
+// DeleteCategoryByID implements CategoryServiceI.
+func (c CategoryService) DeleteCategoryByID(ctx context.Context, id int64) (err error) {
+	ctx, end := _track.Track(ctx, "DeleteCategoryByIDUC")
+	defer end()
+
 	trx := c.trRepo.BeginTransaction(ctx)
 
-	err = c.categoryRepo.CreateCategory(ctx, trx, input)
+	err = c.deleteCategory(ctx, trx, id)
 	if err != nil {
-		_log.Error().Err(err).Msg("c.categoryRepo.CreateCategory got an error on CategoryService.CreateCategory")
 		c.trRepo.RollBackTransaction(ctx, trx)
 		return err
 	}
@@ -70,12 +115,23 @@ func (c CategoryService) CreateCategory(ctx context.Context, input category.Cate
 	return err
 }
 
-// DeleteCategoryByID implements CategoryServiceI.
-func (c CategoryService) DeleteCategoryByID(ctx context.Context, id int64) (err error) {
-	defer _track.TimeTrack(time.Now(), "DeleteCategoryByIDUC")
+// deleteCategory refuses to remove a category that still has books, otherwise deletes it and
+// records its outbox event in trx.
+func (c CategoryService) deleteCategory(ctx context.Context, trx *gorm.DB, id int64) (err error) {
 	_log := _l.Ctx(ctx)
 
-	bookByID, err := c.bookRepo.GetBookLibraryById(ctx, 0, 0, id)
+	catById, err := c.categoryRepo.GetCategoryById(ctx, trx, id, "")
+	if err != nil {
+		_log.Error().Err(err).Msg("c.categoryRepo.GetCategoryById got an error on CategoryService.DeleteCategoryByID")
+		return err
+	}
+
+	if catById.ID == 0 {
+		_log.Error().Msg("Category not found on CategoryService.DeleteCategoryByID")
+		return errors.New("Category not found")
+	}
+
+	bookByID, err := c.bookRepo.GetBookLibraryById(ctx, trx, 0, 0, id, 0)
 	if err != nil {
 		_log.Error().Err(err).Msg("c.bookRepo.GetBookLibraryById got an error on CategoryService.DeleteCategoryByID")
 		return err
@@ -86,26 +142,33 @@ func (c CategoryService) DeleteCategoryByID(ctx context.Context, id int64) (err
 		return fmt.Errorf("There is book(%s) using this category and delete the book(%s) first before delete category", bookByID.Title, bookByID.Title)
 	}
 
-	trx := c.trRepo.BeginTransaction(ctx)
-
 	err = c.categoryRepo.DeleteCategory(ctx, trx, id)
 	if err != nil {
 		_log.Error().Err(err).Msg("c.categoryRepo.DeleteCategory got an error on CategoryService.DeleteCategoryByID")
-		c.trRepo.RollBackTransaction(ctx, trx)
 		return err
 	}
 
-	c.trRepo.CommitTransaction(ctx, trx)
+	err = recordOutboxEvent(ctx, c.outboxRepo, trx, outbox.AggregateCategory, id, webhook.EventCategoryDeleted, webhook.ResourcePayload{ID: id})
+	if err != nil {
+		_log.Error().Err(err).Msg("recordOutboxEvent got an error on CategoryService.DeleteCategoryByID")
+		return err
+	}
 
 	return err
 }
 
 // GetAllCategories implements CategoryServiceI.
-func (c CategoryService) GetAllCategories(ctx context.Context, name string) (resp []category.CategoryResponse, err error) {
-	defer _track.TimeTrack(time.Now(), "GetAllCategoriesUC")
+func (c CategoryService) GetAllCategories(ctx context.Context, name string, similarity float64) (resp []category.CategoryResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetAllCategoriesUC")
+	defer end()
 	_log := _l.Ctx(ctx)
 
-	categories, err := c.categoryRepo.GetAllCategories(ctx, name)
+	if err = validateSimilarity(similarity); err != nil {
+		_log.Error().Err(err).Msg("validateSimilarity got an error on CategoryService.GetAllCategories")
+		return resp, err
+	}
+
+	categories, err := c.categoryRepo.GetAllCategories(ctx, name, similarity)
 	if err != nil {
 		_log.Error().Err(err).Msg("c.categoryRepo.GetAllCategories got an error on CategoryService.GetAllCategories")
 		return resp, err
@@ -116,7 +179,8 @@ func (c CategoryService) GetAllCategories(ctx context.Context, name string) (res
 
 // GetCategoryByID implements CategoryServiceI.
 func (c CategoryService) GetCategoryByID(ctx context.Context, id int64) (resp category.CategoryResponse, err error) {
-	defer _track.TimeTrack(time.Now(), "GetCategoryByIDUC")
+	ctx, end := _track.Track(ctx, "GetCategoryByIDUC")
+	defer end()
 	_log := _l.Ctx(ctx)
 
 	if id == 0 {
@@ -124,7 +188,7 @@ func (c CategoryService) GetCategoryByID(ctx context.Context, id int64) (resp ca
 		return resp, errors.New("CategoryID cannot be nol")
 	}
 
-	catById, err := c.categoryRepo.GetCategoryById(ctx, id, "")
+	catById, err := c.categoryRepo.GetCategoryById(ctx, nil, id, "")
 	if err != nil {
 		_log.Error().Err(err).Msg("c.categoryRepo.GetCategoryById got an error on CategoryService.GetCategoryByID")
 		return resp, err
@@ -140,7 +204,25 @@ func (c CategoryService) GetCategoryByID(ctx context.Context, id int64) (resp ca
 
 // UpdateCategory implements CategoryServiceI.
 func (c CategoryService) UpdateCategory(ctx context.Context, id int64, input category.CategoryInput) (err error) {
-	defer _track.TimeTrack(time.Now(), "UpdateCategoryUC")
+	ctx, end := _track.Track(ctx, "UpdateCategoryUC")
+	defer end()
+
+	trx := c.trRepo.BeginTransaction(ctx)
+
+	err = c.updateCategory(ctx, trx, id, input)
+	if err != nil {
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	c.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// updateCategory fills the fields input leaves empty from the stored category and writes it
+// with its outbox event in trx.
+func (c CategoryService) updateCategory(ctx context.Context, trx *gorm.DB, id int64, input category.CategoryInput) (err error) {
 	_log := _l.Ctx(ctx)
 
 	if id == 0 {
@@ -148,7 +230,7 @@ func (c CategoryService) UpdateCategory(ctx context.Context, id int64, input cat
 		return errors.New("CategoryID cannot be nol")
 	}
 
-	catById, err := c.categoryRepo.GetCategoryById(ctx, id, "")
+	catById, err := c.categoryRepo.GetCategoryById(ctx, trx, id, "")
 	if err != nil {
 		_log.Error().Err(err).Msg("c.categoryRepo.GetCategoryById got an error on CategoryService.UpdateCategory")
 		return err
@@ -167,11 +249,34 @@ func (c CategoryService) UpdateCategory(ctx context.Context, id int64, input cat
 		input.Description = catById.Name
 	}
 
-	trx := c.trRepo.BeginTransaction(ctx)
-
 	err = c.categoryRepo.UpdateCategory(ctx, trx, id, input)
 	if err != nil {
 		_log.Error().Err(err).Msg("c.categoryRepo.UpdateCategory got an error on CategoryService.UpdateCategory")
+		return err
+	}
+
+	err = recordOutboxEvent(ctx, c.outboxRepo, trx, outbox.AggregateCategory, id, webhook.EventCategoryUpdated, webhook.ResourcePayload{ID: id, Attributes: input})
+	if err != nil {
+		_log.Error().Err(err).Msg("recordOutboxEvent got an error on CategoryService.UpdateCategory")
+		return err
+	}
+
+	return err
+}
+
+// categoryPatchFields are the CategoryInput fields, and tb_category columns, a merge patch may change.
+var categoryPatchFields = []string{"name", "description"}
+
+// PatchCategory implements CategoryServiceI.
+// The merged category is validated as a whole and only the columns that changed are written.
+func (c CategoryService) PatchCategory(ctx context.Context, id int64, patch []byte) (err error) {
+	ctx, end := _track.Track(ctx, "PatchCategoryUC")
+	defer end()
+
+	trx := c.trRepo.BeginTransaction(ctx)
+
+	err = c.patchCategory(ctx, trx, id, patch)
+	if err != nil {
 		c.trRepo.RollBackTransaction(ctx, trx)
 		return err
 	}
@@ -181,6 +286,109 @@ func (c CategoryService) UpdateCategory(ctx context.Context, id int64, input cat
 	return err
 }
 
+// patchCategory merges patch into the category read in trx and writes the columns that changed
+// with its outbox event in trx.
+func (c CategoryService) patchCategory(ctx context.Context, trx *gorm.DB, id int64, patch []byte) (err error) {
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("CategoryID cannot be nol on CategoryService.PatchCategory")
+		return errors.New("CategoryID cannot be nol")
+	}
+
+	catById, err := c.categoryRepo.GetCategoryById(ctx, trx, id, "")
+	if err != nil {
+		_log.Error().Err(err).Msg("c.categoryRepo.GetCategoryById got an error on CategoryService.PatchCategory")
+		return err
+	}
+
+	if catById.ID == 0 {
+		_log.Error().Msg("Category not found on CategoryService.PatchCategory")
+		return errors.New("Category not found")
+	}
+
+	var input category.CategoryInput
+	changed, err := applyMergePatch(catById, patch, categoryPatchFields, &input)
+	if err != nil {
+		_log.Error().Err(err).Msg("applyMergePatch got an error on CategoryService.PatchCategory")
+		return err
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if err = c.validationInput(input); err != nil {
+		_log.Error().Err(err).Msg("c.validationInput got an error on CategoryService.PatchCategory")
+		return err
+	}
+
+	err = c.categoryRepo.UpdateCategory(ctx, trx, id, input, changed...)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.categoryRepo.UpdateCategory got an error on CategoryService.PatchCategory")
+		return err
+	}
+
+	err = recordOutboxEvent(ctx, c.outboxRepo, trx, outbox.AggregateCategory, id, webhook.EventCategoryUpdated, webhook.ResourcePayload{ID: id, Attributes: input})
+	if err != nil {
+		_log.Error().Err(err).Msg("recordOutboxEvent got an error on CategoryService.PatchCategory")
+		return err
+	}
+
+	return err
+}
+
+// BatchCategories implements CategoryServiceI.
+func (c CategoryService) BatchCategories(ctx context.Context, input category.CategoryBatchInput) (resp batch.BatchResponse, err error) {
+	ctx, end := _track.Track(ctx, "BatchCategoriesUC")
+	defer end()
+
+	steps := make([]batchStep, 0, len(input.Operations))
+	for _, operation := range input.Operations {
+		step := batchStep{op: operation.Op}
+
+		switch operation.Op {
+		case batch.OpCreate:
+			step.run = func(ctx context.Context, trx *gorm.DB) (int64, error) {
+				return c.createCategory(ctx, trx, operation.Data)
+			}
+		case batch.OpUpdate:
+			step.run = func(ctx context.Context, trx *gorm.DB) (int64, error) {
+				return operation.ID, c.updateCategory(ctx, trx, operation.ID, operation.Data)
+			}
+		case batch.OpDelete:
+			step.run = func(ctx context.Context, trx *gorm.DB) (int64, error) {
+				return operation.ID, c.deleteCategory(ctx, trx, operation.ID)
+			}
+		default:
+			step.run = unsupportedBatchOp(operation.Op)
+		}
+
+		steps = append(steps, step)
+	}
+
+	return runBatch(ctx, c.trRepo, input.Mode, steps)
+}
+
+// GetCategoriesByIDs implements CategoryServiceI.
+func (c CategoryService) GetCategoriesByIDs(ctx context.Context, ids []int64) (resp []category.CategoryResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetCategoriesByIDsUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if len(ids) == 0 {
+		return resp, nil
+	}
+
+	categorys, err := c.categoryRepo.GetCategoriesByIDs(ctx, ids)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.categoryRepo.GetCategoriesByIDs got an error on CategoryService.GetCategoriesByIDs")
+		return resp, err
+	}
+
+	return categorys, err
+}
+
 func (c CategoryService) validationInput(input category.CategoryInput) (err error) {
 	if input.Name == "" {
 		return errors.New("Name can not be empty")
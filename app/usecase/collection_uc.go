@@ -0,0 +1,467 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	_track "github.com/book-library/app/helper"
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/collection"
+	_l "github.com/rs/zerolog/log"
+)
+
+type CollectionServiceI interface {
+	CreateCollection(ctx context.Context, input collection.CollectionInput) (err error)
+	UpdateCollection(ctx context.Context, id int64, input collection.CollectionInput) (err error)
+	GetCollectionByID(ctx context.Context, id, memberID int64) (resp collection.CollectionResponseDetail, err error)
+	GetAllCollections(ctx context.Context, search collection.CollectionSearch) (resp []collection.CollectionResponse, err error)
+	GetFeaturedCollections(ctx context.Context) (resp []collection.CollectionResponse, err error)
+	DeleteCollectionByID(ctx context.Context, id int64) (err error)
+
+	AddCollectionBook(ctx context.Context, id int64, input collection.CollectionEntryInput) (err error)
+	UpdateCollectionBook(ctx context.Context, id, bookID int64, input collection.CollectionEntryInput) (err error)
+	RemoveCollectionBook(ctx context.Context, id, bookID int64) (err error)
+}
+
+type CollectionService struct {
+	collectionRepo _r.CollectionRepositoryI
+	trRepo         _r.TransactionRepositoryI
+	bookRepo       _r.BookLibraryRepositoryI
+	memberRepo     _r.MemberRepositoryI
+}
+
+func NewCollectionService(collectionRepo _r.CollectionRepositoryI, trRepo _r.TransactionRepositoryI, bookRepo _r.BookLibraryRepositoryI, memberRepo _r.MemberRepositoryI) CollectionServiceI {
+	return CollectionService{
+		collectionRepo: collectionRepo,
+		trRepo:         trRepo,
+		bookRepo:       bookRepo,
+		memberRepo:     memberRepo,
+	}
+}
+
+// CreateCollection implements CollectionServiceI.
+// A collection without an owner belongs to the library and is curated by librarians.
+func (c CollectionService) CreateCollection(ctx context.Context, input collection.CollectionInput) (err error) {
+	ctx, end := _track.Track(ctx, "CreateCollectionUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if input.Visibility == "" {
+		input.Visibility = collection.VisibilityPublic
+	}
+
+	if input.FeaturedFlag == nil {
+		featured := false
+		input.FeaturedFlag = &featured
+	}
+
+	if err = c.validationInput(ctx, input); err != nil {
+		_log.Error().Err(err).Msg("c.validationInput got an error on CollectionService.CreateCollection")
+		return err
+	}
+
+	trx := c.trRepo.BeginTransaction(ctx)
+
+	err = c.collectionRepo.CreateCollection(ctx, trx, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.CreateCollection got an error on CollectionService.CreateCollection")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	c.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// UpdateCollection implements CollectionServiceI.
+func (c CollectionService) UpdateCollection(ctx context.Context, id int64, input collection.CollectionInput) (err error) {
+	ctx, end := _track.Track(ctx, "UpdateCollectionUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("CollectionID cannot be nol on CollectionService.UpdateCollection")
+		return errors.New("CollectionID cannot be nol")
+	}
+
+	collectionById, err := c.collectionRepo.GetCollectionById(ctx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.GetCollectionById got an error on CollectionService.UpdateCollection")
+		return err
+	}
+
+	if collectionById.ID == 0 {
+		_log.Error().Msg("Collection not found on CollectionService.UpdateCollection")
+		return errors.New("Collection not found")
+	}
+
+	if input.Name == "" {
+		input.Name = collectionById.Name
+	}
+
+	if input.Description == "" {
+		input.Description = collectionById.Description
+	}
+
+	if input.OwnerID == nil && collectionById.OwnerID != 0 {
+		input.OwnerID = &collectionById.OwnerID
+	}
+
+	if input.Visibility == "" {
+		input.Visibility = collectionById.Visibility
+	}
+
+	if input.FeaturedFlag == nil {
+		input.FeaturedFlag = &collectionById.FeaturedFlag
+	}
+
+	if err = c.validationInput(ctx, input); err != nil {
+		_log.Error().Err(err).Msg("c.validationInput got an error on CollectionService.UpdateCollection")
+		return err
+	}
+
+	trx := c.trRepo.BeginTransaction(ctx)
+
+	err = c.collectionRepo.UpdateCollection(ctx, trx, id, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.UpdateCollection got an error on CollectionService.UpdateCollection")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	c.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// GetAllCollections implements CollectionServiceI.
+// Private collections are only listed when the search is scoped to their owner and made by them.
+func (c CollectionService) GetAllCollections(ctx context.Context, search collection.CollectionSearch) (resp []collection.CollectionResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetAllCollectionsUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if search.OwnerID == 0 || search.OwnerID != search.MemberID {
+		search.Visibility = collection.VisibilityPublic
+	}
+
+	collections, err := c.collectionRepo.GetAllCollections(ctx, search)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.GetAllCollections got an error on CollectionService.GetAllCollections")
+		return resp, err
+	}
+
+	return collections, err
+}
+
+// GetFeaturedCollections implements CollectionServiceI.
+func (c CollectionService) GetFeaturedCollections(ctx context.Context) (resp []collection.CollectionResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetFeaturedCollectionsUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	search := collection.CollectionSearch{
+		Visibility:   collection.VisibilityPublic,
+		FeaturedOnly: true,
+	}
+
+	collections, err := c.collectionRepo.GetAllCollections(ctx, search)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.GetAllCollections got an error on CollectionService.GetFeaturedCollections")
+		return resp, err
+	}
+
+	return collections, err
+}
+
+// GetCollectionByID implements CollectionServiceI.
+// A private collection is only found for its owner, memberID.
+func (c CollectionService) GetCollectionByID(ctx context.Context, id, memberID int64) (resp collection.CollectionResponseDetail, err error) {
+	ctx, end := _track.Track(ctx, "GetCollectionByIDUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("CollectionID cannot be nol on CollectionService.GetCollectionByID")
+		return resp, errors.New("CollectionID cannot be nol")
+	}
+
+	collectionById, err := c.collectionRepo.GetCollectionById(ctx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.GetCollectionById got an error on CollectionService.GetCollectionByID")
+		return resp, err
+	}
+
+	if collectionById.ID == 0 || (collectionById.Visibility == collection.VisibilityPrivate && (memberID == 0 || collectionById.OwnerID != memberID)) {
+		_log.Error().Msg("Collection not found on CollectionService.GetCollectionByID")
+		return resp, errors.New("Collection not found")
+	}
+
+	entries, err := c.collectionRepo.GetCollectionEntries(ctx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.GetCollectionEntries got an error on CollectionService.GetCollectionByID")
+		return resp, err
+	}
+
+	resp = collection.CollectionResponseDetail{
+		ID:           collectionById.ID,
+		Name:         collectionById.Name,
+		Description:  collectionById.Description,
+		OwnerID:      collectionById.OwnerID,
+		OwnerName:    collectionById.OwnerName,
+		Visibility:   collectionById.Visibility,
+		FeaturedFlag: collectionById.FeaturedFlag,
+		Books:        entries,
+		CreatedAt:    collectionById.CreatedAt,
+		UpdatedAt:    collectionById.UpdatedAt,
+	}
+
+	return resp, err
+}
+
+// DeleteCollectionByID implements CollectionServiceI.
+func (c CollectionService) DeleteCollectionByID(ctx context.Context, id int64) (err error) {
+	ctx, end := _track.Track(ctx, "DeleteCollectionByIDUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("CollectionID cannot be nol on CollectionService.DeleteCollectionByID")
+		return errors.New("CollectionID cannot be nol")
+	}
+
+	trx := c.trRepo.BeginTransaction(ctx)
+
+	err = c.collectionRepo.DeleteCollection(ctx, trx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.DeleteCollection got an error on CollectionService.DeleteCollectionByID")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	c.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// AddCollectionBook implements CollectionServiceI.
+// Books after the requested position move down by one to make room.
+func (c CollectionService) AddCollectionBook(ctx context.Context, id int64, input collection.CollectionEntryInput) (err error) {
+	ctx, end := _track.Track(ctx, "AddCollectionBookUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	collectionById, err := c.collectionRepo.GetCollectionById(ctx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.GetCollectionById got an error on CollectionService.AddCollectionBook")
+		return err
+	}
+
+	if collectionById.ID == 0 {
+		_log.Error().Msg("Collection not found on CollectionService.AddCollectionBook")
+		return errors.New("Collection not found")
+	}
+
+	bookById, err := c.bookRepo.GetBookLibraryById(ctx, nil, input.BookID, 0, 0, 0)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.bookRepo.GetBookLibraryById got an error on CollectionService.AddCollectionBook")
+		return err
+	}
+
+	if input.BookID == 0 || bookById.ID == 0 {
+		_log.Error().Msg("Book not found on CollectionService.AddCollectionBook")
+		return errors.New("Book not found")
+	}
+
+	trx := c.trRepo.BeginTransaction(ctx)
+
+	lastPosition, err := c.collectionRepo.GetCollectionLastPosition(ctx, trx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.GetCollectionLastPosition got an error on CollectionService.AddCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	existing, err := c.collectionRepo.GetCollectionEntry(ctx, trx, id, input.BookID)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.GetCollectionEntry got an error on CollectionService.AddCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	if existing.BookID != 0 {
+		_log.Error().Msg("Book is already in the collection on CollectionService.AddCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return errors.New("Book is already in the collection")
+	}
+
+	input.CollectionID = id
+	if input.Position <= 0 || input.Position > lastPosition {
+		input.Position = lastPosition + 1
+	} else {
+		err = c.collectionRepo.ShiftCollectionEntries(ctx, trx, id, input.Position, 1)
+		if err != nil {
+			_log.Error().Err(err).Msg("c.collectionRepo.ShiftCollectionEntries got an error on CollectionService.AddCollectionBook")
+			c.trRepo.RollBackTransaction(ctx, trx)
+			return err
+		}
+	}
+
+	err = c.collectionRepo.CreateCollectionEntry(ctx, trx, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.CreateCollectionEntry got an error on CollectionService.AddCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	c.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// UpdateCollectionBook implements CollectionServiceI.
+// Moving a book closes the gap at its old position before opening one at the new position.
+func (c CollectionService) UpdateCollectionBook(ctx context.Context, id, bookID int64, input collection.CollectionEntryInput) (err error) {
+	ctx, end := _track.Track(ctx, "UpdateCollectionBookUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	trx := c.trRepo.BeginTransaction(ctx)
+
+	lastPosition, err := c.collectionRepo.GetCollectionLastPosition(ctx, trx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.GetCollectionLastPosition got an error on CollectionService.UpdateCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	entry, err := c.collectionRepo.GetCollectionEntry(ctx, trx, id, bookID)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.GetCollectionEntry got an error on CollectionService.UpdateCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	if entry.BookID == 0 {
+		_log.Error().Msg("Book is not in the collection on CollectionService.UpdateCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return errors.New("Book is not in the collection")
+	}
+
+	input.CollectionID = id
+	input.BookID = bookID
+
+	if input.Note == "" {
+		input.Note = entry.Note
+	}
+
+	if input.Position <= 0 {
+		input.Position = entry.Position
+	}
+
+	if input.Position > lastPosition {
+		input.Position = lastPosition
+	}
+
+	if input.Position != entry.Position {
+		moving := collection.CollectionEntryInput{CollectionID: id, BookID: bookID, Position: 0, Note: entry.Note}
+		err = c.collectionRepo.UpdateCollectionEntry(ctx, trx, moving)
+		if err == nil {
+			err = c.collectionRepo.ShiftCollectionEntries(ctx, trx, id, entry.Position+1, -1)
+		}
+		if err == nil {
+			err = c.collectionRepo.ShiftCollectionEntries(ctx, trx, id, input.Position, 1)
+		}
+		if err != nil {
+			_log.Error().Err(err).Msg("c.collectionRepo.ShiftCollectionEntries got an error on CollectionService.UpdateCollectionBook")
+			c.trRepo.RollBackTransaction(ctx, trx)
+			return err
+		}
+	}
+
+	err = c.collectionRepo.UpdateCollectionEntry(ctx, trx, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.UpdateCollectionEntry got an error on CollectionService.UpdateCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	c.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// RemoveCollectionBook implements CollectionServiceI.
+func (c CollectionService) RemoveCollectionBook(ctx context.Context, id, bookID int64) (err error) {
+	ctx, end := _track.Track(ctx, "RemoveCollectionBookUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	trx := c.trRepo.BeginTransaction(ctx)
+
+	_, err = c.collectionRepo.GetCollectionLastPosition(ctx, trx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.GetCollectionLastPosition got an error on CollectionService.RemoveCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	entry, err := c.collectionRepo.GetCollectionEntry(ctx, trx, id, bookID)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.GetCollectionEntry got an error on CollectionService.RemoveCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	if entry.BookID == 0 {
+		_log.Error().Msg("Book is not in the collection on CollectionService.RemoveCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return errors.New("Book is not in the collection")
+	}
+
+	err = c.collectionRepo.DeleteCollectionEntry(ctx, trx, id, bookID)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.DeleteCollectionEntry got an error on CollectionService.RemoveCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	err = c.collectionRepo.ShiftCollectionEntries(ctx, trx, id, entry.Position+1, -1)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.collectionRepo.ShiftCollectionEntries got an error on CollectionService.RemoveCollectionBook")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	c.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+func (c CollectionService) validationInput(ctx context.Context, input collection.CollectionInput) (err error) {
+	if input.Name == "" {
+		return errors.New("Name can not be empty")
+	}
+
+	if input.Visibility != collection.VisibilityPublic && input.Visibility != collection.VisibilityPrivate {
+		return errors.New("Visibility must be public or private")
+	}
+
+	if input.FeaturedFlag != nil && *input.FeaturedFlag && input.Visibility != collection.VisibilityPublic {
+		return errors.New("Only public collection can be featured")
+	}
+
+	if input.OwnerID != nil {
+		memberById, err := c.memberRepo.GetMemberById(ctx, *input.OwnerID, "")
+		if err != nil {
+			return err
+		}
+
+		if memberById.ID == 0 {
+			return errors.New("Owner not found")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/collection"
+	"github.com/book-library/entity/member"
+)
+
+func TestPrivateCollectionsAreOnlyShownToTheirOwner(t *testing.T) {
+	ctx := context.Background()
+	store := _r.NewMemoryStore()
+	memberRepo := _r.NewMemoryMemberRepository(store)
+	service := NewCollectionService(_r.NewMemoryCollectionRepository(store), _r.NewMemoryTransactionRepository(store),
+		_r.NewMemoryBookLibraryRepository(store), memberRepo)
+
+	for _, input := range []member.MemberInput{
+		{Name: "Reader", Email: "reader@example.com"},
+		{Name: "Critic", Email: "critic@example.com"},
+	} {
+		if err := memberRepo.CreateMember(ctx, nil, input); err != nil {
+			t.Fatalf("CreateMember: %v", err)
+		}
+	}
+
+	owner := int64(1)
+	for _, input := range []collection.CollectionInput{
+		{Name: "Wishlist", OwnerID: &owner, Visibility: collection.VisibilityPrivate},
+		{Name: "Favourites", OwnerID: &owner, Visibility: collection.VisibilityPublic},
+	} {
+		if err := service.CreateCollection(ctx, input); err != nil {
+			t.Fatalf("CreateCollection: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		memberID int64
+		found    bool
+		names    []string
+	}{
+		{name: "owner", memberID: 1, found: true, names: []string{"Wishlist", "Favourites"}},
+		{name: "other member", memberID: 2, names: []string{"Favourites"}},
+		{name: "anonymous", names: []string{"Favourites"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := service.GetCollectionByID(ctx, 1, tt.memberID)
+			if found := err == nil && resp.ID == 1; found != tt.found {
+				t.Errorf("GetCollectionByID = %+v, %v, want found %v", resp, err, tt.found)
+			}
+
+			collections, err := service.GetAllCollections(ctx, collection.CollectionSearch{OwnerID: owner, MemberID: tt.memberID})
+			if err != nil {
+				t.Fatalf("GetAllCollections: %v", err)
+			}
+
+			names := []string{}
+			for _, c := range collections {
+				names = append(names, c.Name)
+			}
+
+			if !reflect.DeepEqual(names, tt.names) {
+				t.Errorf("names = %v, want %v", names, tt.names)
+			}
+		})
+	}
+}
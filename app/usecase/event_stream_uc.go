@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/outbox"
+	_l "github.com/rs/zerolog/log"
+)
+
+type EventStreamServiceI interface {
+	Stream(ctx context.Context, lastEventID int64, aggregateTypes []string) (events <-chan outbox.OutboxEvent, err error)
+	Close()
+}
+
+// EventStreamConfig controls the streams. Published events are read PageSize at a time, and
+// looked for every PollInterval; a stream that falls BufferSize events behind the others is
+// closed.
+type EventStreamConfig struct {
+	BufferSize   int
+	PageSize     int
+	PollInterval time.Duration
+}
+
+type EventStreamService struct {
+	outboxRepo _r.OutboxRepositoryI
+	config     EventStreamConfig
+	hub        *eventHub
+	done       chan struct{}
+	closeOnce  *sync.Once
+}
+
+// eventHub reads the published events once for all the streams of this instance and hands them
+// on. Reading the outbox rather than being a relay sink, streams see the events whichever
+// instance relayed them.
+type eventHub struct {
+	mu      sync.Mutex
+	started bool
+	nextID  int
+	streams map[int]*liveStream
+}
+
+type liveStream struct {
+	aggregateTypes []string
+	events         chan outbox.OutboxEvent
+	overflow       chan struct{}
+	overflowOnce   sync.Once
+}
+
+func NewEventStreamService(outboxRepo _r.OutboxRepositoryI, config EventStreamConfig) EventStreamServiceI {
+	if config.BufferSize <= 0 {
+		config.BufferSize = 64
+	}
+
+	if config.PageSize <= 0 {
+		config.PageSize = 500
+	}
+
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+
+	return EventStreamService{
+		outboxRepo: outboxRepo,
+		config:     config,
+		hub:        &eventHub{streams: map[int]*liveStream{}},
+		done:       make(chan struct{}),
+		closeOnce:  &sync.Once{},
+	}
+}
+
+// Stream implements EventStreamServiceI.
+// Events are streamed in publish order. With a lastEventID every event published after it is
+// replayed first, a page at a time until caught up, before live events follow. The channel is
+// closed when ctx ends, when Close is called, or when the client falls so far behind that its
+// buffer overflows; the client is then expected to reconnect with Last-Event-ID.
+func (e EventStreamService) Stream(ctx context.Context, lastEventID int64, aggregateTypes []string) (events <-chan outbox.OutboxEvent, err error) {
+	_log := _l.Ctx(ctx)
+
+	for _, aggregateType := range aggregateTypes {
+		if !slices.Contains(outbox.AggregateTypes, aggregateType) {
+			return nil, fmt.Errorf("Event type %s is not supported", aggregateType)
+		}
+	}
+
+	if err = e.start(ctx); err != nil {
+		_log.Error().Err(err).Msg("e.start got an error on EventStreamService.Stream")
+		return nil, err
+	}
+
+	// Subscribe before replaying so nothing published in between is missed.
+	live, unsubscribe := e.hub.subscribe(aggregateTypes, e.config.BufferSize)
+
+	out := make(chan outbox.OutboxEvent)
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+
+		cursor := lastEventID
+		for replaying := lastEventID > 0; replaying; {
+			page, err := e.outboxRepo.GetPublishedOutboxEventsAfter(ctx, cursor, aggregateTypes, e.config.PageSize)
+			if err != nil {
+				_log.Error().Err(err).Msg("e.outboxRepo.GetPublishedOutboxEventsAfter got an error on EventStreamService.Stream")
+				return
+			}
+
+			for _, event := range page {
+				if !e.send(ctx, out, event) {
+					return
+				}
+				cursor = event.PublishedSeq
+			}
+
+			replaying = len(page) == e.config.PageSize
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-e.done:
+				return
+			case <-live.overflow:
+				_log.Warn().Msg("event stream client fell behind on EventStreamService.Stream")
+				return
+			case event := <-live.events:
+				if event.PublishedSeq <= cursor {
+					continue
+				}
+
+				if !e.send(ctx, out, event) {
+					return
+				}
+				cursor = event.PublishedSeq
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// start begins reading the published events with the first stream, from the last one published.
+func (e EventStreamService) start(ctx context.Context) (err error) {
+	e.hub.mu.Lock()
+	defer e.hub.mu.Unlock()
+
+	if e.hub.started {
+		return nil
+	}
+
+	cursor, err := e.outboxRepo.GetLastPublishedOutboxSeq(ctx)
+	if err != nil {
+		return err
+	}
+
+	e.hub.started = true
+	go e.tail(context.WithoutCancel(ctx), cursor)
+
+	return nil
+}
+
+// tail hands every event published after cursor to the live streams until Close is called.
+func (e EventStreamService) tail(ctx context.Context, cursor int64) {
+	_log := _l.Ctx(ctx)
+	ticker := time.NewTicker(e.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			page, err := e.outboxRepo.GetPublishedOutboxEventsAfter(ctx, cursor, nil, e.config.PageSize)
+			if err != nil {
+				_log.Error().Err(err).Msg("e.outboxRepo.GetPublishedOutboxEventsAfter got an error on EventStreamService.tail")
+				break
+			}
+
+			for _, event := range page {
+				e.hub.broadcast(event)
+				cursor = event.PublishedSeq
+			}
+
+			if len(page) < e.config.PageSize {
+				break
+			}
+		}
+	}
+}
+
+func (h *eventHub) subscribe(aggregateTypes []string, bufferSize int) (stream *liveStream, unsubscribe func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	stream = &liveStream{
+		aggregateTypes: aggregateTypes,
+		events:         make(chan outbox.OutboxEvent, bufferSize),
+		overflow:       make(chan struct{}),
+	}
+
+	id := h.nextID
+	h.nextID++
+	h.streams[id] = stream
+
+	return stream, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		delete(h.streams, id)
+	}
+}
+
+func (h *eventHub) broadcast(event outbox.OutboxEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, stream := range h.streams {
+		if len(stream.aggregateTypes) > 0 && !slices.Contains(stream.aggregateTypes, event.AggregateType) {
+			continue
+		}
+
+		select {
+		case stream.events <- event:
+		default:
+			stream.overflowOnce.Do(func() { close(stream.overflow) })
+		}
+	}
+}
+
+func (e EventStreamService) send(ctx context.Context, out chan<- outbox.OutboxEvent, event outbox.OutboxEvent) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-e.done:
+		return false
+	case out <- event:
+		return true
+	}
+}
+
+// Close implements EventStreamServiceI.
+// It ends every open stream so their HTTP handlers return during shutdown.
+func (e EventStreamService) Close() {
+	e.closeOnce.Do(func() { close(e.done) })
+}
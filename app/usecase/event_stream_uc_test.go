@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/outbox"
+)
+
+// publishTestEvents records n events and then marks the outbox events with ids published, in
+// that order.
+func publishTestEvents(t *testing.T, outboxRepo _r.OutboxRepositoryI, n int, ids ...int64) {
+	t.Helper()
+
+	recordTestEvents(t, outboxRepo, n)
+	for _, id := range ids {
+		if err := outboxRepo.MarkOutboxEventPublished(context.Background(), id); err != nil {
+			t.Fatalf("MarkOutboxEventPublished: %v", err)
+		}
+	}
+}
+
+// receive reads n events from events and returns their outbox ids.
+func receive(t *testing.T, events <-chan outbox.OutboxEvent, n int) (ids []int64) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for len(ids) < n {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				t.Fatalf("stream closed after %v", ids)
+			}
+			ids = append(ids, event.ID)
+		case <-timeout:
+			t.Fatalf("got %v, want %d events", ids, n)
+		}
+	}
+
+	return ids
+}
+
+func TestEventStreamReplayPagesUntilCaughtUp(t *testing.T) {
+	outboxRepo := _r.NewMemoryOutboxRepository(_r.NewMemoryStore())
+	publishTestEvents(t, outboxRepo, 7, 1, 2, 3, 4, 5, 6, 7)
+
+	service := NewEventStreamService(outboxRepo, EventStreamConfig{PageSize: 2, PollInterval: time.Millisecond})
+	defer service.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := service.Stream(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+
+	if ids := receive(t, events, 6); !reflect.DeepEqual(ids, []int64{2, 3, 4, 5, 6, 7}) {
+		t.Errorf("replayed %v, want 2 to 7", ids)
+	}
+}
+
+func TestEventStreamResumesInPublishOrder(t *testing.T) {
+	outboxRepo := _r.NewMemoryOutboxRepository(_r.NewMemoryStore())
+
+	// Event 2 is published before event 1, so a client that saw event 2 has not seen event 1.
+	publishTestEvents(t, outboxRepo, 3, 2, 1, 3)
+
+	service := NewEventStreamService(outboxRepo, EventStreamConfig{PollInterval: time.Millisecond})
+	defer service.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := service.Stream(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+
+	if ids := receive(t, events, 2); !reflect.DeepEqual(ids, []int64{1, 3}) {
+		t.Errorf("resumed with %v, want 1 and 3", ids)
+	}
+}
+
+func TestEventStreamFollowsLiveEvents(t *testing.T) {
+	outboxRepo := _r.NewMemoryOutboxRepository(_r.NewMemoryStore())
+	publishTestEvents(t, outboxRepo, 2, 1, 2)
+
+	service := NewEventStreamService(outboxRepo, EventStreamConfig{PageSize: 2, PollInterval: time.Millisecond})
+	defer service.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fresh, err := service.Stream(ctx, 0, nil)
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+
+	resumed, err := service.Stream(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+
+	publishTestEvents(t, outboxRepo, 3, 3, 4, 5)
+
+	if ids := receive(t, fresh, 3); !reflect.DeepEqual(ids, []int64{3, 4, 5}) {
+		t.Errorf("fresh stream got %v, want only the events published after it opened", ids)
+	}
+
+	if ids := receive(t, resumed, 4); !reflect.DeepEqual(ids, []int64{2, 3, 4, 5}) {
+		t.Errorf("resumed stream got %v, want 2 to 5 without repeats", ids)
+	}
+}
+
+func TestEventStreamRejectsUnknownTypes(t *testing.T) {
+	outboxRepo := _r.NewMemoryOutboxRepository(_r.NewMemoryStore())
+	service := NewEventStreamService(outboxRepo, EventStreamConfig{})
+
+	if _, err := service.Stream(context.Background(), 0, []string{"loan"}); err == nil {
+		t.Error("Stream accepted an unsupported type")
+	}
+}
@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/health"
+	_l "github.com/rs/zerolog/log"
+)
+
+type HealthServiceI interface {
+	Liveness(ctx context.Context) (resp health.Report)
+	Readiness(ctx context.Context) (resp health.Report)
+	StartDraining()
+}
+
+type HealthService struct {
+	healthRepo _r.HealthRepositoryI
+	timeout    time.Duration
+	draining   *atomic.Bool
+}
+
+func NewHealthService(healthRepo _r.HealthRepositoryI, timeout time.Duration) HealthServiceI {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+
+	return HealthService{
+		healthRepo: healthRepo,
+		timeout:    timeout,
+		draining:   &atomic.Bool{},
+	}
+}
+
+// Liveness implements HealthServiceI.
+// It only tells that the process is serving requests; dependencies belong to Readiness.
+func (h HealthService) Liveness(ctx context.Context) (resp health.Report) {
+	return health.Report{Status: health.StatusUp, Checks: []health.Check{}}
+}
+
+// Readiness implements HealthServiceI.
+// Every dependency is checked even when an earlier one failed so the report is complete.
+func (h HealthService) Readiness(ctx context.Context) (resp health.Report) {
+	_log := _l.Ctx(ctx)
+
+	resp = health.Report{
+		Status: health.StatusUp,
+		Checks: []health.Check{h.checkShutdown(), h.checkDatabase(ctx), h.checkMigrations(ctx)},
+	}
+
+	for _, check := range resp.Checks {
+		if check.Status != health.StatusUp {
+			resp.Status = health.StatusDown
+			_log.Warn().Str("check", check.Name).Str("error", check.Error).Msg("readiness check is down on HealthService.Readiness")
+		}
+	}
+
+	return resp
+}
+
+// StartDraining implements HealthServiceI.
+// From then on Readiness reports down so load balancers stop routing new traffic here.
+func (h HealthService) StartDraining() {
+	h.draining.Store(true)
+}
+
+func (h HealthService) checkShutdown() health.Check {
+	if h.draining.Load() {
+		return health.Check{Name: "shutdown", Status: health.StatusDown, Error: "graceful shutdown in progress"}
+	}
+
+	return health.Check{Name: "shutdown", Status: health.StatusUp}
+}
+
+// checkDatabase is named after the database in use: postgres, sqlite or memory.
+func (h HealthService) checkDatabase(ctx context.Context) health.Check {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	check := health.Check{Name: h.healthRepo.Dialect(), Status: health.StatusUp}
+
+	if err := h.healthRepo.Ping(ctx); err != nil {
+		check.Status = health.StatusDown
+		check.Error = err.Error()
+	}
+	check.Latency = time.Since(start).String()
+
+	return check
+}
+
+func (h HealthService) checkMigrations(ctx context.Context) health.Check {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	check := health.Check{Name: "migrations", Status: health.StatusUp}
+
+	pending, err := h.healthRepo.GetPendingMigrations(ctx)
+	check.Latency = time.Since(start).String()
+
+	if err != nil {
+		check.Status = health.StatusDown
+		check.Error = err.Error()
+		return check
+	}
+
+	if len(pending) > 0 {
+		check.Status = health.StatusDown
+		check.Error = "migrations are not current"
+		check.Details = map[string]interface{}{"pending": pending}
+	}
+
+	return check
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	_track "github.com/book-library/app/helper"
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/idempotency"
+	"github.com/google/uuid"
+	_l "github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when a key comes back with a different request.
+	ErrIdempotencyKeyReused = errors.New("Idempotency-Key was already used with a different request")
+	// ErrIdempotencyKeyInProgress is returned while the first request with the key is running.
+	ErrIdempotencyKeyInProgress = errors.New("A request with this Idempotency-Key is still in progress")
+	// ErrIdempotencyKeyTooLong is returned for a key longer than idempotency.MaxKeyLength.
+	ErrIdempotencyKeyTooLong = errors.New("Idempotency-Key is too long")
+)
+
+type IdempotencyServiceI interface {
+	Begin(ctx context.Context, scope, key, requestHash string) (replay *idempotency.StoredResponse, owner string, err error)
+	Hold(ctx context.Context, scope, key, owner string)
+	Complete(ctx context.Context, scope, key, owner string, resp idempotency.StoredResponse) (err error)
+	Release(ctx context.Context, scope, key, owner string)
+	Run(ctx context.Context)
+}
+
+type IdempotencyConfig struct {
+	TTL           time.Duration
+	Lease         time.Duration
+	PurgeInterval time.Duration
+}
+
+type IdempotencyService struct {
+	idempotencyRepo _r.IdempotencyRepositoryI
+	config          IdempotencyConfig
+}
+
+func NewIdempotencyService(idempotencyRepo _r.IdempotencyRepositoryI, config IdempotencyConfig) IdempotencyServiceI {
+	if config.TTL <= 0 {
+		config.TTL = 24 * time.Hour
+	}
+
+	if config.Lease <= 0 {
+		config.Lease = time.Minute
+	}
+
+	if config.PurgeInterval <= 0 {
+		config.PurgeInterval = time.Hour
+	}
+
+	return IdempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		config:          config,
+	}
+}
+
+// IdempotencyScope names the keys of one client on one route, so keys of other clients or of
+// other routes never collide with them. It is hashed since client may hold an API key.
+func IdempotencyScope(client, method, path string) string {
+	hash := sha256.Sum256([]byte(client + "\n" + method + " " + path))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// HashIdempotentRequest fingerprints a request so a reused key can be told apart from a retry.
+func HashIdempotentRequest(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Begin implements IdempotencyServiceI.
+// It takes the key in scope for a new request and returns the owner the request completes or
+// releases it with, or returns the stored response when the key was already completed by the
+// same request. A request that is still running holds the key for Lease, so a client is not
+// locked out of its key when the request dies unfinished.
+func (i IdempotencyService) Begin(ctx context.Context, scope, key, requestHash string) (replay *idempotency.StoredResponse, owner string, err error) {
+	ctx, end := _track.Track(ctx, "BeginIdempotency")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if key == "" {
+		_log.Error().Msg("Idempotency-Key can not be empty on IdempotencyService.Begin")
+		return nil, "", errors.New("Idempotency-Key can not be empty")
+	}
+
+	if len(key) > idempotency.MaxKeyLength {
+		_log.Error().Msg("Idempotency-Key is too long on IdempotencyService.Begin")
+		return nil, "", ErrIdempotencyKeyTooLong
+	}
+
+	now := time.Now()
+	owner = uuid.New().String()
+	created, err := i.idempotencyRepo.CreateIdempotencyKey(ctx, idempotency.IdempotencyInput{
+		Scope:          scope,
+		IdempotencyKey: key,
+		Owner:          owner,
+		RequestHash:    requestHash,
+		LockedUntil:    now.Add(i.config.Lease),
+		ExpiresAt:      now.Add(i.config.TTL),
+	})
+	if err != nil {
+		_log.Error().Err(err).Msg("i.idempotencyRepo.CreateIdempotencyKey got an error on IdempotencyService.Begin")
+		return nil, "", err
+	}
+
+	if created {
+		return nil, owner, nil
+	}
+
+	record, err := i.idempotencyRepo.GetIdempotencyKey(ctx, scope, key)
+	if err != nil {
+		_log.Error().Err(err).Msg("i.idempotencyRepo.GetIdempotencyKey got an error on IdempotencyService.Begin")
+		return nil, "", err
+	}
+
+	// The holder was released between the insert and this read, so the retry can go ahead.
+	if record.IdempotencyKey == "" {
+		return i.Begin(ctx, scope, key, requestHash)
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, "", ErrIdempotencyKeyReused
+	}
+
+	if !record.CompletedFlag {
+		return nil, "", ErrIdempotencyKeyInProgress
+	}
+
+	return &idempotency.StoredResponse{
+		StatusCode:   record.StatusCode,
+		ContentType:  record.ContentType,
+		ResponseBody: record.ResponseBody,
+	}, "", nil
+}
+
+// Hold implements IdempotencyServiceI.
+// It extends the lease of the key owner took every third of Lease until ctx is cancelled, so a
+// request that runs longer than Lease keeps its key.
+func (i IdempotencyService) Hold(ctx context.Context, scope, key, owner string) {
+	ticker := time.NewTicker(i.config.Lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := i.idempotencyRepo.ExtendIdempotencyKey(ctx, scope, key, owner, time.Now().Add(i.config.Lease))
+			if err != nil && ctx.Err() == nil {
+				_l.Ctx(ctx).Error().Err(err).Msg("i.idempotencyRepo.ExtendIdempotencyKey got an error on IdempotencyService.Hold")
+			}
+		}
+	}
+}
+
+// Complete implements IdempotencyServiceI.
+func (i IdempotencyService) Complete(ctx context.Context, scope, key, owner string, resp idempotency.StoredResponse) (err error) {
+	ctx, end := _track.Track(ctx, "CompleteIdempotency")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	err = i.idempotencyRepo.CompleteIdempotencyKey(ctx, scope, key, owner, resp)
+	if err != nil {
+		_log.Error().Err(err).Msg("i.idempotencyRepo.CompleteIdempotencyKey got an error on IdempotencyService.Complete")
+		return err
+	}
+
+	return nil
+}
+
+// Release implements IdempotencyServiceI.
+// It frees a key whose request failed without a response worth replaying, so it can be retried.
+func (i IdempotencyService) Release(ctx context.Context, scope, key, owner string) {
+	ctx, end := _track.Track(ctx, "ReleaseIdempotency")
+	defer end()
+
+	if err := i.idempotencyRepo.DeleteIdempotencyKey(ctx, scope, key, owner); err != nil {
+		_l.Ctx(ctx).Error().Err(err).Msg("i.idempotencyRepo.DeleteIdempotencyKey got an error on IdempotencyService.Release")
+	}
+}
+
+// Run implements IdempotencyServiceI.
+// It deletes expired keys every PurgeInterval until ctx is cancelled.
+func (i IdempotencyService) Run(ctx context.Context) {
+	_log := _l.Ctx(ctx)
+	ticker := time.NewTicker(i.config.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := i.idempotencyRepo.DeleteExpiredIdempotencyKeys(ctx)
+			if err != nil {
+				_log.Error().Err(err).Msg("i.idempotencyRepo.DeleteExpiredIdempotencyKeys got an error on IdempotencyService.Run")
+				continue
+			}
+
+			if deleted > 0 {
+				_log.Info().Int64("deleted", deleted).Msg("expired idempotency keys purged on IdempotencyService.Run")
+			}
+		}
+	}
+}
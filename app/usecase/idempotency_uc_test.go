@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/idempotency"
+)
+
+func TestIdempotencyKeysAreScoped(t *testing.T) {
+	ctx := context.Background()
+	service := NewIdempotencyService(_r.NewMemoryIdempotencyRepository(_r.NewMemoryStore()), IdempotencyConfig{})
+
+	alice := IdempotencyScope("ip:192.0.2.1", "POST", "/api/v1/book/create")
+	hash := HashIdempotentRequest("POST", "/api/v1/book/create", []byte(`{}`))
+	_, owner, err := service.Begin(ctx, alice, "key", hash)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	service.Complete(ctx, alice, "key", owner, idempotency.StoredResponse{StatusCode: 201, ResponseBody: "alice"})
+
+	tests := []struct {
+		name  string
+		scope string
+	}{
+		{name: "other client", scope: IdempotencyScope("ip:192.0.2.2", "POST", "/api/v1/book/create")},
+		{name: "other route", scope: IdempotencyScope("ip:192.0.2.1", "POST", "/api/v1/author/create")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replay, _, err := service.Begin(ctx, tt.scope, "key", hash)
+			if replay != nil || err != nil {
+				t.Errorf("Begin = %+v, %v, want the key free", replay, err)
+			}
+		})
+	}
+
+	replay, _, err := service.Begin(ctx, alice, "key", hash)
+	if err != nil || replay == nil || replay.ResponseBody != "alice" {
+		t.Errorf("Begin on the same scope = %+v, %v, want the stored response", replay, err)
+	}
+}
+
+func TestIdempotencyLeaseRunsOut(t *testing.T) {
+	ctx := context.Background()
+	service := NewIdempotencyService(_r.NewMemoryIdempotencyRepository(_r.NewMemoryStore()), IdempotencyConfig{Lease: 10 * time.Millisecond})
+
+	scope := IdempotencyScope("ip:192.0.2.1", "POST", "/api/v1/book/create")
+	_, first, err := service.Begin(ctx, scope, "key", "hash")
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+
+	if _, _, err = service.Begin(ctx, scope, "key", "hash"); !errors.Is(err, ErrIdempotencyKeyInProgress) {
+		t.Fatalf("Begin under the lease = %v, want %v", err, ErrIdempotencyKeyInProgress)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	replay, retry, err := service.Begin(ctx, scope, "key", "hash")
+	if replay != nil || err != nil {
+		t.Fatalf("Begin after the lease = %+v, %v, want the key taken over", replay, err)
+	}
+
+	// The first request finishing late must not touch the key its retry holds now.
+	service.Complete(ctx, scope, "key", first, idempotency.StoredResponse{StatusCode: 201, ResponseBody: "first"})
+	service.Release(ctx, scope, "key", first)
+
+	if _, _, err = service.Begin(ctx, scope, "key", "hash"); !errors.Is(err, ErrIdempotencyKeyInProgress) {
+		t.Fatalf("Begin after the first request ended = %v, want the retry still holding the key", err)
+	}
+
+	service.Complete(ctx, scope, "key", retry, idempotency.StoredResponse{StatusCode: 201, ResponseBody: "retry"})
+
+	replay, _, err = service.Begin(ctx, scope, "key", "hash")
+	if err != nil || replay == nil || replay.ResponseBody != "retry" {
+		t.Errorf("Begin after the retry completed = %+v, %v, want the response of the retry", replay, err)
+	}
+}
+
+func TestIdempotencyHoldExtendsTheLease(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service := NewIdempotencyService(_r.NewMemoryIdempotencyRepository(_r.NewMemoryStore()), IdempotencyConfig{Lease: 30 * time.Millisecond})
+
+	scope := IdempotencyScope("ip:192.0.2.1", "POST", "/api/v1/book/create")
+	_, owner, err := service.Begin(ctx, scope, "key", "hash")
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+
+	go service.Hold(ctx, scope, "key", owner)
+	time.Sleep(100 * time.Millisecond)
+
+	if _, _, err = service.Begin(ctx, scope, "key", "hash"); !errors.Is(err, ErrIdempotencyKeyInProgress) {
+		t.Errorf("Begin past the first lease = %v, want %v while the request holds the key", err, ErrIdempotencyKeyInProgress)
+	}
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	_track "github.com/book-library/app/helper"
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/member"
+	_l "github.com/rs/zerolog/log"
+)
+
+type MemberServiceI interface {
+	CreateMember(ctx context.Context, input member.MemberInput) (err error)
+	UpdateMember(ctx context.Context, id int64, input member.MemberInput) (err error)
+	GetMemberByID(ctx context.Context, id int64) (resp member.MemberResponse, err error)
+	GetAllMembers(ctx context.Context, name string) (resp []member.MemberResponse, err error)
+	DeleteMemberByID(ctx context.Context, id int64) (err error)
+}
+
+type MemberService struct {
+	memberRepo _r.MemberRepositoryI
+	trRepo     _r.TransactionRepositoryI
+	reviewRepo _r.ReviewRepositoryI
+}
+
+func NewMemberService(memberRepo _r.MemberRepositoryI, trRepo _r.TransactionRepositoryI, reviewRepo _r.ReviewRepositoryI) MemberServiceI {
+	return MemberService{
+		memberRepo: memberRepo,
+		trRepo:     trRepo,
+		reviewRepo: reviewRepo,
+	}
+}
+
+// CreateMember implements MemberServiceI.
+func (m MemberService) CreateMember(ctx context.Context, input member.MemberInput) (err error) {
+	ctx, end := _track.Track(ctx, "CreateMember")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if err = m.validationInput(input); err != nil {
+		_log.Error().Err(err).Msg("m.validationInput got an error on MemberService.CreateMember")
+		return err
+	}
+
+	byEmail, err := m.memberRepo.GetMemberById(ctx, 0, strings.ToLower(input.Email))
+	if err != nil {
+		_log.Error().Err(err).Msg("m.memberRepo.GetMemberById got an error on MemberService.CreateMember")
+		return err
+	}
+
+	if byEmail.ID != 0 {
+		_log.Error().Msgf("Member with email %s is already exist", byEmail.Email)
+		return fmt.Errorf("Member with email %s is already exist", byEmail.Email)
+	}
+
+	trx := m.trRepo.BeginTransaction(ctx)
+
+	err = m.memberRepo.CreateMember(ctx, trx, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("m.memberRepo.CreateMember got an error on MemberService.CreateMember")
+		m.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	m.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// DeleteMemberByID implements MemberServiceI.
+func (m MemberService) DeleteMemberByID(ctx context.Context, id int64) (err error) {
+	ctx, end := _track.Track(ctx, "DeleteMemberByID")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	reviewByMember, err := m.reviewRepo.GetReviewById(ctx, 0, 0, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("m.reviewRepo.GetReviewById got an error on MemberService.DeleteMemberByID")
+		return err
+	}
+
+	if reviewByMember.ID != 0 {
+		_log.Error().Msg("There is review written by this member and delete the review first before delete member")
+		return errors.New("There is review written by this member and delete the review first before delete member")
+	}
+
+	trx := m.trRepo.BeginTransaction(ctx)
+
+	err = m.memberRepo.DeleteMember(ctx, trx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("m.memberRepo.DeleteMember got an error on MemberService.DeleteMemberByID")
+		m.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	m.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// GetAllMembers implements MemberServiceI.
+func (m MemberService) GetAllMembers(ctx context.Context, name string) (resp []member.MemberResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetAllMembers")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	members, err := m.memberRepo.GetAllMembers(ctx, name)
+	if err != nil {
+		_log.Error().Err(err).Msg("m.memberRepo.GetAllMembers got an error on MemberService.GetAllMembers")
+		return resp, err
+	}
+
+	return members, err
+}
+
+// GetMemberByID implements MemberServiceI.
+func (m MemberService) GetMemberByID(ctx context.Context, id int64) (resp member.MemberResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetMemberByID")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("MemberID cannot be nol on MemberService.GetMemberByID")
+		return resp, errors.New("MemberID cannot be nol")
+	}
+
+	memberById, err := m.memberRepo.GetMemberById(ctx, id, "")
+	if err != nil {
+		_log.Error().Err(err).Msg("m.memberRepo.GetMemberById got an error on MemberService.GetMemberByID")
+		return resp, err
+	}
+
+	if memberById.ID == 0 {
+		_log.Error().Err(err).Msg("Member not found on MemberService.GetMemberByID")
+		return resp, errors.New("Member not found")
+	}
+
+	return memberById, err
+}
+
+// UpdateMember implements MemberServiceI.
+func (m MemberService) UpdateMember(ctx context.Context, id int64, input member.MemberInput) (err error) {
+	ctx, end := _track.Track(ctx, "UpdateMember")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("MemberID cannot be nol on MemberService.UpdateMember")
+		return errors.New("MemberID cannot be nol")
+	}
+
+	memberById, err := m.memberRepo.GetMemberById(ctx, id, "")
+	if err != nil {
+		_log.Error().Err(err).Msg("m.memberRepo.GetMemberById got an error on MemberService.UpdateMember")
+		return err
+	}
+
+	if memberById.ID == 0 {
+		_log.Error().Msg("Member not found on MemberService.UpdateMember")
+		return errors.New("Member not found")
+	}
+
+	if input.Name == "" {
+		input.Name = memberById.Name
+	}
+
+	if input.Email == "" {
+		input.Email = memberById.Email
+	}
+
+	trx := m.trRepo.BeginTransaction(ctx)
+
+	err = m.memberRepo.UpdateMember(ctx, trx, id, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("m.memberRepo.UpdateMember got an error on MemberService.UpdateMember")
+		m.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	m.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+func (m MemberService) validationInput(input member.MemberInput) (err error) {
+	if input.Name == "" {
+		return errors.New("Name can not be empty")
+	}
+
+	if input.Email == "" {
+		return errors.New("Email can not be empty")
+	}
+
+	return nil
+}
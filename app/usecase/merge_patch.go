@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// applyMergePatch applies an RFC 7396 merge patch to the fields of current and decodes the
+// result into merged. A null in the patch removes the field, which decodes as its zero value
+// (nil for optional references). Only fields may be patched; it returns the ones whose value
+// changed, in order.
+func applyMergePatch(current interface{}, patch []byte, fields []string, merged interface{}) (changed []string, err error) {
+	var patchDoc map[string]interface{}
+	if err = json.Unmarshal(patch, &patchDoc); err != nil || patchDoc == nil {
+		return nil, errors.New("Patch must be a JSON object")
+	}
+
+	allowed := map[string]bool{}
+	for _, field := range fields {
+		allowed[field] = true
+	}
+
+	for field := range patchDoc {
+		if !allowed[field] {
+			return nil, fmt.Errorf("Field %s can not be patched", field)
+		}
+	}
+
+	encoded, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var currentDoc map[string]interface{}
+	if err = json.Unmarshal(encoded, &currentDoc); err != nil {
+		return nil, err
+	}
+
+	target := map[string]interface{}{}
+	for _, field := range fields {
+		if value, ok := currentDoc[field]; ok && value != nil {
+			target[field] = value
+		}
+	}
+
+	mergedDoc := map[string]interface{}{}
+	for field, value := range target {
+		mergedDoc[field] = value
+	}
+	mergedDoc = mergePatchValue(mergedDoc, patchDoc).(map[string]interface{})
+	for _, field := range fields {
+		if !reflect.DeepEqual(target[field], mergedDoc[field]) {
+			changed = append(changed, field)
+		}
+	}
+	sort.Strings(changed)
+
+	encoded, err = json.Marshal(mergedDoc)
+	if err != nil {
+		return nil, err
+	}
+
+	return changed, json.Unmarshal(encoded, merged)
+}
+
+// mergePatchValue is the MergePatch function of RFC 7396 section 2.
+func mergePatchValue(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+
+		targetObject[name] = mergePatchValue(targetObject[name], value)
+	}
+
+	return targetObject
+}
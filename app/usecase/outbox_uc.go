@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/outbox"
+	"github.com/book-library/entity/webhook"
+	"github.com/google/uuid"
+	_l "github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// OutboxSinkI receives the events the relay reads from the outbox. Handle may be called more
+// than once for the same event, so sinks must tolerate duplicates.
+type OutboxSinkI interface {
+	Name() string
+	Handle(ctx context.Context, event outbox.OutboxEvent) (err error)
+}
+
+type OutboxRelayI interface {
+	Run(ctx context.Context)
+	RelayPending(ctx context.Context) (published int, err error)
+}
+
+// OutboxConfig controls the relay. Of several instances only the one holding the relay lease
+// relays; it renews the lease for LeaseTTL on every batch, and another instance takes over once
+// LeaseTTL passes without one. A batch must be relayed within LeaseTTL.
+type OutboxConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	LeaseTTL     time.Duration
+}
+
+type OutboxRelay struct {
+	outboxRepo _r.OutboxRepositoryI
+	sinks      []OutboxSinkI
+	config     OutboxConfig
+	owner      string
+}
+
+func NewOutboxRelay(outboxRepo _r.OutboxRepositoryI, config OutboxConfig, sinks ...OutboxSinkI) OutboxRelayI {
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+
+	if config.LeaseTTL <= 0 {
+		config.LeaseTTL = 30 * time.Second
+	}
+
+	return OutboxRelay{
+		outboxRepo: outboxRepo,
+		sinks:      sinks,
+		config:     config,
+		owner:      uuid.New().String(),
+	}
+}
+
+// recordOutboxEvent stores a domain event in the caller's transaction. It is committed or
+// rolled back together with the change it describes.
+func recordOutboxEvent(ctx context.Context, outboxRepo _r.OutboxRepositoryI, trx *gorm.DB, aggregateType string, aggregateID int64, eventType string, data interface{}) (err error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return outboxRepo.CreateOutboxEvent(ctx, trx, outbox.OutboxInput{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(payload),
+	})
+}
+
+// Run implements OutboxRelayI.
+// It polls until ctx is cancelled; an in-flight batch is finished and the relay lease handed back
+// before it returns.
+func (o OutboxRelay) Run(ctx context.Context) {
+	_log := _l.Ctx(ctx)
+	ticker := time.NewTicker(o.config.PollInterval)
+	defer ticker.Stop()
+
+	defer func() {
+		if err := o.outboxRepo.ReleaseOutboxLease(context.WithoutCancel(ctx), o.owner); err != nil {
+			_log.Error().Err(err).Msg("o.outboxRepo.ReleaseOutboxLease got an error on OutboxRelay.Run")
+		}
+	}()
+
+	for {
+		published, err := o.RelayPending(context.WithoutCancel(ctx))
+		if err != nil {
+			_log.Error().Err(err).Msg("o.RelayPending got an error on OutboxRelay.Run")
+		}
+
+		// A full batch means more events are probably waiting, so skip the wait.
+		if published == o.config.BatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending implements OutboxRelayI.
+// Events are handed to every sink oldest first and marked published only when all sinks
+// accepted them. Once an event of an aggregate fails, the later events of that aggregate are
+// held back until the next run so subscribers never see them out of order. Nothing is relayed
+// while another instance holds the relay lease.
+func (o OutboxRelay) RelayPending(ctx context.Context) (published int, err error) {
+	_log := _l.Ctx(ctx)
+
+	acquired, err := o.outboxRepo.AcquireOutboxLease(ctx, o.owner, o.config.LeaseTTL)
+	if err != nil {
+		_log.Error().Err(err).Msg("o.outboxRepo.AcquireOutboxLease got an error on OutboxRelay.RelayPending")
+		return published, err
+	}
+
+	if !acquired {
+		return published, nil
+	}
+
+	events, err := o.outboxRepo.GetPendingOutboxEvents(ctx, o.config.BatchSize)
+	if err != nil {
+		_log.Error().Err(err).Msg("o.outboxRepo.GetPendingOutboxEvents got an error on OutboxRelay.RelayPending")
+		return published, err
+	}
+
+	blocked := map[string]bool{}
+	for _, event := range events {
+		aggregate := event.AggregateType + ":" + strconv.FormatInt(event.AggregateID, 10)
+		if blocked[aggregate] {
+			continue
+		}
+
+		if err = o.dispatch(ctx, event); err != nil {
+			_log.Error().Err(err).Msgf("o.dispatch got an error on OutboxRelay.RelayPending for outbox event %d", event.ID)
+			blocked[aggregate] = true
+
+			if err = o.outboxRepo.MarkOutboxEventFailed(ctx, event.ID, err.Error()); err != nil {
+				_log.Error().Err(err).Msg("o.outboxRepo.MarkOutboxEventFailed got an error on OutboxRelay.RelayPending")
+				return published, err
+			}
+			continue
+		}
+
+		if err = o.outboxRepo.MarkOutboxEventPublished(ctx, event.ID); err != nil {
+			_log.Error().Err(err).Msg("o.outboxRepo.MarkOutboxEventPublished got an error on OutboxRelay.RelayPending")
+			return published, err
+		}
+
+		published++
+	}
+
+	return published, nil
+}
+
+func (o OutboxRelay) dispatch(ctx context.Context, event outbox.OutboxEvent) (err error) {
+	for _, sink := range o.sinks {
+		if err = sink.Handle(ctx, event); err != nil {
+			return fmt.Errorf("sink %s: %w", sink.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// LogSink writes every relayed event to the application log.
+type LogSink struct{}
+
+func NewLogSink() OutboxSinkI {
+	return LogSink{}
+}
+
+// Name implements OutboxSinkI.
+func (l LogSink) Name() string {
+	return "log"
+}
+
+// Handle implements OutboxSinkI.
+func (l LogSink) Handle(ctx context.Context, event outbox.OutboxEvent) (err error) {
+	_l.Ctx(ctx).Info().
+		Int64("outbox_id", event.ID).
+		Str("aggregate_type", event.AggregateType).
+		Int64("aggregate_id", event.AggregateID).
+		Str("event_type", event.EventType).
+		RawJSON("payload", []byte(event.Payload)).
+		Msg("domain event published")
+
+	return nil
+}
+
+// WebhookSink forwards relayed events to the webhook subscribers. The outbox id is used as
+// the event id so receivers can drop redeliveries. Handle returns once a delivery per subscriber
+// is stored; from there the webhook delivery worker sends and retries them, so a crash after the
+// event is marked published loses nothing.
+type WebhookSink struct {
+	publisher EventPublisherI
+}
+
+func NewWebhookSink(publisher EventPublisherI) OutboxSinkI {
+	return WebhookSink{publisher: publisher}
+}
+
+// Name implements OutboxSinkI.
+func (ws WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Handle implements OutboxSinkI.
+func (ws WebhookSink) Handle(ctx context.Context, event outbox.OutboxEvent) (err error) {
+	return ws.publisher.Publish(ctx, webhook.Event{
+		ID:         strconv.FormatInt(event.ID, 10),
+		Type:       event.EventType,
+		OccurredAt: event.CreatedAt.UTC(),
+		Data:       json.RawMessage(event.Payload),
+	})
+}
+
+// OutboxSubscriber is an in-process consumer of relayed events.
+type OutboxSubscriber func(ctx context.Context, event outbox.OutboxEvent) (err error)
+
+// SubscriberSink fans relayed events out to in-process subscribers.
+type SubscriberSink struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]OutboxSubscriber
+}
+
+func NewSubscriberSink() *SubscriberSink {
+	return &SubscriberSink{subscribers: map[int]OutboxSubscriber{}}
+}
+
+// Subscribe registers fn and returns the function that removes it again.
+func (s *SubscriberSink) Subscribe(fn OutboxSubscriber) (unsubscribe func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	s.subscribers[id] = fn
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.subscribers, id)
+	}
+}
+
+// Name implements OutboxSinkI.
+func (s *SubscriberSink) Name() string {
+	return "subscriber"
+}
+
+// Handle implements OutboxSinkI.
+func (s *SubscriberSink) Handle(ctx context.Context, event outbox.OutboxEvent) (err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, fn := range s.subscribers {
+		if err = fn(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
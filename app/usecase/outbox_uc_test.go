@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/outbox"
+	"github.com/book-library/entity/webhook"
+)
+
+// countingSink records the ids of the events it is handed.
+type countingSink struct {
+	ids []int64
+}
+
+func (c *countingSink) Name() string {
+	return "counting"
+}
+
+func (c *countingSink) Handle(ctx context.Context, event outbox.OutboxEvent) (err error) {
+	c.ids = append(c.ids, event.ID)
+	return nil
+}
+
+func recordTestEvents(t *testing.T, outboxRepo _r.OutboxRepositoryI, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		err := recordOutboxEvent(context.Background(), outboxRepo, nil, outbox.AggregateBook, int64(i+1), webhook.EventBookCreated, webhook.ResourcePayload{ID: int64(i + 1)})
+		if err != nil {
+			t.Fatalf("recordOutboxEvent: %v", err)
+		}
+	}
+}
+
+func TestOutboxRelayLeaseKeepsOneRelay(t *testing.T) {
+	outboxRepo := _r.NewMemoryOutboxRepository(_r.NewMemoryStore())
+	first, second := &countingSink{}, &countingSink{}
+	relayFirst := NewOutboxRelay(outboxRepo, OutboxConfig{LeaseTTL: time.Hour}, first)
+	relaySecond := NewOutboxRelay(outboxRepo, OutboxConfig{LeaseTTL: time.Hour}, second)
+
+	ctx := context.Background()
+	recordTestEvents(t, outboxRepo, 2)
+
+	if published, err := relayFirst.RelayPending(ctx); published != 2 || err != nil {
+		t.Fatalf("first RelayPending = %d, %v, want 2", published, err)
+	}
+
+	recordTestEvents(t, outboxRepo, 1)
+
+	if published, err := relaySecond.RelayPending(ctx); published != 0 || err != nil {
+		t.Fatalf("second RelayPending under the first lease = %d, %v, want 0", published, err)
+	}
+
+	// Run hands the lease back when it stops.
+	stopped, stop := context.WithCancel(ctx)
+	stop()
+	relayFirst.Run(stopped)
+
+	if published, err := relaySecond.RelayPending(ctx); published != 0 || err != nil {
+		t.Fatalf("second RelayPending after the first stopped = %d, %v, want 0 left", published, err)
+	}
+
+	if len(first.ids) != 3 || len(second.ids) != 0 {
+		t.Errorf("first relayed %v, second %v, want the first to relay all 3", first.ids, second.ids)
+	}
+
+	recordTestEvents(t, outboxRepo, 1)
+
+	if published, err := relaySecond.RelayPending(ctx); published != 1 || err != nil {
+		t.Errorf("second RelayPending with the lease free = %d, %v, want 1", published, err)
+	}
+}
+
+func TestOutboxRelayLeaseRunsOut(t *testing.T) {
+	outboxRepo := _r.NewMemoryOutboxRepository(_r.NewMemoryStore())
+	relayFirst := NewOutboxRelay(outboxRepo, OutboxConfig{LeaseTTL: time.Millisecond}, &countingSink{})
+	second := &countingSink{}
+	relaySecond := NewOutboxRelay(outboxRepo, OutboxConfig{LeaseTTL: time.Millisecond}, second)
+
+	ctx := context.Background()
+	if _, err := relayFirst.RelayPending(ctx); err != nil {
+		t.Fatalf("first RelayPending: %v", err)
+	}
+
+	recordTestEvents(t, outboxRepo, 1)
+	time.Sleep(5 * time.Millisecond)
+
+	if published, err := relaySecond.RelayPending(ctx); published != 1 || err != nil || len(second.ids) != 1 {
+		t.Errorf("second RelayPending after the lease ran out = %d, %v, want 1", published, err)
+	}
+}
+
+func TestWebhookSinkStoresDeliveriesBeforeReturning(t *testing.T) {
+	service, receiver := newTestWebhookService(t, WebhookConfig{MaxAttempts: 1}, http.StatusOK)
+
+	err := NewWebhookSink(service).Handle(context.Background(), outbox.OutboxEvent{
+		ID: 9, AggregateType: outbox.AggregateBook, AggregateID: 1, EventType: webhook.EventBookCreated, Payload: `{"id":1}`,
+	})
+	if err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+
+	deliveries, _ := service.GetWebhookDeliveries(context.Background(), 1)
+	if len(deliveries) != 1 || deliveries[0].Status != webhook.DeliveryStatusPending || deliveries[0].EventID != "9" || deliveries[0].NextAttemptAt == nil {
+		t.Fatalf("deliveries = %+v, want one pending delivery of event 9 due for the worker", deliveries)
+	}
+
+	if receiver.calls() != 0 {
+		t.Errorf("calls before the worker ran = %d, want 0", receiver.calls())
+	}
+
+	if delivery := deliverAll(t, service); delivery.Status != webhook.DeliveryStatusSuccess {
+		t.Errorf("status = %s, want %s", delivery.Status, webhook.DeliveryStatusSuccess)
+	}
+}
+
+func TestDeletingAMissingRowRecordsNoEvent(t *testing.T) {
+	ctx := context.Background()
+	store := _r.NewMemoryStore()
+	trRepo, bookRepo, outboxRepo := _r.NewMemoryTransactionRepository(store), _r.NewMemoryBookLibraryRepository(store), _r.NewMemoryOutboxRepository(store)
+	authorRepo, categoryRepo := _r.NewMemoryAuthorRepository(store), _r.NewMemoryCategoryRepository(store)
+
+	bookService := NewbookLibraryService(bookRepo, trRepo, authorRepo, categoryRepo, _r.NewMemoryPublisherRepository(store),
+		_r.NewMemorySeriesRepository(store), _r.NewMemoryWorkRepository(store), outboxRepo)
+	authorService := NewAuthorService(authorRepo, trRepo, bookRepo, outboxRepo)
+	categoryService := NewCategoryService(categoryRepo, trRepo, bookRepo, outboxRepo)
+
+	tests := []struct {
+		name    string
+		delete  func() error
+		message string
+	}{
+		{name: "book", delete: func() error { return bookService.DeleteBookByID(ctx, 999) }, message: "Book not found"},
+		{name: "author", delete: func() error { return authorService.DeleteAuthorByID(ctx, 999) }, message: "Author not found"},
+		{name: "category", delete: func() error { return categoryService.DeleteCategoryByID(ctx, 999) }, message: "Category not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.delete(); err == nil || err.Error() != tt.message {
+				t.Errorf("delete = %v, want %s", err, tt.message)
+			}
+
+			if pending, _ := outboxRepo.GetPendingOutboxEvents(ctx, 10); len(pending) != 0 {
+				t.Errorf("pending events = %+v, want none", pending)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	_track "github.com/book-library/app/helper"
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/publisher"
+	_l "github.com/rs/zerolog/log"
+)
+
+type PublisherServiceI interface {
+	CreatePublisher(ctx context.Context, input publisher.PublisherInput) (err error)
+	UpdatePublisher(ctx context.Context, id int64, input publisher.PublisherInput) (err error)
+	GetPublisherByID(ctx context.Context, id int64) (resp publisher.PublisherResponse, err error)
+	GetAllPublishers(ctx context.Context, name string) (resp []publisher.PublisherResponse, err error)
+	DeletePublisherByID(ctx context.Context, id int64) (err error)
+}
+
+type PublisherService struct {
+	publisherRepo _r.PublisherRepositoryI
+	trRepo        _r.TransactionRepositoryI
+	bookRepo      _r.BookLibraryRepositoryI
+}
+
+func NewPublisherService(publisherRepo _r.PublisherRepositoryI, trRepo _r.TransactionRepositoryI, bookRepo _r.BookLibraryRepositoryI) PublisherServiceI {
+	return PublisherService{
+		publisherRepo: publisherRepo,
+		trRepo:        trRepo,
+		bookRepo:      bookRepo,
+	}
+}
+
+// CreatePublisher implements PublisherServiceI.
+func (c PublisherService) CreatePublisher(ctx context.Context, input publisher.PublisherInput) (err error) {
+	ctx, end := _track.Track(ctx, "CreatePublisherUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if err = c.validationInput(input); err != nil {
+		_log.Error().Err(err).Msg("c.validationInput got an error on PublisherService.CreatePublisher")
+		return err
+	}
+
+	byID, err := c.publisherRepo.GetPublisherById(ctx, nil, 0, strings.ToLower(input.Name))
+	if err != nil {
+		_log.Error().Err(err).Msg("c.publisherRepo.GetPublisherById got an error on PublisherService.CreatePublisher")
+		return err
+	}
+
+	if byID.ID != 0 {
+		_log.Error().Err(err).Msgf("Publisher %s is already exist", byID.Name)
+		return fmt.Errorf("Publisher %s is already exist", byID.Name)
+	}
+
+	trx := c.trRepo.BeginTransaction(ctx)
+
+	_, err = c.publisherRepo.CreatePublisher(ctx, trx, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.publisherRepo.CreatePublisher got an error on PublisherService.CreatePublisher")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	c.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// DeletePublisherByID implements PublisherServiceI.
+func (c PublisherService) DeletePublisherByID(ctx context.Context, id int64) (err error) {
+	ctx, end := _track.Track(ctx, "DeletePublisherByIDUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	bookByID, err := c.bookRepo.GetBookLibraryById(ctx, nil, 0, 0, 0, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.bookRepo.GetBookLibraryById got an error on PublisherService.DeletePublisherByID")
+		return err
+	}
+
+	if bookByID.ID != 0 {
+		_log.Error().Msgf("There is book(%s) using this publisher and delete book(%s) first before delete publisher", bookByID.Title, bookByID.Title)
+		return fmt.Errorf("There is book(%s) using this publisher and delete the book(%s) first before delete publisher", bookByID.Title, bookByID.Title)
+	}
+
+	trx := c.trRepo.BeginTransaction(ctx)
+
+	err = c.publisherRepo.DeletePublisher(ctx, trx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.publisherRepo.DeletePublisher got an error on PublisherService.DeletePublisherByID")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	c.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// GetAllPublishers implements PublisherServiceI.
+func (c PublisherService) GetAllPublishers(ctx context.Context, name string) (resp []publisher.PublisherResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetAllPublishersUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	publishers, err := c.publisherRepo.GetAllPublishers(ctx, name)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.publisherRepo.GetAllPublishers got an error on PublisherService.GetAllPublishers")
+		return resp, err
+	}
+
+	return publishers, err
+}
+
+// GetPublisherByID implements PublisherServiceI.
+func (c PublisherService) GetPublisherByID(ctx context.Context, id int64) (resp publisher.PublisherResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetPublisherByIDUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("PublisherID cannot be nol on PublisherService.GetPublisherByID")
+		return resp, errors.New("PublisherID cannot be nol")
+	}
+
+	publisherById, err := c.publisherRepo.GetPublisherById(ctx, nil, id, "")
+	if err != nil {
+		_log.Error().Err(err).Msg("c.publisherRepo.GetPublisherById got an error on PublisherService.GetPublisherByID")
+		return resp, err
+	}
+
+	if publisherById.ID == 0 {
+		_log.Error().Err(err).Msg("Publisher not found on PublisherService.GetPublisherByID")
+		return resp, errors.New("Publisher not found")
+	}
+
+	return publisherById, err
+}
+
+// UpdatePublisher implements PublisherServiceI.
+func (c PublisherService) UpdatePublisher(ctx context.Context, id int64, input publisher.PublisherInput) (err error) {
+	ctx, end := _track.Track(ctx, "UpdatePublisherUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("PublisherID cannot be nol on PublisherService.UpdatePublisher")
+		return errors.New("PublisherID cannot be nol")
+	}
+
+	publisherById, err := c.publisherRepo.GetPublisherById(ctx, nil, id, "")
+	if err != nil {
+		_log.Error().Err(err).Msg("c.publisherRepo.GetPublisherById got an error on PublisherService.UpdatePublisher")
+		return err
+	}
+
+	if publisherById.ID == 0 {
+		_log.Error().Msg("Publisher not found on PublisherService.UpdatePublisher")
+		return errors.New("Publisher not found")
+	}
+
+	if input.Name == "" {
+		input.Name = publisherById.Name
+	}
+
+	if input.Address == "" {
+		input.Address = publisherById.Address
+	}
+
+	if input.Website == "" {
+		input.Website = publisherById.Website
+	}
+
+	trx := c.trRepo.BeginTransaction(ctx)
+
+	err = c.publisherRepo.UpdatePublisher(ctx, trx, id, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("c.publisherRepo.UpdatePublisher got an error on PublisherService.UpdatePublisher")
+		c.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	c.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+func (c PublisherService) validationInput(input publisher.PublisherInput) (err error) {
+	if input.Name == "" {
+		return errors.New("Name can not be empty")
+	}
+
+	return nil
+}
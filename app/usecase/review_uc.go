@@ -0,0 +1,314 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	_track "github.com/book-library/app/helper"
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/review"
+	_l "github.com/rs/zerolog/log"
+)
+
+type ReviewServiceI interface {
+	CreateReview(ctx context.Context, input review.ReviewInput) (err error)
+	UpdateReview(ctx context.Context, id int64, input review.ReviewInput) (err error)
+	ModerateReview(ctx context.Context, id int64, input review.ReviewModerationInput) (err error)
+	GetReviewByID(ctx context.Context, id int64) (resp review.ReviewResponse, err error)
+	GetAllReviews(ctx context.Context, search review.ReviewSearch) (resp []review.ReviewResponse, err error)
+	DeleteReviewByID(ctx context.Context, id int64) (err error)
+}
+
+type ReviewService struct {
+	reviewRepo _r.ReviewRepositoryI
+	trRepo     _r.TransactionRepositoryI
+	bookRepo   _r.BookLibraryRepositoryI
+	memberRepo _r.MemberRepositoryI
+}
+
+func NewReviewService(reviewRepo _r.ReviewRepositoryI, trRepo _r.TransactionRepositoryI, bookRepo _r.BookLibraryRepositoryI, memberRepo _r.MemberRepositoryI) ReviewServiceI {
+	return ReviewService{
+		reviewRepo: reviewRepo,
+		trRepo:     trRepo,
+		bookRepo:   bookRepo,
+		memberRepo: memberRepo,
+	}
+}
+
+// CreateReview implements ReviewServiceI.
+// New reviews start as pending and only count towards the book rating once approved.
+func (r ReviewService) CreateReview(ctx context.Context, input review.ReviewInput) (err error) {
+	ctx, end := _track.Track(ctx, "CreateReviewUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if err = r.validationInput(input); err != nil {
+		_log.Error().Err(err).Msg("r.validationInput got an error on ReviewService.CreateReview")
+		return err
+	}
+
+	bookById, err := r.bookRepo.GetBookLibraryById(ctx, nil, input.BookID, 0, 0, 0)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.bookRepo.GetBookLibraryById got an error on ReviewService.CreateReview")
+		return err
+	}
+
+	if bookById.ID == 0 {
+		_log.Error().Msg("Book not found on ReviewService.CreateReview")
+		return errors.New("Book not found")
+	}
+
+	memberById, err := r.memberRepo.GetMemberById(ctx, input.MemberID, "")
+	if err != nil {
+		_log.Error().Err(err).Msg("r.memberRepo.GetMemberById got an error on ReviewService.CreateReview")
+		return err
+	}
+
+	if memberById.ID == 0 {
+		_log.Error().Msg("Member not found on ReviewService.CreateReview")
+		return errors.New("Member not found")
+	}
+
+	existing, err := r.reviewRepo.GetReviewById(ctx, 0, input.BookID, input.MemberID)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.reviewRepo.GetReviewById got an error on ReviewService.CreateReview")
+		return err
+	}
+
+	if existing.ID != 0 {
+		_log.Error().Msg("Member already reviewed this book on ReviewService.CreateReview")
+		return errors.New("Member already reviewed this book")
+	}
+
+	input.Status = review.StatusPending
+
+	trx := r.trRepo.BeginTransaction(ctx)
+
+	err = r.reviewRepo.CreateReview(ctx, trx, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.reviewRepo.CreateReview got an error on ReviewService.CreateReview")
+		r.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	err = r.bookRepo.UpdateBookRating(ctx, trx, input.BookID)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.bookRepo.UpdateBookRating got an error on ReviewService.CreateReview")
+		r.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	r.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// UpdateReview implements ReviewServiceI.
+// An edited review goes back to pending so the new text is moderated again.
+func (r ReviewService) UpdateReview(ctx context.Context, id int64, input review.ReviewInput) (err error) {
+	ctx, end := _track.Track(ctx, "UpdateReviewUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("ReviewID cannot be nol on ReviewService.UpdateReview")
+		return errors.New("ReviewID cannot be nol")
+	}
+
+	reviewById, err := r.reviewRepo.GetReviewById(ctx, id, 0, 0)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.reviewRepo.GetReviewById got an error on ReviewService.UpdateReview")
+		return err
+	}
+
+	if reviewById.ID == 0 {
+		_log.Error().Msg("Review not found on ReviewService.UpdateReview")
+		return errors.New("Review not found")
+	}
+
+	input.BookID = reviewById.BookID
+	input.MemberID = reviewById.MemberID
+	input.Status = review.StatusPending
+
+	if input.Rating == 0 {
+		input.Rating = reviewById.Rating
+	}
+
+	if input.Review == "" {
+		input.Review = reviewById.Review
+	}
+
+	if err = r.validationInput(input); err != nil {
+		_log.Error().Err(err).Msg("r.validationInput got an error on ReviewService.UpdateReview")
+		return err
+	}
+
+	trx := r.trRepo.BeginTransaction(ctx)
+
+	err = r.reviewRepo.UpdateReview(ctx, trx, id, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.reviewRepo.UpdateReview got an error on ReviewService.UpdateReview")
+		r.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	err = r.bookRepo.UpdateBookRating(ctx, trx, reviewById.BookID)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.bookRepo.UpdateBookRating got an error on ReviewService.UpdateReview")
+		r.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	r.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// ModerateReview implements ReviewServiceI.
+func (r ReviewService) ModerateReview(ctx context.Context, id int64, input review.ReviewModerationInput) (err error) {
+	ctx, end := _track.Track(ctx, "ModerateReviewUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("ReviewID cannot be nol on ReviewService.ModerateReview")
+		return errors.New("ReviewID cannot be nol")
+	}
+
+	switch input.Status {
+	case review.StatusPending, review.StatusApproved, review.StatusRejected:
+	default:
+		_log.Error().Msgf("Status %s is not valid on ReviewService.ModerateReview", input.Status)
+		return errors.New("Status must be pending, approved or rejected")
+	}
+
+	reviewById, err := r.reviewRepo.GetReviewById(ctx, id, 0, 0)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.reviewRepo.GetReviewById got an error on ReviewService.ModerateReview")
+		return err
+	}
+
+	if reviewById.ID == 0 {
+		_log.Error().Msg("Review not found on ReviewService.ModerateReview")
+		return errors.New("Review not found")
+	}
+
+	trx := r.trRepo.BeginTransaction(ctx)
+
+	err = r.reviewRepo.UpdateReviewStatus(ctx, trx, id, input.Status)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.reviewRepo.UpdateReviewStatus got an error on ReviewService.ModerateReview")
+		r.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	err = r.bookRepo.UpdateBookRating(ctx, trx, reviewById.BookID)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.bookRepo.UpdateBookRating got an error on ReviewService.ModerateReview")
+		r.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	r.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// GetAllReviews implements ReviewServiceI.
+func (r ReviewService) GetAllReviews(ctx context.Context, search review.ReviewSearch) (resp []review.ReviewResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetAllReviewsUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	reviews, err := r.reviewRepo.GetAllReviews(ctx, search)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.reviewRepo.GetAllReviews got an error on ReviewService.GetAllReviews")
+		return resp, err
+	}
+
+	return reviews, err
+}
+
+// GetReviewByID implements ReviewServiceI.
+func (r ReviewService) GetReviewByID(ctx context.Context, id int64) (resp review.ReviewResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetReviewByIDUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("ReviewID cannot be nol on ReviewService.GetReviewByID")
+		return resp, errors.New("ReviewID cannot be nol")
+	}
+
+	reviewById, err := r.reviewRepo.GetReviewById(ctx, id, 0, 0)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.reviewRepo.GetReviewById got an error on ReviewService.GetReviewByID")
+		return resp, err
+	}
+
+	if reviewById.ID == 0 {
+		_log.Error().Msg("Review not found on ReviewService.GetReviewByID")
+		return resp, errors.New("Review not found")
+	}
+
+	return reviewById, err
+}
+
+// DeleteReviewByID implements ReviewServiceI.
+func (r ReviewService) DeleteReviewByID(ctx context.Context, id int64) (err error) {
+	ctx, end := _track.Track(ctx, "DeleteReviewByIDUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("ReviewID cannot be nol on ReviewService.DeleteReviewByID")
+		return errors.New("ReviewID cannot be nol")
+	}
+
+	reviewById, err := r.reviewRepo.GetReviewById(ctx, id, 0, 0)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.reviewRepo.GetReviewById got an error on ReviewService.DeleteReviewByID")
+		return err
+	}
+
+	if reviewById.ID == 0 {
+		_log.Error().Msg("Review not found on ReviewService.DeleteReviewByID")
+		return errors.New("Review not found")
+	}
+
+	trx := r.trRepo.BeginTransaction(ctx)
+
+	err = r.reviewRepo.DeleteReview(ctx, trx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.reviewRepo.DeleteReview got an error on ReviewService.DeleteReviewByID")
+		r.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	err = r.bookRepo.UpdateBookRating(ctx, trx, reviewById.BookID)
+	if err != nil {
+		_log.Error().Err(err).Msg("r.bookRepo.UpdateBookRating got an error on ReviewService.DeleteReviewByID")
+		r.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	r.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+func (r ReviewService) validationInput(input review.ReviewInput) (err error) {
+	if input.BookID == 0 {
+		return errors.New("BookID can not be zero / 0")
+	}
+
+	if input.MemberID == 0 {
+		return errors.New("MemberID can not be zero / 0")
+	}
+
+	if input.Rating < 1 || input.Rating > 5 {
+		return errors.New("Rating must be between 1 and 5")
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	_track "github.com/book-library/app/helper"
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/series"
+	_l "github.com/rs/zerolog/log"
+)
+
+type SeriesServiceI interface {
+	CreateSeries(ctx context.Context, input series.SeriesInput) (err error)
+	UpdateSeries(ctx context.Context, id int64, input series.SeriesInput) (err error)
+	GetSeriesByID(ctx context.Context, id int64) (resp series.SeriesResponseDetail, err error)
+	GetAllSeries(ctx context.Context, name string) (resp []series.SeriesResponse, err error)
+	DeleteSeriesByID(ctx context.Context, id int64) (err error)
+}
+
+type SeriesService struct {
+	seriesRepo _r.SeriesRepositoryI
+	trRepo     _r.TransactionRepositoryI
+}
+
+func NewSeriesService(seriesRepo _r.SeriesRepositoryI, trRepo _r.TransactionRepositoryI) SeriesServiceI {
+	return SeriesService{
+		seriesRepo: seriesRepo,
+		trRepo:     trRepo,
+	}
+}
+
+// CreateSeries implements SeriesServiceI.
+func (s SeriesService) CreateSeries(ctx context.Context, input series.SeriesInput) (err error) {
+	ctx, end := _track.Track(ctx, "CreateSeriesUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if err = s.validationInput(input); err != nil {
+		_log.Error().Err(err).Msg("s.validationInput got an error on SeriesService.CreateSeries")
+		return err
+	}
+
+	byID, err := s.seriesRepo.GetSeriesById(ctx, nil, 0, strings.ToLower(input.Name))
+	if err != nil {
+		_log.Error().Err(err).Msg("s.seriesRepo.GetSeriesById got an error on SeriesService.CreateSeries")
+		return err
+	}
+
+	if byID.ID != 0 {
+		_log.Error().Err(err).Msgf("Series %s is already exist", byID.Name)
+		return fmt.Errorf("Series %s is already exist", byID.Name)
+	}
+
+	trx := s.trRepo.BeginTransaction(ctx)
+
+	_, err = s.seriesRepo.CreateSeries(ctx, trx, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("s.seriesRepo.CreateSeries got an error on SeriesService.CreateSeries")
+		s.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	s.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// DeleteSeriesByID implements SeriesServiceI.
+func (s SeriesService) DeleteSeriesByID(ctx context.Context, id int64) (err error) {
+	ctx, end := _track.Track(ctx, "DeleteSeriesByIDUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	volumes, err := s.seriesRepo.GetSeriesVolumes(ctx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("s.seriesRepo.GetSeriesVolumes got an error on SeriesService.DeleteSeriesByID")
+		return err
+	}
+
+	if len(volumes) != 0 {
+		_log.Error().Msgf("There is book(%s) using this series and delete book(%s) first before delete series", volumes[0].Title, volumes[0].Title)
+		return fmt.Errorf("There is book(%s) using this series and delete the book(%s) first before delete series", volumes[0].Title, volumes[0].Title)
+	}
+
+	trx := s.trRepo.BeginTransaction(ctx)
+
+	err = s.seriesRepo.DeleteSeries(ctx, trx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("s.seriesRepo.DeleteSeries got an error on SeriesService.DeleteSeriesByID")
+		s.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	s.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// GetAllSeries implements SeriesServiceI.
+func (s SeriesService) GetAllSeries(ctx context.Context, name string) (resp []series.SeriesResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetAllSeriesUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	seriesList, err := s.seriesRepo.GetAllSeries(ctx, name)
+	if err != nil {
+		_log.Error().Err(err).Msg("s.seriesRepo.GetAllSeries got an error on SeriesService.GetAllSeries")
+		return resp, err
+	}
+
+	return seriesList, err
+}
+
+// GetSeriesByID implements SeriesServiceI.
+func (s SeriesService) GetSeriesByID(ctx context.Context, id int64) (resp series.SeriesResponseDetail, err error) {
+	ctx, end := _track.Track(ctx, "GetSeriesByIDUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("SeriesID cannot be nol on SeriesService.GetSeriesByID")
+		return resp, errors.New("SeriesID cannot be nol")
+	}
+
+	seriesById, err := s.seriesRepo.GetSeriesById(ctx, nil, id, "")
+	if err != nil {
+		_log.Error().Err(err).Msg("s.seriesRepo.GetSeriesById got an error on SeriesService.GetSeriesByID")
+		return resp, err
+	}
+
+	if seriesById.ID == 0 {
+		_log.Error().Err(err).Msg("Series not found on SeriesService.GetSeriesByID")
+		return resp, errors.New("Series not found")
+	}
+
+	volumes, err := s.seriesRepo.GetSeriesVolumes(ctx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("s.seriesRepo.GetSeriesVolumes got an error on SeriesService.GetSeriesByID")
+		return resp, err
+	}
+
+	resp = series.SeriesResponseDetail{
+		ID:          seriesById.ID,
+		Name:        seriesById.Name,
+		Description: seriesById.Description,
+		Volumes:     volumes,
+		CreatedAt:   seriesById.CreatedAt,
+		UpdatedAt:   seriesById.UpdatedAt,
+	}
+
+	return resp, err
+}
+
+// UpdateSeries implements SeriesServiceI.
+func (s SeriesService) UpdateSeries(ctx context.Context, id int64, input series.SeriesInput) (err error) {
+	ctx, end := _track.Track(ctx, "UpdateSeriesUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("SeriesID cannot be nol on SeriesService.UpdateSeries")
+		return errors.New("SeriesID cannot be nol")
+	}
+
+	seriesById, err := s.seriesRepo.GetSeriesById(ctx, nil, id, "")
+	if err != nil {
+		_log.Error().Err(err).Msg("s.seriesRepo.GetSeriesById got an error on SeriesService.UpdateSeries")
+		return err
+	}
+
+	if seriesById.ID == 0 {
+		_log.Error().Msg("Series not found on SeriesService.UpdateSeries")
+		return errors.New("Series not found")
+	}
+
+	if input.Name == "" {
+		input.Name = seriesById.Name
+	}
+
+	if input.Description == "" {
+		input.Description = seriesById.Description
+	}
+
+	trx := s.trRepo.BeginTransaction(ctx)
+
+	err = s.seriesRepo.UpdateSeries(ctx, trx, id, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("s.seriesRepo.UpdateSeries got an error on SeriesService.UpdateSeries")
+		s.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	s.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+func (s SeriesService) validationInput(input series.SeriesInput) (err error) {
+	if input.Name == "" {
+		return errors.New("Name can not be empty")
+	}
+
+	return nil
+}
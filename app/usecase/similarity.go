@@ -0,0 +1,12 @@
+package usecase
+
+import "errors"
+
+// validateSimilarity checks a trigram similarity threshold, where 0 turns fuzzy matching off.
+func validateSimilarity(similarity float64) error {
+	if similarity < 0 || similarity > 1 {
+		return errors.New("Similarity must be between 0 and 1")
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	_track "github.com/book-library/app/helper"
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/suggest"
+	_l "github.com/rs/zerolog/log"
+)
+
+type SuggestServiceI interface {
+	Suggest(ctx context.Context, query string, limit int) (resp []suggest.Suggestion, err error)
+}
+
+type SuggestService struct {
+	suggestRepo _r.SuggestRepositoryI
+}
+
+func NewSuggestService(suggestRepo _r.SuggestRepositoryI) SuggestServiceI {
+	return SuggestService{
+		suggestRepo: suggestRepo,
+	}
+}
+
+// suggestTypeRank orders suggestions of the same quality: books first, then authors and categories.
+var suggestTypeRank = map[string]int{
+	suggest.TypeBook:     0,
+	suggest.TypeAuthor:   1,
+	suggest.TypeCategory: 2,
+}
+
+// Suggest implements SuggestServiceI.
+// It returns at most limit suggestions of each type for names starting with query. Exact
+// matches rank first, then shorter names, which are closer to what was typed.
+func (s SuggestService) Suggest(ctx context.Context, query string, limit int) (resp []suggest.Suggestion, err error) {
+	ctx, end := _track.Track(ctx, "Suggest")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	query = strings.TrimSpace(query)
+	if len([]rune(query)) < suggest.MinQueryLength {
+		return []suggest.Suggestion{}, nil
+	}
+
+	if limit <= 0 {
+		limit = suggest.DefaultLimit
+	}
+
+	if limit > suggest.MaxLimit {
+		limit = suggest.MaxLimit
+	}
+
+	resp, err = s.suggestRepo.GetSuggestions(ctx, query, limit)
+	if err != nil {
+		_log.Error().Err(err).Msg("s.suggestRepo.GetSuggestions got an error on SuggestService.Suggest")
+		return resp, err
+	}
+
+	lowered := strings.ToLower(query)
+	exact := func(i int) bool { return strings.ToLower(resp[i].Label) == lowered }
+	sort.SliceStable(resp, func(i, j int) bool {
+		if exact(i) != exact(j) {
+			return exact(i)
+		}
+
+		if len(resp[i].Label) != len(resp[j].Label) {
+			return len(resp[i].Label) < len(resp[j].Label)
+		}
+
+		if resp[i].Type != resp[j].Type {
+			return suggestTypeRank[resp[i].Type] < suggestTypeRank[resp[j].Type]
+		}
+
+		return strings.ToLower(resp[i].Label) < strings.ToLower(resp[j].Label)
+	})
+
+	if resp == nil {
+		resp = []suggest.Suggestion{}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,532 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	_track "github.com/book-library/app/helper"
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/webhook"
+	"github.com/google/uuid"
+	_l "github.com/rs/zerolog/log"
+)
+
+// EventPublisherI fans an event out to the webhook subscribers of its type.
+type EventPublisherI interface {
+	Publish(ctx context.Context, event webhook.Event) (err error)
+}
+
+type WebhookServiceI interface {
+	EventPublisherI
+
+	CreateWebhook(ctx context.Context, input webhook.WebhookInput) (err error)
+	UpdateWebhook(ctx context.Context, id int64, input webhook.WebhookInput) (err error)
+	GetWebhookByID(ctx context.Context, id int64) (resp webhook.WebhookResponse, err error)
+	GetAllWebhooks(ctx context.Context) (resp []webhook.WebhookResponse, err error)
+	DeleteWebhookByID(ctx context.Context, id int64) (err error)
+	GetWebhookDeliveries(ctx context.Context, id int64) (resp []webhook.WebhookDeliveryResponse, err error)
+	PingWebhook(ctx context.Context, id int64) (resp webhook.WebhookDeliveryResponse, err error)
+
+	Run(ctx context.Context)
+	DeliverPending(ctx context.Context) (attempted int, err error)
+}
+
+// WebhookConfig controls how deliveries are retried. The wait before attempt n+1
+// is RetryBackoff * 2^(n-1). The delivery worker looks for due deliveries every PollInterval
+// and sends up to BatchSize at a time.
+type WebhookConfig struct {
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	Timeout      time.Duration
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+type WebhookService struct {
+	webhookRepo _r.WebhookRepositoryI
+	trRepo      _r.TransactionRepositoryI
+	client      *http.Client
+	config      WebhookConfig
+}
+
+func NewWebhookService(webhookRepo _r.WebhookRepositoryI, trRepo _r.TransactionRepositoryI, config WebhookConfig) WebhookServiceI {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 1
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = 20
+	}
+
+	return WebhookService{
+		webhookRepo: webhookRepo,
+		trRepo:      trRepo,
+		client:      &http.Client{Timeout: config.Timeout},
+		config:      config,
+	}
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>" under the subscription secret.
+// Receivers recompute it to check the payload came from us and was not replayed with another timestamp.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Publish implements EventPublisherI.
+// A pending delivery is stored for every subscriber and sent by the delivery worker, see Run, so
+// a slow subscriber never holds up the caller. Once Publish returns the deliveries survive a
+// restart; an error means they could not be stored and the event should be published again.
+func (w WebhookService) Publish(ctx context.Context, event webhook.Event) (err error) {
+	_log := _l.Ctx(ctx)
+
+	subscriptions, err := w.webhookRepo.GetWebhooksByEvent(ctx, event.Type)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.webhookRepo.GetWebhooksByEvent got an error on WebhookService.Publish")
+		return err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	if event.ID == "" {
+		event.ID = uuid.New().String()
+	}
+
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		_log.Error().Err(err).Msg("json.Marshal got an error on WebhookService.Publish")
+		return err
+	}
+
+	now := time.Now()
+	for _, subscription := range subscriptions {
+		if _, err = w.newDelivery(ctx, subscription, event, body, &now); err != nil {
+			_log.Error().Err(err).Msgf("w.newDelivery got an error on WebhookService.Publish for webhook %d", subscription.ID)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newDelivery stores a pending delivery. One without nextAttemptAt is never picked up by the
+// delivery worker.
+func (w WebhookService) newDelivery(ctx context.Context, subscription webhook.WebhookSubscription, event webhook.Event, body []byte, nextAttemptAt *time.Time) (delivery webhook.WebhookDeliveryInput, err error) {
+	delivery = webhook.WebhookDeliveryInput{
+		WebhookID:     subscription.ID,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		Payload:       string(body),
+		Status:        webhook.DeliveryStatusPending,
+		NextAttemptAt: nextAttemptAt,
+	}
+
+	delivery.ID, err = w.webhookRepo.CreateWebhookDelivery(ctx, delivery)
+
+	return delivery, err
+}
+
+// Run implements WebhookServiceI.
+// It sends the due deliveries until ctx is cancelled; an in-flight batch is finished before it
+// returns.
+func (w WebhookService) Run(ctx context.Context) {
+	_log := _l.Ctx(ctx)
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		attempted, err := w.DeliverPending(context.WithoutCancel(ctx))
+		if err != nil {
+			_log.Error().Err(err).Msg("w.DeliverPending got an error on WebhookService.Run")
+		}
+
+		// A full batch means more deliveries are probably due, so skip the wait.
+		if attempted == w.config.BatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverPending implements WebhookServiceI.
+// The due deliveries are claimed for twice the send timeout and sent concurrently. A failed
+// attempt is scheduled again after RetryBackoff * 2^(attempts-1) until MaxAttempts is reached.
+func (w WebhookService) DeliverPending(ctx context.Context) (attempted int, err error) {
+	_log := _l.Ctx(ctx)
+
+	deliveries, err := w.webhookRepo.ClaimDueWebhookDeliveries(ctx, 2*w.config.Timeout, w.config.BatchSize)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.webhookRepo.ClaimDueWebhookDeliveries got an error on WebhookService.DeliverPending")
+		return attempted, err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery webhook.WebhookDeliveryInput) {
+			defer wg.Done()
+			w.deliverPending(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (w WebhookService) deliverPending(ctx context.Context, delivery webhook.WebhookDeliveryInput) {
+	_log := _l.Ctx(ctx)
+
+	subscription, err := w.webhookRepo.GetWebhookById(ctx, delivery.WebhookID)
+	if err != nil {
+		// The claim runs out and the delivery is tried again.
+		_log.Error().Err(err).Msg("w.webhookRepo.GetWebhookById got an error on WebhookService.deliverPending")
+		return
+	}
+
+	if subscription.ID == 0 {
+		// Deleted together with its deliveries while this one was claimed.
+		return
+	}
+
+	if !subscription.ActiveFlag {
+		delivery.Status, delivery.LastError, delivery.NextAttemptAt = webhook.DeliveryStatusFailed, "webhook is inactive", nil
+		if err = w.webhookRepo.UpdateWebhookDelivery(ctx, delivery); err != nil {
+			_log.Error().Err(err).Msg("w.webhookRepo.UpdateWebhookDelivery got an error on WebhookService.deliverPending")
+		}
+		return
+	}
+
+	w.attempt(ctx, subscription, delivery, w.config.MaxAttempts)
+}
+
+// attempt posts the payload once and records the outcome in the delivery log. Short of 2xx the
+// delivery stays pending for its next attempt, or fails once maxAttempts is reached.
+func (w WebhookService) attempt(ctx context.Context, subscription webhook.WebhookSubscription, delivery webhook.WebhookDeliveryInput, maxAttempts int) webhook.WebhookDeliveryInput {
+	_log := _l.Ctx(ctx)
+
+	delivery.Attempts++
+	delivery.ResponseCode, delivery.LastError = w.send(ctx, subscription, delivery, []byte(delivery.Payload))
+
+	now := time.Now()
+	switch {
+	case delivery.ResponseCode >= 200 && delivery.ResponseCode < 300:
+		delivery.Status, delivery.LastError = webhook.DeliveryStatusSuccess, ""
+		delivery.DeliveredAt, delivery.NextAttemptAt = &now, nil
+	case delivery.Attempts >= maxAttempts:
+		delivery.Status, delivery.NextAttemptAt = webhook.DeliveryStatusFailed, nil
+	default:
+		next := now.Add(w.config.RetryBackoff << (delivery.Attempts - 1))
+		delivery.NextAttemptAt = &next
+	}
+
+	if err := w.webhookRepo.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		_log.Error().Err(err).Msg("w.webhookRepo.UpdateWebhookDelivery got an error on WebhookService.attempt")
+	}
+
+	return delivery
+}
+
+func (w WebhookService) send(ctx context.Context, subscription webhook.WebhookSubscription, delivery webhook.WebhookDeliveryInput, body []byte) (code int, errMessage string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.TargetURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err.Error()
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.EventHeader, delivery.EventType)
+	req.Header.Set(webhook.DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(webhook.TimestampHeader, timestamp)
+	req.Header.Set(webhook.SignatureHeader, SignWebhookPayload(subscription.Secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Sprintf("subscriber responded with %s", resp.Status)
+	}
+
+	return resp.StatusCode, ""
+}
+
+// PingWebhook implements WebhookServiceI.
+// The ping is sent once and synchronously so the caller sees the subscriber's answer.
+func (w WebhookService) PingWebhook(ctx context.Context, id int64) (resp webhook.WebhookDeliveryResponse, err error) {
+	ctx, end := _track.Track(ctx, "PingWebhookUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	subscription, err := w.webhookRepo.GetWebhookById(ctx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.webhookRepo.GetWebhookById got an error on WebhookService.PingWebhook")
+		return resp, err
+	}
+
+	if subscription.ID == 0 {
+		_log.Error().Msg("Webhook not found on WebhookService.PingWebhook")
+		return resp, errors.New("Webhook not found")
+	}
+
+	event := webhook.Event{
+		ID:         uuid.New().String(),
+		Type:       webhook.EventPing,
+		OccurredAt: time.Now().UTC(),
+		Data:       map[string]interface{}{"webhook_id": subscription.ID},
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		_log.Error().Err(err).Msg("json.Marshal got an error on WebhookService.PingWebhook")
+		return resp, err
+	}
+
+	delivery, err := w.newDelivery(ctx, subscription, event, body, nil)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.newDelivery got an error on WebhookService.PingWebhook")
+		return resp, err
+	}
+
+	delivery = w.attempt(ctx, subscription, delivery, 1)
+
+	resp = webhook.WebhookDeliveryResponse(delivery)
+
+	return resp, err
+}
+
+// CreateWebhook implements WebhookServiceI.
+func (w WebhookService) CreateWebhook(ctx context.Context, input webhook.WebhookInput) (err error) {
+	ctx, end := _track.Track(ctx, "CreateWebhookUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if input.ActiveFlag == nil {
+		active := true
+		input.ActiveFlag = &active
+	}
+
+	if err = w.validationInput(input); err != nil {
+		_log.Error().Err(err).Msg("w.validationInput got an error on WebhookService.CreateWebhook")
+		return err
+	}
+
+	trx := w.trRepo.BeginTransaction(ctx)
+
+	err = w.webhookRepo.CreateWebhook(ctx, trx, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.webhookRepo.CreateWebhook got an error on WebhookService.CreateWebhook")
+		w.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	w.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// UpdateWebhook implements WebhookServiceI.
+func (w WebhookService) UpdateWebhook(ctx context.Context, id int64, input webhook.WebhookInput) (err error) {
+	ctx, end := _track.Track(ctx, "UpdateWebhookUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("WebhookID cannot be nol on WebhookService.UpdateWebhook")
+		return errors.New("WebhookID cannot be nol")
+	}
+
+	subscription, err := w.webhookRepo.GetWebhookById(ctx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.webhookRepo.GetWebhookById got an error on WebhookService.UpdateWebhook")
+		return err
+	}
+
+	if subscription.ID == 0 {
+		_log.Error().Msg("Webhook not found on WebhookService.UpdateWebhook")
+		return errors.New("Webhook not found")
+	}
+
+	if input.TargetURL == "" {
+		input.TargetURL = subscription.TargetURL
+	}
+
+	if input.Secret == "" {
+		input.Secret = subscription.Secret
+	}
+
+	if len(input.EventTypes) == 0 {
+		input.EventTypes = subscription.EventTypes
+	}
+
+	if input.ActiveFlag == nil {
+		input.ActiveFlag = &subscription.ActiveFlag
+	}
+
+	if err = w.validationInput(input); err != nil {
+		_log.Error().Err(err).Msg("w.validationInput got an error on WebhookService.UpdateWebhook")
+		return err
+	}
+
+	trx := w.trRepo.BeginTransaction(ctx)
+
+	err = w.webhookRepo.UpdateWebhook(ctx, trx, id, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.webhookRepo.UpdateWebhook got an error on WebhookService.UpdateWebhook")
+		w.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	w.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// GetWebhookByID implements WebhookServiceI.
+func (w WebhookService) GetWebhookByID(ctx context.Context, id int64) (resp webhook.WebhookResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetWebhookByIDUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("WebhookID cannot be nol on WebhookService.GetWebhookByID")
+		return resp, errors.New("WebhookID cannot be nol")
+	}
+
+	subscription, err := w.webhookRepo.GetWebhookById(ctx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.webhookRepo.GetWebhookById got an error on WebhookService.GetWebhookByID")
+		return resp, err
+	}
+
+	if subscription.ID == 0 {
+		_log.Error().Msg("Webhook not found on WebhookService.GetWebhookByID")
+		return resp, errors.New("Webhook not found")
+	}
+
+	resp = webhook.WebhookResponse{
+		ID:         subscription.ID,
+		TargetURL:  subscription.TargetURL,
+		EventTypes: subscription.EventTypes,
+		ActiveFlag: subscription.ActiveFlag,
+	}
+
+	return resp, err
+}
+
+// GetAllWebhooks implements WebhookServiceI.
+func (w WebhookService) GetAllWebhooks(ctx context.Context) (resp []webhook.WebhookResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetAllWebhooksUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	webhooks, err := w.webhookRepo.GetAllWebhooks(ctx)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.webhookRepo.GetAllWebhooks got an error on WebhookService.GetAllWebhooks")
+		return resp, err
+	}
+
+	return webhooks, err
+}
+
+// DeleteWebhookByID implements WebhookServiceI.
+func (w WebhookService) DeleteWebhookByID(ctx context.Context, id int64) (err error) {
+	ctx, end := _track.Track(ctx, "DeleteWebhookByIDUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("WebhookID cannot be nol on WebhookService.DeleteWebhookByID")
+		return errors.New("WebhookID cannot be nol")
+	}
+
+	trx := w.trRepo.BeginTransaction(ctx)
+
+	err = w.webhookRepo.DeleteWebhook(ctx, trx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.webhookRepo.DeleteWebhook got an error on WebhookService.DeleteWebhookByID")
+		w.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	w.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// GetWebhookDeliveries implements WebhookServiceI.
+func (w WebhookService) GetWebhookDeliveries(ctx context.Context, id int64) (resp []webhook.WebhookDeliveryResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetWebhookDeliveriesUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	deliveries, err := w.webhookRepo.GetWebhookDeliveries(ctx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.webhookRepo.GetWebhookDeliveries got an error on WebhookService.GetWebhookDeliveries")
+		return resp, err
+	}
+
+	return deliveries, err
+}
+
+func (w WebhookService) validationInput(input webhook.WebhookInput) (err error) {
+	target, err := url.ParseRequestURI(input.TargetURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return errors.New("TargetURL must be an absolute http or https URL")
+	}
+
+	if input.Secret == "" {
+		return errors.New("Secret can not be empty")
+	}
+
+	if len(input.EventTypes) == 0 {
+		return errors.New("EventTypes can not be empty")
+	}
+
+	for _, eventType := range input.EventTypes {
+		if !slices.Contains(webhook.EventTypes, eventType) {
+			return fmt.Errorf("EventType %s is not supported", eventType)
+		}
+	}
+
+	return nil
+}
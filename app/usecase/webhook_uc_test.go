@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/webhook"
+)
+
+const testWebhookSecret = "s3cret"
+
+// webhookReceiver is an httptest subscriber answering the nth request with codes[n-1], and the
+// last code after that.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	codes    []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	rcv.requests = append(rcv.requests, r)
+	rcv.bodies = append(rcv.bodies, body)
+	w.WriteHeader(rcv.codes[min(len(rcv.requests), len(rcv.codes))-1])
+}
+
+func (rcv *webhookReceiver) calls() int {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+
+	return len(rcv.requests)
+}
+
+func newTestWebhookService(t *testing.T, config WebhookConfig, codes ...int) (WebhookServiceI, *webhookReceiver) {
+	t.Helper()
+
+	receiver := &webhookReceiver{codes: codes}
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	store := _r.NewMemoryStore()
+	service := NewWebhookService(_r.NewMemoryWebhookRepository(store), _r.NewMemoryTransactionRepository(store), config)
+
+	err := service.CreateWebhook(context.Background(), webhook.WebhookInput{
+		TargetURL:  server.URL,
+		Secret:     testWebhookSecret,
+		EventTypes: []string{webhook.EventBookCreated},
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+
+	return service, receiver
+}
+
+// deliverAll runs the delivery worker until the delivery of the only webhook is no longer
+// pending and returns it.
+func deliverAll(t *testing.T, service WebhookServiceI) webhook.WebhookDeliveryResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if _, err := service.DeliverPending(ctx); err != nil {
+			t.Fatalf("DeliverPending: %v", err)
+		}
+
+		deliveries, err := service.GetWebhookDeliveries(ctx, 1)
+		if err != nil {
+			t.Fatalf("GetWebhookDeliveries: %v", err)
+		}
+
+		if len(deliveries) != 1 {
+			t.Fatalf("deliveries = %d, want 1", len(deliveries))
+		}
+
+		if deliveries[0].Status != webhook.DeliveryStatusPending {
+			return deliveries[0]
+		}
+	}
+
+	t.Fatal("delivery still pending")
+	return webhook.WebhookDeliveryResponse{}
+}
+
+func publishBookCreated(t *testing.T, service WebhookServiceI) {
+	t.Helper()
+
+	err := service.Publish(context.Background(), webhook.Event{ID: "42", Type: webhook.EventBookCreated, Data: map[string]int{"id": 7}})
+	if err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+}
+
+func TestWebhookDeliveryIsSigned(t *testing.T) {
+	service, receiver := newTestWebhookService(t, WebhookConfig{MaxAttempts: 1}, http.StatusOK)
+	publishBookCreated(t, service)
+
+	if delivery := deliverAll(t, service); delivery.Status != webhook.DeliveryStatusSuccess {
+		t.Fatalf("status = %s, want %s", delivery.Status, webhook.DeliveryStatusSuccess)
+	}
+
+	req, body := receiver.requests[0], receiver.bodies[0]
+
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(req.Header.Get(webhook.TimestampHeader) + "."))
+	mac.Write(body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); req.Header.Get(webhook.SignatureHeader) != want {
+		t.Errorf("signature = %s, want %s", req.Header.Get(webhook.SignatureHeader), want)
+	}
+
+	if SignWebhookPayload("other", req.Header.Get(webhook.TimestampHeader), body) == req.Header.Get(webhook.SignatureHeader) {
+		t.Error("signature verifies under another secret")
+	}
+
+	if got := req.Header.Get(webhook.EventHeader); got != webhook.EventBookCreated {
+		t.Errorf("event header = %s, want %s", got, webhook.EventBookCreated)
+	}
+
+	event := webhook.Event{}
+	if err := json.Unmarshal(body, &event); err != nil || event.ID != "42" || event.Type != webhook.EventBookCreated {
+		t.Errorf("body = %s, want event 42 of type %s", body, webhook.EventBookCreated)
+	}
+}
+
+func TestWebhookDeliveryRetriesUntilSuccess(t *testing.T) {
+	service, receiver := newTestWebhookService(t, WebhookConfig{MaxAttempts: 5, RetryBackoff: time.Millisecond},
+		http.StatusInternalServerError, http.StatusBadGateway, http.StatusNoContent)
+	publishBookCreated(t, service)
+
+	delivery := deliverAll(t, service)
+	if delivery.Status != webhook.DeliveryStatusSuccess || delivery.Attempts != 3 || delivery.ResponseCode != http.StatusNoContent {
+		t.Errorf("delivery = %s after %d attempts with %d, want success after 3 with 204", delivery.Status, delivery.Attempts, delivery.ResponseCode)
+	}
+
+	if delivery.LastError != "" || delivery.DeliveredAt == nil || delivery.NextAttemptAt != nil {
+		t.Errorf("delivery = %+v, want no error, a delivery time and no next attempt", delivery)
+	}
+
+	if receiver.calls() != 3 {
+		t.Errorf("calls = %d, want 3", receiver.calls())
+	}
+}
+
+func TestWebhookDeliveryStopsAtMaxAttempts(t *testing.T) {
+	service, receiver := newTestWebhookService(t, WebhookConfig{MaxAttempts: 3, RetryBackoff: time.Millisecond}, http.StatusInternalServerError)
+	publishBookCreated(t, service)
+
+	delivery := deliverAll(t, service)
+	if delivery.Status != webhook.DeliveryStatusFailed || delivery.Attempts != 3 || delivery.ResponseCode != http.StatusInternalServerError {
+		t.Errorf("delivery = %s after %d attempts with %d, want failed after 3 with 500", delivery.Status, delivery.Attempts, delivery.ResponseCode)
+	}
+
+	if delivery.LastError == "" || delivery.NextAttemptAt != nil {
+		t.Errorf("delivery = %+v, want an error and no next attempt", delivery)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if attempted, _ := service.DeliverPending(context.Background()); attempted != 0 || receiver.calls() != 3 {
+		t.Errorf("after failing: attempted %d, calls %d, want 0 and 3", attempted, receiver.calls())
+	}
+}
+
+func TestWebhookRetryWaitsForBackoff(t *testing.T) {
+	service, receiver := newTestWebhookService(t, WebhookConfig{MaxAttempts: 3, RetryBackoff: time.Hour}, http.StatusInternalServerError)
+	publishBookCreated(t, service)
+
+	ctx := context.Background()
+	if attempted, err := service.DeliverPending(ctx); attempted != 1 || err != nil {
+		t.Fatalf("DeliverPending = %d, %v, want 1 attempt", attempted, err)
+	}
+
+	deliveries, _ := service.GetWebhookDeliveries(ctx, 1)
+	if next := deliveries[0].NextAttemptAt; deliveries[0].Status != webhook.DeliveryStatusPending || next == nil || time.Until(*next) < 59*time.Minute {
+		t.Errorf("delivery = %+v, want pending with the next attempt an hour away", deliveries[0])
+	}
+
+	if attempted, _ := service.DeliverPending(ctx); attempted != 0 || receiver.calls() != 1 {
+		t.Errorf("before the backoff: attempted %d, calls %d, want 0 and 1", attempted, receiver.calls())
+	}
+}
@@ -0,0 +1,312 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	_track "github.com/book-library/app/helper"
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/book"
+	"github.com/book-library/entity/outbox"
+	"github.com/book-library/entity/webhook"
+	"github.com/book-library/entity/work"
+	_l "github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type WorkServiceI interface {
+	CreateWork(ctx context.Context, input work.WorkInput) (err error)
+	UpdateWork(ctx context.Context, id int64, input work.WorkInput) (err error)
+	GetWorkByID(ctx context.Context, id int64) (resp work.WorkResponseDetail, err error)
+	GetAllWorks(ctx context.Context, title string) (resp []work.WorkResponse, err error)
+	DeleteWorkByID(ctx context.Context, id int64) (err error)
+}
+
+type WorkService struct {
+	workRepo   _r.WorkRepositoryI
+	trRepo     _r.TransactionRepositoryI
+	bookRepo   _r.BookLibraryRepositoryI
+	outboxRepo _r.OutboxRepositoryI
+}
+
+func NewWorkService(workRepo _r.WorkRepositoryI, trRepo _r.TransactionRepositoryI, bookRepo _r.BookLibraryRepositoryI, outboxRepo _r.OutboxRepositoryI) WorkServiceI {
+	return WorkService{
+		workRepo:   workRepo,
+		trRepo:     trRepo,
+		bookRepo:   bookRepo,
+		outboxRepo: outboxRepo,
+	}
+}
+
+// CreateWork implements WorkServiceI.
+func (w WorkService) CreateWork(ctx context.Context, input work.WorkInput) (err error) {
+	ctx, end := _track.Track(ctx, "CreateWorkUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if err = w.validationInput(input); err != nil {
+		_log.Error().Err(err).Msg("w.validationInput got an error on WorkService.CreateWork")
+		return err
+	}
+
+	trx := w.trRepo.BeginTransaction(ctx)
+
+	_, err = w.workRepo.CreateWork(ctx, trx, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.workRepo.CreateWork got an error on WorkService.CreateWork")
+		w.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	w.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// DeleteWorkByID implements WorkServiceI.
+func (w WorkService) DeleteWorkByID(ctx context.Context, id int64) (err error) {
+	ctx, end := _track.Track(ctx, "DeleteWorkByIDUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	editions, err := w.workRepo.GetWorkEditions(ctx, nil, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.workRepo.GetWorkEditions got an error on WorkService.DeleteWorkByID")
+		return err
+	}
+
+	if len(editions) != 0 {
+		_log.Error().Msgf("There is book(%s) using this work and delete book(%s) first before delete work", editions[0].Title, editions[0].Title)
+		return fmt.Errorf("There is book(%s) using this work and delete the book(%s) first before delete work", editions[0].Title, editions[0].Title)
+	}
+
+	trx := w.trRepo.BeginTransaction(ctx)
+
+	err = w.workRepo.DeleteWork(ctx, trx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.workRepo.DeleteWork got an error on WorkService.DeleteWorkByID")
+		w.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	w.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// GetAllWorks implements WorkServiceI.
+func (w WorkService) GetAllWorks(ctx context.Context, title string) (resp []work.WorkResponse, err error) {
+	ctx, end := _track.Track(ctx, "GetAllWorksUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	works, err := w.workRepo.GetAllWorks(ctx, nil, title)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.workRepo.GetAllWorks got an error on WorkService.GetAllWorks")
+		return resp, err
+	}
+
+	return works, err
+}
+
+// GetWorkByID implements WorkServiceI.
+func (w WorkService) GetWorkByID(ctx context.Context, id int64) (resp work.WorkResponseDetail, err error) {
+	ctx, end := _track.Track(ctx, "GetWorkByIDUC")
+	defer end()
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("WorkID cannot be nol on WorkService.GetWorkByID")
+		return resp, errors.New("WorkID cannot be nol")
+	}
+
+	workById, err := w.workRepo.GetWorkById(ctx, nil, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.workRepo.GetWorkById got an error on WorkService.GetWorkByID")
+		return resp, err
+	}
+
+	if workById.ID == 0 {
+		_log.Error().Err(err).Msg("Work not found on WorkService.GetWorkByID")
+		return resp, errors.New("Work not found")
+	}
+
+	editions, err := w.workRepo.GetWorkEditions(ctx, nil, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.workRepo.GetWorkEditions got an error on WorkService.GetWorkByID")
+		return resp, err
+	}
+
+	resp = work.WorkResponseDetail{
+		ID:                   workById.ID,
+		Title:                workById.Title,
+		Description:          workById.Description,
+		AuthorID:             workById.AuthorID,
+		CategoryID:           workById.CategoryID,
+		OriginalLanguage:     workById.OriginalLanguage,
+		FirstPublicationYear: workById.FirstPublicationYear,
+		Editions:             editions,
+		CreatedAt:            workById.CreatedAt,
+		UpdatedAt:            workById.UpdatedAt,
+	}
+
+	return resp, err
+}
+
+// UpdateWork implements WorkServiceI.
+// Editions that still carry the title, description, author, category or language of the work
+// inherited them and follow the update; values an edition overrode are kept.
+func (w WorkService) UpdateWork(ctx context.Context, id int64, input work.WorkInput) (err error) {
+	ctx, end := _track.Track(ctx, "UpdateWorkUC")
+	defer end()
+
+	trx := w.trRepo.BeginTransaction(ctx)
+
+	err = w.updateWork(ctx, trx, id, input)
+	if err != nil {
+		w.trRepo.RollBackTransaction(ctx, trx)
+		return err
+	}
+
+	w.trRepo.CommitTransaction(ctx, trx)
+
+	return err
+}
+
+// updateWork fills the fields input leaves empty from the stored work and writes it, with the
+// editions that follow it, in trx.
+func (w WorkService) updateWork(ctx context.Context, trx *gorm.DB, id int64, input work.WorkInput) (err error) {
+	_log := _l.Ctx(ctx)
+
+	if id == 0 {
+		_log.Error().Msg("WorkID cannot be nol on WorkService.UpdateWork")
+		return errors.New("WorkID cannot be nol")
+	}
+
+	workById, err := w.workRepo.GetWorkById(ctx, trx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.workRepo.GetWorkById got an error on WorkService.UpdateWork")
+		return err
+	}
+
+	if workById.ID == 0 {
+		_log.Error().Msg("Work not found on WorkService.UpdateWork")
+		return errors.New("Work not found")
+	}
+
+	if input.Title == "" {
+		input.Title = workById.Title
+	}
+
+	if input.Description == "" {
+		input.Description = workById.Description
+	}
+
+	if input.AuthorID == 0 {
+		input.AuthorID = workById.AuthorID
+	}
+
+	if input.CategoryID == 0 {
+		input.CategoryID = workById.CategoryID
+	}
+
+	if input.OriginalLanguage == "" {
+		input.OriginalLanguage = workById.OriginalLanguage
+	}
+
+	if input.FirstPublicationYear == 0 {
+		input.FirstPublicationYear = workById.FirstPublicationYear
+	}
+
+	err = w.workRepo.UpdateWork(ctx, trx, id, input)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.workRepo.UpdateWork got an error on WorkService.UpdateWork")
+		return err
+	}
+
+	editions, err := w.workRepo.GetWorkEditions(ctx, trx, id)
+	if err != nil {
+		_log.Error().Err(err).Msg("w.workRepo.GetWorkEditions got an error on WorkService.UpdateWork")
+		return err
+	}
+
+	for _, edition := range editions {
+		bookById, err := w.bookRepo.GetBookLibraryById(ctx, trx, edition.BookID, 0, 0, 0)
+		if err != nil {
+			_log.Error().Err(err).Msg("w.bookRepo.GetBookLibraryById got an error on WorkService.UpdateWork")
+			return err
+		}
+
+		bookInput := bookInputFromResponse(bookById)
+		columns := followWork(&bookInput, workById, input)
+		if len(columns) == 0 {
+			continue
+		}
+
+		err = w.bookRepo.UpdateBookLibrary(ctx, trx, bookById.ID, bookInput, columns...)
+		if err != nil {
+			_log.Error().Err(err).Msg("w.bookRepo.UpdateBookLibrary got an error on WorkService.UpdateWork")
+			return err
+		}
+
+		err = recordOutboxEvent(ctx, w.outboxRepo, trx, outbox.AggregateBook, bookById.ID, webhook.EventBookUpdated, webhook.ResourcePayload{ID: bookById.ID, Attributes: bookInput})
+		if err != nil {
+			_log.Error().Err(err).Msg("recordOutboxEvent got an error on WorkService.UpdateWork")
+			return err
+		}
+	}
+
+	return err
+}
+
+// followWork moves the fields edition still shares with previous, the work before the update,
+// to updated and returns the columns it changed.
+func followWork(edition *book.BookInput, previous work.WorkResponse, updated work.WorkInput) (columns []string) {
+	if edition.Title == previous.Title && updated.Title != previous.Title {
+		edition.Title = updated.Title
+		columns = append(columns, "title")
+	}
+
+	if edition.Description == previous.Description && updated.Description != previous.Description {
+		edition.Description = updated.Description
+		columns = append(columns, "description")
+	}
+
+	if edition.AuthorID == previous.AuthorID && updated.AuthorID != previous.AuthorID {
+		edition.AuthorID = updated.AuthorID
+		columns = append(columns, "author_id")
+	}
+
+	if edition.CategoryID == previous.CategoryID && updated.CategoryID != previous.CategoryID {
+		edition.CategoryID = updated.CategoryID
+		columns = append(columns, "category_id")
+	}
+
+	if edition.Language == previous.OriginalLanguage && updated.OriginalLanguage != previous.OriginalLanguage {
+		edition.Language = updated.OriginalLanguage
+		columns = append(columns, "language")
+	}
+
+	return columns
+}
+
+func (w WorkService) validationInput(input work.WorkInput) (err error) {
+	if input.Title == "" {
+		return errors.New("Title can not be empty")
+	}
+
+	if input.AuthorID == 0 {
+		return errors.New("AuthorID can not be zero / 0")
+	}
+
+	if input.CategoryID == 0 {
+		return errors.New("CategoryID can not be zero / 0")
+	}
+
+	if input.FirstPublicationYear < 0 {
+		return errors.New("FirstPublicationYear can not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	_r "github.com/book-library/app/repository"
+	"github.com/book-library/entity/author"
+	"github.com/book-library/entity/book"
+	"github.com/book-library/entity/category"
+	"github.com/book-library/entity/work"
+)
+
+func TestUpdateWorkReachesInheritedEditionFields(t *testing.T) {
+	ctx := context.Background()
+	store := _r.NewMemoryStore()
+	trRepo, bookRepo, workRepo, outboxRepo := _r.NewMemoryTransactionRepository(store), _r.NewMemoryBookLibraryRepository(store),
+		_r.NewMemoryWorkRepository(store), _r.NewMemoryOutboxRepository(store)
+	authorRepo, categoryRepo := _r.NewMemoryAuthorRepository(store), _r.NewMemoryCategoryRepository(store)
+
+	bookService := NewbookLibraryService(bookRepo, trRepo, authorRepo, categoryRepo, _r.NewMemoryPublisherRepository(store),
+		_r.NewMemorySeriesRepository(store), workRepo, outboxRepo)
+	workService := NewWorkService(workRepo, trRepo, bookRepo, outboxRepo)
+
+	authorRepo.CreateAuthor(ctx, nil, author.AuthorInput{Name: "Ann Leckie", Email: "ann@example.com"})
+	categoryRepo.CreateCategory(ctx, nil, category.CategoryInput{Name: "Science Fiction"})
+
+	err := workService.CreateWork(ctx, work.WorkInput{Title: "Ancillary Justice", Description: "Breq", AuthorID: 1, CategoryID: 1, OriginalLanguage: "en"})
+	if err != nil {
+		t.Fatalf("CreateWork: %v", err)
+	}
+
+	workID := int64(1)
+	for _, input := range []book.BookInput{
+		{ISBN: "9780316246620", WorkID: &workID},
+		{ISBN: "9788498891041", Title: "Justicia Auxiliar", Language: "es", WorkID: &workID},
+	} {
+		if err = bookService.CreateBook(ctx, input); err != nil {
+			t.Fatalf("CreateBook: %v", err)
+		}
+	}
+
+	err = workService.UpdateWork(ctx, workID, work.WorkInput{Title: "Ancillary Justice (Imperial Radch)", Description: "One Esk"})
+	if err != nil {
+		t.Fatalf("UpdateWork: %v", err)
+	}
+
+	tests := []struct {
+		id          int64
+		title       string
+		description string
+		language    string
+	}{
+		{id: 1, title: "Ancillary Justice (Imperial Radch)", description: "One Esk", language: "en"},
+		{id: 2, title: "Justicia Auxiliar", description: "One Esk", language: "es"},
+	}
+
+	for _, tt := range tests {
+		edition, err := bookService.GetBookByID(ctx, tt.id)
+		if err != nil {
+			t.Fatalf("GetBookByID(%d): %v", tt.id, err)
+		}
+
+		if edition.Title != tt.title || edition.Description != tt.description || edition.Language != tt.language {
+			t.Errorf("edition %d = %q, %q, %q, want %q, %q, %q", tt.id, edition.Title, edition.Description, edition.Language, tt.title, tt.description, tt.language)
+		}
+	}
+}
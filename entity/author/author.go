@@ -6,6 +6,7 @@ import (
 
 type (
 	AuthorInput struct {
+		ID        int64      `json:"-"`
 		Name      string     `json:"name"`
 		Email     string     `json:"email"`
 		CreatedAt time.Time  `json:"created_at"`
@@ -30,4 +31,16 @@ type (
 		Name  string `json:"name"`
 		Email string `json:"email"`
 	}
+
+	// AuthorBatchOperation is one entry of a batch request. ID is required for update and delete.
+	AuthorBatchOperation struct {
+		Op   string      `json:"op"`
+		ID   int64       `json:"id"`
+		Data AuthorInput `json:"data"`
+	}
+
+	AuthorBatchInput struct {
+		Mode       string                 `json:"mode"`
+		Operations []AuthorBatchOperation `json:"operations"`
+	}
 )
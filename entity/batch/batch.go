@@ -0,0 +1,36 @@
+package batch
+
+const (
+	// ModeAtomic applies every operation in one transaction or none of them.
+	ModeAtomic = "atomic"
+	// ModeBestEffort applies each operation on its own and reports the ones that failed.
+	ModeBestEffort = "best_effort"
+
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+
+	StatusSuccess    = "success"
+	StatusFailed     = "failed"
+	StatusRolledBack = "rolled_back"
+	StatusSkipped    = "skipped"
+
+	MaxOperations = 500
+)
+
+type (
+	BatchResult struct {
+		Index  int    `json:"index"`
+		Op     string `json:"op"`
+		ID     int64  `json:"id,omitempty"`
+		Status string `json:"status"`
+		Error  string `json:"error,omitempty"`
+	}
+
+	BatchResponse struct {
+		Mode      string        `json:"mode"`
+		Succeeded int           `json:"succeeded"`
+		Failed    int           `json:"failed"`
+		Results   []BatchResult `json:"results"`
+	}
+)
@@ -5,18 +5,42 @@ import (
 
 	"github.com/book-library/entity/author"
 	"github.com/book-library/entity/category"
+	"github.com/book-library/entity/publisher"
+	"github.com/book-library/entity/series"
+	"github.com/book-library/entity/work"
+)
+
+const (
+	SortByRating = "rating"
+
+	// Values of BookSearch.Published. An empty value lists published books only.
+	PublishedTrue  = "true"
+	PublishedFalse = "false"
+	PublishedAny   = "any"
+
+	// PublicationYearBucket is the width in years of the publication year facet buckets.
+	PublicationYearBucket = 10
 )
 
 type (
 	BookInput struct {
-		Title         string     `json:"title"`
-		AuthorID      int64      `json:"author_id"`
-		Description   string     `json:"description"`
-		ISBN          string     `json:"isbn"`
-		PublishedFlag *bool      `json:"published_flag"`
-		CategoryID    int64      `json:"category_id"`
-		CreatedAt     time.Time  `json:"created_at"`
-		UpdatedAt     *time.Time `json:"updated_at"`
+		ID              int64      `json:"-"`
+		Title           string     `json:"title"`
+		AuthorID        int64      `json:"author_id"`
+		Description     string     `json:"description"`
+		ISBN            string     `json:"isbn"`
+		PublishedFlag   *bool      `json:"published_flag"`
+		CategoryID      int64      `json:"category_id"`
+		PublisherID     *int64     `json:"publisher_id"`
+		SeriesID        *int64     `json:"series_id"`
+		SeriesVolume    int        `json:"series_volume"`
+		WorkID          *int64     `json:"work_id"`
+		PublicationYear int        `json:"publication_year"`
+		Edition         string     `json:"edition"`
+		PageCount       int        `json:"page_count"`
+		Language        string     `json:"language"`
+		CreatedAt       time.Time  `json:"created_at"`
+		UpdatedAt       *time.Time `json:"updated_at"`
 	}
 
 	BookResponse struct {
@@ -29,21 +53,151 @@ type (
 		CategoryID          int64      `json:"category_id"`
 		CategoryName        string     `json:"category_name"`
 		CategoryDescription string     `json:"category_description"`
+		PublisherID         int64      `json:"publisher_id"`
+		PublisherName       string     `json:"publisher_name"`
+		SeriesID            int64      `json:"series_id"`
+		SeriesName          string     `json:"series_name"`
+		SeriesVolume        int        `json:"series_volume"`
+		WorkID              int64      `json:"work_id"`
+		WorkTitle           string     `json:"work_title"`
+		RatingAverage       float64    `json:"rating_average"`
+		RatingCount         int        `json:"rating_count"`
 		ISBN                string     `json:"isbn"`
 		PublishedFlag       bool       `json:"published_flag"`
+		PublicationYear     int        `json:"publication_year"`
+		Edition             string     `json:"edition"`
+		PageCount           int        `json:"page_count"`
+		Language            string     `json:"language"`
 		CreatedAt           time.Time  `json:"created_at"`
 		UpdatedAt           *time.Time `json:"updated_at"`
 	}
 
 	BookResponseDetail struct {
-		ID            int64                         `json:"id"`
-		Title         string                        `json:"title"`
-		Description   string                        `json:"description"`
-		Author        author.AuthorResponseJoin     `json:"author"`
-		Category      category.CategoryResponseJoin `json:"category"`
-		ISBN          string                        `json:"isbn"`
-		PublishedFlag bool                          `json:"published_flag"`
-		CreatedAt     time.Time                     `json:"created_at"`
-		UpdatedAt     *time.Time                    `json:"updated_at"`
+		ID              int64                           `json:"id"`
+		Title           string                          `json:"title"`
+		Description     string                          `json:"description"`
+		Author          author.AuthorResponseJoin       `json:"author"`
+		Category        category.CategoryResponseJoin   `json:"category"`
+		Publisher       publisher.PublisherResponseJoin `json:"publisher"`
+		Series          series.SeriesResponseJoin       `json:"series"`
+		Work            work.WorkResponseJoin           `json:"work"`
+		ISBN            string                          `json:"isbn"`
+		PublishedFlag   bool                            `json:"published_flag"`
+		PublicationYear int                             `json:"publication_year"`
+		Edition         string                          `json:"edition"`
+		PageCount       int                             `json:"page_count"`
+		Language        string                          `json:"language"`
+		RatingAverage   float64                         `json:"rating_average"`
+		RatingCount     int                             `json:"rating_count"`
+		CreatedAt       time.Time                       `json:"created_at"`
+		UpdatedAt       *time.Time                      `json:"updated_at"`
+	}
+
+	// BookWorkGroup collapses the editions of one work into a single search result.
+	// Books without a work are returned as a group of their own with WorkID 0.
+	BookWorkGroup struct {
+		WorkID       int64                `json:"work_id"`
+		WorkTitle    string               `json:"work_title"`
+		EditionCount int                  `json:"edition_count"`
+		Edition      BookResponseDetail   `json:"edition"`
+		Editions     []BookResponseDetail `json:"editions,omitempty"`
+	}
+
+	SimilarBookResponse struct {
+		ID            int64    `json:"id"`
+		Title         string   `json:"title"`
+		AuthorID      int64    `json:"author_id"`
+		AuthorName    string   `json:"author_name"`
+		CategoryID    int64    `json:"category_id"`
+		CategoryName  string   `json:"category_name"`
+		RatingAverage float64  `json:"rating_average"`
+		Score         float64  `json:"score"`
+		Reasons       []string `json:"reasons"`
+	}
+
+	// BookSearch filters the book list. Every filter is optional and they combine with AND.
+	// Date ranges include From and exclude To. With Similarity set, Search matches titles and
+	// author names by trigram similarity at that threshold instead of exactly.
+	BookSearch struct {
+		Search              string     `json:"search"`
+		PublisherID         int64      `json:"publisher_id"`
+		PublicationYearFrom int        `json:"publication_year_from"`
+		PublicationYearTo   int        `json:"publication_year_to"`
+		AuthorID            int64      `json:"author_id"`
+		CategoryID          int64      `json:"category_id"`
+		Published           string     `json:"published"`
+		ISBNPrefix          string     `json:"isbn_prefix"`
+		TitleContains       string     `json:"title_contains"`
+		CreatedFrom         *time.Time `json:"created_from"`
+		CreatedTo           *time.Time `json:"created_to"`
+		UpdatedFrom         *time.Time `json:"updated_from"`
+		UpdatedTo           *time.Time `json:"updated_to"`
+		Similarity          float64    `json:"similarity"`
+		Sort                string     `json:"sort"`
+	}
+
+	// BookFacetBucket counts the books sharing one value of a facet. Value is what the matching
+	// list filter takes: an id, the first year of a decade or a published state.
+	BookFacetBucket struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+		Count int64  `json:"count"`
+	}
+
+	// BookFacets are counted with every active filter except the facet's own, so a sidebar can
+	// show the other values of a facet the user already narrowed.
+	BookFacets struct {
+		Category        []BookFacetBucket `json:"category"`
+		Author          []BookFacetBucket `json:"author"`
+		PublicationYear []BookFacetBucket `json:"publication_year"`
+		Availability    []BookFacetBucket `json:"availability"`
+	}
+
+	BookSearchResponse struct {
+		Books  []BookResponseDetail `json:"books"`
+		Facets BookFacets           `json:"facets"`
+	}
+
+	// BookBatchOperation is one entry of a batch request. ID is required for update and delete.
+	BookBatchOperation struct {
+		Op   string    `json:"op"`
+		ID   int64     `json:"id"`
+		Data BookInput `json:"data"`
+	}
+
+	BookBatchInput struct {
+		Mode       string               `json:"mode"`
+		Operations []BookBatchOperation `json:"operations"`
+	}
+
+	// BookRecord is a book in the export and import format. References are carried by name, and
+	// the author by email, so an import resolves them in the target library or creates them.
+	BookRecord struct {
+		Title               string `json:"title"`
+		Description         string `json:"description"`
+		ISBN                string `json:"isbn"`
+		PublishedFlag       bool   `json:"published_flag"`
+		PublicationYear     int    `json:"publication_year"`
+		Edition             string `json:"edition"`
+		PageCount           int    `json:"page_count"`
+		Language            string `json:"language"`
+		AuthorName          string `json:"author_name"`
+		AuthorEmail         string `json:"author_email"`
+		CategoryName        string `json:"category_name"`
+		CategoryDescription string `json:"category_description"`
+		PublisherName       string `json:"publisher_name,omitempty"`
+		SeriesName          string `json:"series_name,omitempty"`
+		SeriesVolume        int    `json:"series_volume,omitempty"`
+		WorkTitle           string `json:"work_title,omitempty"`
+	}
+
+	// BookExport is the body of an export, and of the import that loads it back.
+	BookExport struct {
+		Books []BookRecord `json:"books"`
+	}
+
+	BookImportResponse struct {
+		Created int `json:"created"`
+		Updated int `json:"updated"`
 	}
 )
@@ -4,6 +4,7 @@ import "time"
 
 type (
 	CategoryInput struct {
+		ID          int64      `json:"-"`
 		Name        string     `json:"name"`
 		Description string     `json:"description"`
 		CreatedAt   time.Time  `json:"created_at"`
@@ -28,4 +29,16 @@ type (
 		Name        string `json:"name"`
 		Description string `json:"description"`
 	}
+
+	// CategoryBatchOperation is one entry of a batch request. ID is required for update and delete.
+	CategoryBatchOperation struct {
+		Op   string        `json:"op"`
+		ID   int64         `json:"id"`
+		Data CategoryInput `json:"data"`
+	}
+
+	CategoryBatchInput struct {
+		Mode       string                   `json:"mode"`
+		Operations []CategoryBatchOperation `json:"operations"`
+	}
 )
@@ -0,0 +1,76 @@
+package collection
+
+import "time"
+
+const (
+	VisibilityPublic  = "public"
+	VisibilityPrivate = "private"
+
+	// MemberHeader names the member making the request, who is shown their own private collections.
+	MemberHeader = "X-Member-ID"
+)
+
+type (
+	CollectionInput struct {
+		Name         string     `json:"name"`
+		Description  string     `json:"description"`
+		OwnerID      *int64     `json:"owner_id"`
+		Visibility   string     `json:"visibility"`
+		FeaturedFlag *bool      `json:"featured_flag"`
+		CreatedAt    time.Time  `json:"created_at"`
+		UpdatedAt    *time.Time `json:"updated_at"`
+	}
+
+	CollectionResponse struct {
+		ID           int64      `json:"id"`
+		Name         string     `json:"name"`
+		Description  string     `json:"description"`
+		OwnerID      int64      `json:"owner_id"`
+		OwnerName    string     `json:"owner_name"`
+		Visibility   string     `json:"visibility"`
+		FeaturedFlag bool       `json:"featured_flag"`
+		BookCount    int        `json:"book_count"`
+		CreatedAt    time.Time  `json:"created_at"`
+		UpdatedAt    *time.Time `json:"updated_at"`
+	}
+
+	CollectionResponseDetail struct {
+		ID           int64                     `json:"id"`
+		Name         string                    `json:"name"`
+		Description  string                    `json:"description"`
+		OwnerID      int64                     `json:"owner_id"`
+		OwnerName    string                    `json:"owner_name"`
+		Visibility   string                    `json:"visibility"`
+		FeaturedFlag bool                      `json:"featured_flag"`
+		Books        []CollectionEntryResponse `json:"books"`
+		CreatedAt    time.Time                 `json:"created_at"`
+		UpdatedAt    *time.Time                `json:"updated_at"`
+	}
+
+	// CollectionEntryInput is one book placed in a collection.
+	// Position starts at 1; a zero position appends the book at the end.
+	CollectionEntryInput struct {
+		CollectionID int64     `json:"-"`
+		BookID       int64     `json:"book_id"`
+		Position     int       `json:"position"`
+		Note         string    `json:"note"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+
+	CollectionEntryResponse struct {
+		BookID     int64     `json:"book_id"`
+		Title      string    `json:"title"`
+		AuthorName string    `json:"author_name"`
+		Position   int       `json:"position"`
+		Note       string    `json:"note"`
+		CreatedAt  time.Time `json:"created_at"`
+	}
+
+	CollectionSearch struct {
+		Name         string `json:"name"`
+		OwnerID      int64  `json:"owner_id"`
+		Visibility   string `json:"visibility"`
+		FeaturedOnly bool   `json:"featured_only"`
+		MemberID     int64  `json:"-"`
+	}
+)
@@ -0,0 +1,21 @@
+package health
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+type (
+	Check struct {
+		Name    string      `json:"name"`
+		Status  string      `json:"status"`
+		Latency string      `json:"latency,omitempty"`
+		Error   string      `json:"error,omitempty"`
+		Details interface{} `json:"details,omitempty"`
+	}
+
+	Report struct {
+		Status string  `json:"status"`
+		Checks []Check `json:"checks"`
+	}
+)
@@ -0,0 +1,44 @@
+package idempotency
+
+import "time"
+
+const (
+	KeyHeader      = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+
+	MaxKeyLength = 255
+)
+
+type (
+	IdempotencyInput struct {
+		Scope          string    `json:"scope"`
+		IdempotencyKey string    `json:"idempotency_key"`
+		Owner          string    `json:"owner"`
+		RequestHash    string    `json:"request_hash"`
+		LockedUntil    time.Time `json:"locked_until"`
+		ExpiresAt      time.Time `json:"expires_at"`
+	}
+
+	// IdempotencyRecord is a key taken by a request in a scope. CompletedFlag stays false while
+	// the first request is still running, which holds the key until LockedUntil. Owner tells that
+	// request apart from a retry that took the key over after the lease ran out.
+	IdempotencyRecord struct {
+		Scope          string    `json:"scope"`
+		IdempotencyKey string    `json:"idempotency_key"`
+		Owner          string    `json:"owner"`
+		RequestHash    string    `json:"request_hash"`
+		StatusCode     int       `json:"status_code"`
+		ContentType    string    `json:"content_type"`
+		ResponseBody   string    `json:"response_body"`
+		CompletedFlag  bool      `json:"completed_flag"`
+		CreatedAt      time.Time `json:"created_at"`
+		LockedUntil    time.Time `json:"locked_until"`
+		ExpiresAt      time.Time `json:"expires_at"`
+	}
+
+	StoredResponse struct {
+		StatusCode   int    `json:"status_code"`
+		ContentType  string `json:"content_type"`
+		ResponseBody string `json:"response_body"`
+	}
+)
@@ -0,0 +1,33 @@
+package member
+
+import (
+	"time"
+)
+
+type (
+	MemberInput struct {
+		Name      string     `json:"name"`
+		Email     string     `json:"email"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt *time.Time `json:"updated_at"`
+	}
+
+	MemberResponse struct {
+		ID        int64      `json:"id"`
+		Name      string     `json:"name"`
+		Email     string     `json:"email"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt *time.Time `json:"updated_at"`
+	}
+
+	MemberResponseJoin struct {
+		ID    int64  `json:"member_id"`
+		Name  string `json:"member_name"`
+		Email string `json:"member_email"`
+	}
+
+	MemberSearch struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+)
@@ -0,0 +1,57 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AggregateBook     = "book"
+	AggregateAuthor   = "author"
+	AggregateCategory = "category"
+)
+
+type (
+	OutboxInput struct {
+		ID            int64      `json:"id"`
+		AggregateType string     `json:"aggregate_type"`
+		AggregateID   int64      `json:"aggregate_id"`
+		EventType     string     `json:"event_type"`
+		Payload       string     `json:"payload"`
+		Attempts      int        `json:"attempts"`
+		LastError     string     `json:"last_error"`
+		CreatedAt     time.Time  `json:"created_at"`
+		PublishedAt   *time.Time `json:"published_at"`
+		PublishedSeq  int64      `json:"published_seq"`
+	}
+
+	// OutboxEvent is a recorded domain event as handed to the relay sinks.
+	OutboxEvent struct {
+		ID            int64     `json:"id"`
+		AggregateType string    `json:"aggregate_type"`
+		AggregateID   int64     `json:"aggregate_id"`
+		EventType     string    `json:"event_type"`
+		Payload       string    `json:"payload"`
+		Attempts      int       `json:"attempts"`
+		CreatedAt     time.Time `json:"created_at"`
+		PublishedSeq  int64     `json:"published_seq"`
+	}
+
+	// StreamEvent is the data line of a Server-Sent Event. Its SSE id is the publish sequence,
+	// the position to resume from with Last-Event-ID.
+	StreamEvent struct {
+		ID            int64           `json:"id"`
+		Type          string          `json:"type"`
+		AggregateType string          `json:"aggregate_type"`
+		AggregateID   int64           `json:"aggregate_id"`
+		OccurredAt    time.Time       `json:"occurred_at"`
+		Data          json.RawMessage `json:"data"`
+	}
+)
+
+// AggregateTypes lists the entity types that record outbox events.
+var AggregateTypes = []string{
+	AggregateBook,
+	AggregateAuthor,
+	AggregateCategory,
+}
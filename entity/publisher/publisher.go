@@ -0,0 +1,32 @@
+package publisher
+
+import "time"
+
+type (
+	PublisherInput struct {
+		ID        int64      `json:"-"`
+		Name      string     `json:"name"`
+		Address   string     `json:"address"`
+		Website   string     `json:"website"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt *time.Time `json:"updated_at"`
+	}
+
+	PublisherResponse struct {
+		ID        int64      `json:"id"`
+		Name      string     `json:"name"`
+		Address   string     `json:"address"`
+		Website   string     `json:"website"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt *time.Time `json:"updated_at"`
+	}
+
+	PublisherResponseJoin struct {
+		ID   int64  `json:"publisher_id"`
+		Name string `json:"publisher_name"`
+	}
+
+	PublisherSearch struct {
+		Name string `json:"name"`
+	}
+)
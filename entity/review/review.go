@@ -0,0 +1,45 @@
+package review
+
+import "time"
+
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+type (
+	ReviewInput struct {
+		BookID    int64      `json:"book_id"`
+		MemberID  int64      `json:"member_id"`
+		Rating    int        `json:"rating"`
+		Review    string     `json:"review"`
+		Status    string     `json:"status"`
+		CreatedAt time.Time  `json:"created_at"`
+		UpdatedAt *time.Time `json:"updated_at"`
+	}
+
+	ReviewModerationInput struct {
+		Status string `json:"status"`
+	}
+
+	ReviewResponse struct {
+		ID         int64      `json:"id"`
+		BookID     int64      `json:"book_id"`
+		BookTitle  string     `json:"book_title"`
+		MemberID   int64      `json:"member_id"`
+		MemberName string     `json:"member_name"`
+		Rating     int        `json:"rating"`
+		Review     string     `json:"review"`
+		Status     string     `json:"status"`
+		CreatedAt  time.Time  `json:"created_at"`
+		UpdatedAt  *time.Time `json:"updated_at"`
+	}
+
+	ReviewSearch struct {
+		BookID   int64   `json:"book_id"`
+		MemberID int64   `json:"member_id"`
+		Status   string  `json:"status"`
+		BookIDs  []int64 `json:"-"`
+	}
+)
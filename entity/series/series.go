@@ -0,0 +1,49 @@
+package series
+
+import "time"
+
+type (
+	SeriesInput struct {
+		ID          int64      `json:"-"`
+		Name        string     `json:"name"`
+		Description string     `json:"description"`
+		CreatedAt   time.Time  `json:"created_at"`
+		UpdatedAt   *time.Time `json:"updated_at"`
+	}
+
+	SeriesResponse struct {
+		ID          int64      `json:"id"`
+		Name        string     `json:"name"`
+		Description string     `json:"description"`
+		CreatedAt   time.Time  `json:"created_at"`
+		UpdatedAt   *time.Time `json:"updated_at"`
+	}
+
+	SeriesResponseDetail struct {
+		ID          int64          `json:"id"`
+		Name        string         `json:"name"`
+		Description string         `json:"description"`
+		Volumes     []SeriesVolume `json:"volumes"`
+		CreatedAt   time.Time      `json:"created_at"`
+		UpdatedAt   *time.Time     `json:"updated_at"`
+	}
+
+	SeriesVolume struct {
+		BookID          int64  `json:"book_id"`
+		Title           string `json:"title"`
+		ISBN            string `json:"isbn"`
+		Volume          int    `json:"volume"`
+		PublicationYear int    `json:"publication_year"`
+		PublishedFlag   bool   `json:"published_flag"`
+	}
+
+	SeriesResponseJoin struct {
+		ID     int64  `json:"series_id"`
+		Name   string `json:"series_name"`
+		Volume int    `json:"series_volume"`
+	}
+
+	SeriesSearch struct {
+		Name string `json:"name"`
+	}
+)
@@ -0,0 +1,20 @@
+package suggest
+
+const (
+	TypeBook     = "book"
+	TypeAuthor   = "author"
+	TypeCategory = "category"
+
+	// MinQueryLength is the shortest prefix worth suggesting for; shorter ones get no results.
+	MinQueryLength = 2
+	DefaultLimit   = 5
+	MaxLimit       = 20
+)
+
+type (
+	Suggestion struct {
+		Type  string `json:"type"`
+		ID    int64  `json:"id"`
+		Label string `json:"label"`
+	}
+)
@@ -0,0 +1,117 @@
+package webhook
+
+import "time"
+
+const (
+	EventBookCreated     = "book.created"
+	EventBookUpdated     = "book.updated"
+	EventBookDeleted     = "book.deleted"
+	EventAuthorCreated   = "author.created"
+	EventAuthorUpdated   = "author.updated"
+	EventAuthorDeleted   = "author.deleted"
+	EventCategoryCreated = "category.created"
+	EventCategoryUpdated = "category.updated"
+	EventCategoryDeleted = "category.deleted"
+	// EventLoanOverdue is reserved: subscriptions may ask for it, but the catalog has no loans yet so
+	// nothing publishes it.
+	EventLoanOverdue = "loan.overdue"
+	EventPing        = "webhook.ping"
+
+	DeliveryStatusPending = "pending"
+	DeliveryStatusSuccess = "success"
+	DeliveryStatusFailed  = "failed"
+
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// EventTypes lists every event a subscription may ask for.
+var EventTypes = []string{
+	EventBookCreated,
+	EventBookUpdated,
+	EventBookDeleted,
+	EventAuthorCreated,
+	EventAuthorUpdated,
+	EventAuthorDeleted,
+	EventCategoryCreated,
+	EventCategoryUpdated,
+	EventCategoryDeleted,
+	EventLoanOverdue,
+}
+
+type (
+	WebhookInput struct {
+		TargetURL  string     `json:"target_url"`
+		Secret     string     `json:"secret"`
+		EventTypes []string   `json:"event_types"`
+		ActiveFlag *bool      `json:"active_flag"`
+		CreatedAt  time.Time  `json:"created_at"`
+		UpdatedAt  *time.Time `json:"updated_at"`
+	}
+
+	// WebhookResponse never carries the secret back to the client.
+	WebhookResponse struct {
+		ID         int64      `json:"id"`
+		TargetURL  string     `json:"target_url"`
+		EventTypes []string   `json:"event_types"`
+		ActiveFlag bool       `json:"active_flag"`
+		CreatedAt  time.Time  `json:"created_at"`
+		UpdatedAt  *time.Time `json:"updated_at"`
+	}
+
+	// WebhookSubscription is the internal view used to sign and send deliveries.
+	WebhookSubscription struct {
+		ID         int64
+		TargetURL  string
+		Secret     string
+		EventTypes []string
+		ActiveFlag bool
+	}
+
+	WebhookDeliveryInput struct {
+		ID            int64      `json:"id"`
+		WebhookID     int64      `json:"webhook_id"`
+		EventID       string     `json:"event_id"`
+		EventType     string     `json:"event_type"`
+		Payload       string     `json:"payload"`
+		Status        string     `json:"status"`
+		Attempts      int        `json:"attempts"`
+		ResponseCode  int        `json:"response_code"`
+		LastError     string     `json:"last_error"`
+		CreatedAt     time.Time  `json:"created_at"`
+		DeliveredAt   *time.Time `json:"delivered_at"`
+		NextAttemptAt *time.Time `json:"next_attempt_at"`
+	}
+
+	WebhookDeliveryResponse struct {
+		ID            int64      `json:"id"`
+		WebhookID     int64      `json:"webhook_id"`
+		EventID       string     `json:"event_id"`
+		EventType     string     `json:"event_type"`
+		Payload       string     `json:"payload"`
+		Status        string     `json:"status"`
+		Attempts      int        `json:"attempts"`
+		ResponseCode  int        `json:"response_code"`
+		LastError     string     `json:"last_error"`
+		CreatedAt     time.Time  `json:"created_at"`
+		DeliveredAt   *time.Time `json:"delivered_at"`
+		NextAttemptAt *time.Time `json:"next_attempt_at"`
+	}
+
+	// ResourcePayload is the event data for catalog changes. Attributes holds the submitted input
+	// and is omitted for deletes.
+	ResourcePayload struct {
+		ID         int64       `json:"id,omitempty"`
+		Attributes interface{} `json:"attributes,omitempty"`
+	}
+
+	// Event is the JSON body posted to every subscriber.
+	Event struct {
+		ID         string      `json:"id"`
+		Type       string      `json:"type"`
+		OccurredAt time.Time   `json:"occurred_at"`
+		Data       interface{} `json:"data"`
+	}
+)
@@ -0,0 +1,61 @@
+package work
+
+import "time"
+
+type (
+	WorkInput struct {
+		ID                   int64      `json:"-"`
+		Title                string     `json:"title"`
+		Description          string     `json:"description"`
+		AuthorID             int64      `json:"author_id"`
+		CategoryID           int64      `json:"category_id"`
+		OriginalLanguage     string     `json:"original_language"`
+		FirstPublicationYear int        `json:"first_publication_year"`
+		CreatedAt            time.Time  `json:"created_at"`
+		UpdatedAt            *time.Time `json:"updated_at"`
+	}
+
+	WorkResponse struct {
+		ID                   int64      `json:"id"`
+		Title                string     `json:"title"`
+		Description          string     `json:"description"`
+		AuthorID             int64      `json:"author_id"`
+		CategoryID           int64      `json:"category_id"`
+		OriginalLanguage     string     `json:"original_language"`
+		FirstPublicationYear int        `json:"first_publication_year"`
+		CreatedAt            time.Time  `json:"created_at"`
+		UpdatedAt            *time.Time `json:"updated_at"`
+	}
+
+	WorkResponseDetail struct {
+		ID                   int64         `json:"id"`
+		Title                string        `json:"title"`
+		Description          string        `json:"description"`
+		AuthorID             int64         `json:"author_id"`
+		CategoryID           int64         `json:"category_id"`
+		OriginalLanguage     string        `json:"original_language"`
+		FirstPublicationYear int           `json:"first_publication_year"`
+		Editions             []WorkEdition `json:"editions"`
+		CreatedAt            time.Time     `json:"created_at"`
+		UpdatedAt            *time.Time    `json:"updated_at"`
+	}
+
+	WorkEdition struct {
+		BookID          int64  `json:"book_id"`
+		Title           string `json:"title"`
+		ISBN            string `json:"isbn"`
+		Edition         string `json:"edition"`
+		Language        string `json:"language"`
+		PublicationYear int    `json:"publication_year"`
+		PublishedFlag   bool   `json:"published_flag"`
+	}
+
+	WorkResponseJoin struct {
+		ID    int64  `json:"work_id"`
+		Title string `json:"work_title"`
+	}
+
+	WorkSearch struct {
+		Title string `json:"title"`
+	}
+)
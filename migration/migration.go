@@ -0,0 +1,105 @@
+package migration
+
+import (
+	"embed"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// Every engine has its own directory of migrations under sql, named after the gorm dialect.
+// They share version numbers so schema_migrations reads the same on both.
+//
+//go:embed sql/postgres/*.sql sql/sqlite/*.sql
+var sqlFiles embed.FS
+
+const migrationTableName = "schema_migrations"
+
+type schemaMigration struct {
+	Version   string
+	AppliedAt time.Time
+}
+
+// Migrate applies every embedded migration that is not recorded in schema_migrations yet.
+// Each file runs in its own transaction together with its version record.
+func Migrate(db *gorm.DB) error {
+	err := db.Exec(`CREATE TABLE IF NOT EXISTS ` + migrationTableName + ` (
+		version varchar(255) PRIMARY KEY,
+		applied_at timestamp NOT NULL
+	)`).Error
+	if err != nil {
+		return err
+	}
+
+	pending, err := Pending(db)
+	if err != nil {
+		return err
+	}
+
+	for _, version := range pending {
+		content, err := sqlFiles.ReadFile(dir(db) + "/" + version + ".sql")
+		if err != nil {
+			return err
+		}
+
+		err = db.Transaction(func(trx *gorm.DB) error {
+			if err := trx.Exec(string(content)).Error; err != nil {
+				return err
+			}
+
+			return trx.Table(migrationTableName).Create(&schemaMigration{Version: version, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			log.Error().Err(err).Msgf("failed to apply migration %s", version)
+			return err
+		}
+
+		log.Info().Msgf("applied migration %s", version)
+	}
+
+	return nil
+}
+
+// Pending returns the versions of embedded migrations that have not been applied, in order.
+func Pending(db *gorm.DB) (versions []string, err error) {
+	applied := []string{}
+	if db.Migrator().HasTable(migrationTableName) {
+		err = db.Table(migrationTableName).Pluck("version", &applied).Error
+		if err != nil {
+			return versions, err
+		}
+	}
+
+	appliedSet := map[string]bool{}
+	for _, v := range applied {
+		appliedSet[v] = true
+	}
+
+	for _, version := range available(db) {
+		if !appliedSet[version] {
+			versions = append(versions, version)
+		}
+	}
+
+	return versions, nil
+}
+
+// dir is the directory holding the migrations for the engine behind db.
+func dir(db *gorm.DB) string {
+	return "sql/" + db.Dialector.Name()
+}
+
+func available(db *gorm.DB) (versions []string) {
+	entries, _ := fs.ReadDir(sqlFiles, dir(db))
+	for _, entry := range entries {
+		versions = append(versions, strings.TrimSuffix(entry.Name(), ".sql"))
+	}
+
+	sort.Strings(versions)
+
+	return versions
+}
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS tb_author (
+	id bigserial PRIMARY KEY,
+	name varchar(255) NOT NULL,
+	email varchar(255) NOT NULL,
+	created_at timestamp NOT NULL DEFAULT now(),
+	updated_at timestamp NULL
+);
+
+CREATE TABLE IF NOT EXISTS tb_category (
+	id bigserial PRIMARY KEY,
+	name varchar(255) NOT NULL,
+	description text NOT NULL DEFAULT '',
+	created_at timestamp NOT NULL DEFAULT now(),
+	updated_at timestamp NULL
+);
+
+CREATE TABLE IF NOT EXISTS tb_book (
+	id bigserial PRIMARY KEY,
+	title varchar(255) NOT NULL,
+	author_id bigint NOT NULL REFERENCES tb_author (id),
+	description text NOT NULL DEFAULT '',
+	isbn varchar(32) NOT NULL,
+	published_flag boolean NOT NULL DEFAULT false,
+	category_id bigint NOT NULL REFERENCES tb_category (id),
+	created_at timestamp NOT NULL DEFAULT now(),
+	updated_at timestamp NULL
+);
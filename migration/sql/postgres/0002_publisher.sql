@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS tb_publisher (
+	id bigserial PRIMARY KEY,
+	name varchar(255) NOT NULL,
+	address text NOT NULL DEFAULT '',
+	website varchar(255) NOT NULL DEFAULT '',
+	created_at timestamp NOT NULL DEFAULT now(),
+	updated_at timestamp NULL
+);
+
+ALTER TABLE tb_book
+	ADD COLUMN IF NOT EXISTS publisher_id bigint NULL REFERENCES tb_publisher (id),
+	ADD COLUMN IF NOT EXISTS publication_year integer NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS edition varchar(64) NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS page_count integer NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS language varchar(32) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_tb_book_publisher_id ON tb_book (publisher_id);
+CREATE INDEX IF NOT EXISTS idx_tb_book_publication_year ON tb_book (publication_year);
@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_tb_book_title_trgm ON tb_book USING gin (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_tb_author_name_trgm ON tb_author USING gin (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_tb_category_name_trgm ON tb_category USING gin (name gin_trgm_ops);
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_tb_book_title_prefix ON tb_book (lower(title) text_pattern_ops) WHERE published_flag = true;
+CREATE INDEX IF NOT EXISTS idx_tb_author_name_prefix ON tb_author (lower(name) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_tb_category_name_prefix ON tb_category (lower(name) text_pattern_ops);
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS tb_author (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	name varchar(255) NOT NULL,
+	email varchar(255) NOT NULL,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
+
+CREATE TABLE IF NOT EXISTS tb_category (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	name varchar(255) NOT NULL,
+	description text NOT NULL DEFAULT '',
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
+
+CREATE TABLE IF NOT EXISTS tb_book (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	title varchar(255) NOT NULL,
+	author_id bigint NOT NULL REFERENCES tb_author (id),
+	description text NOT NULL DEFAULT '',
+	isbn varchar(32) NOT NULL,
+	published_flag boolean NOT NULL DEFAULT false,
+	category_id bigint NOT NULL REFERENCES tb_category (id),
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS tb_publisher (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	name varchar(255) NOT NULL,
+	address text NOT NULL DEFAULT '',
+	website varchar(255) NOT NULL DEFAULT '',
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
+
+ALTER TABLE tb_book ADD COLUMN publisher_id bigint NULL REFERENCES tb_publisher (id);
+ALTER TABLE tb_book ADD COLUMN publication_year integer NOT NULL DEFAULT 0;
+ALTER TABLE tb_book ADD COLUMN edition varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE tb_book ADD COLUMN page_count integer NOT NULL DEFAULT 0;
+ALTER TABLE tb_book ADD COLUMN language varchar(32) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_tb_book_publisher_id ON tb_book (publisher_id);
+CREATE INDEX IF NOT EXISTS idx_tb_book_publication_year ON tb_book (publication_year);
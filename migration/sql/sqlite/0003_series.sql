@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS tb_series (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	name varchar(255) NOT NULL,
+	description text NOT NULL DEFAULT '',
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
+
+ALTER TABLE tb_book ADD COLUMN series_id bigint NULL REFERENCES tb_series (id);
+ALTER TABLE tb_book ADD COLUMN series_volume integer NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_tb_book_series_id_volume ON tb_book (series_id, series_volume);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS tb_work (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	title varchar(255) NOT NULL,
+	description text NOT NULL DEFAULT '',
+	author_id bigint NOT NULL REFERENCES tb_author (id),
+	category_id bigint NOT NULL REFERENCES tb_category (id),
+	original_language varchar(32) NOT NULL DEFAULT '',
+	first_publication_year integer NOT NULL DEFAULT 0,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
+
+ALTER TABLE tb_book ADD COLUMN work_id bigint NULL REFERENCES tb_work (id);
+
+CREATE INDEX IF NOT EXISTS idx_tb_book_work_id ON tb_book (work_id);
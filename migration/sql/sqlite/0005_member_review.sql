@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS tb_member (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	name varchar(255) NOT NULL,
+	email varchar(255) NOT NULL,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tb_member_email ON tb_member (lower(email));
+
+CREATE TABLE IF NOT EXISTS tb_review (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	book_id bigint NOT NULL REFERENCES tb_book (id) ON DELETE CASCADE,
+	member_id bigint NOT NULL REFERENCES tb_member (id),
+	rating smallint NOT NULL CHECK (rating BETWEEN 1 AND 5),
+	review text NOT NULL DEFAULT '',
+	status varchar(16) NOT NULL DEFAULT 'pending',
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL,
+	UNIQUE (book_id, member_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tb_review_book_id_status ON tb_review (book_id, status);
+
+ALTER TABLE tb_book ADD COLUMN rating_average real NOT NULL DEFAULT 0;
+ALTER TABLE tb_book ADD COLUMN rating_count integer NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_tb_book_rating ON tb_book (rating_average DESC, rating_count DESC);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS tb_collection (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	name varchar(255) NOT NULL,
+	description text NOT NULL DEFAULT '',
+	owner_id bigint NULL REFERENCES tb_member (id) ON DELETE CASCADE,
+	visibility varchar(16) NOT NULL DEFAULT 'public',
+	featured_flag boolean NOT NULL DEFAULT false,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_tb_collection_owner_id ON tb_collection (owner_id);
+CREATE INDEX IF NOT EXISTS idx_tb_collection_featured ON tb_collection (visibility, featured_flag);
+
+CREATE TABLE IF NOT EXISTS tb_collection_book (
+	collection_id bigint NOT NULL REFERENCES tb_collection (id) ON DELETE CASCADE,
+	book_id bigint NOT NULL REFERENCES tb_book (id) ON DELETE CASCADE,
+	position integer NOT NULL,
+	note text NOT NULL DEFAULT '',
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (collection_id, book_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tb_collection_book_position ON tb_collection_book (collection_id, position);
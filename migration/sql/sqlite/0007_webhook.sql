@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS tb_webhook (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	target_url text NOT NULL,
+	secret varchar(255) NOT NULL,
+	event_types text NOT NULL,
+	active_flag boolean NOT NULL DEFAULT true,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at timestamp NULL
+);
+
+CREATE TABLE IF NOT EXISTS tb_webhook_delivery (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	webhook_id bigint NOT NULL REFERENCES tb_webhook (id) ON DELETE CASCADE,
+	event_id varchar(64) NOT NULL,
+	event_type varchar(64) NOT NULL,
+	payload text NOT NULL,
+	status varchar(16) NOT NULL DEFAULT 'pending',
+	attempts integer NOT NULL DEFAULT 0,
+	response_code integer NOT NULL DEFAULT 0,
+	last_error text NOT NULL DEFAULT '',
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	delivered_at timestamp NULL,
+	next_attempt_at timestamp NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_tb_webhook_delivery_webhook_id ON tb_webhook_delivery (webhook_id, id);
+CREATE INDEX IF NOT EXISTS idx_tb_webhook_delivery_due ON tb_webhook_delivery (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS tb_outbox (
+	id integer PRIMARY KEY AUTOINCREMENT,
+	aggregate_type varchar(32) NOT NULL,
+	aggregate_id bigint NOT NULL,
+	event_type varchar(64) NOT NULL,
+	payload text NOT NULL,
+	attempts integer NOT NULL DEFAULT 0,
+	last_error text NOT NULL DEFAULT '',
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	published_at timestamp NULL,
+	-- published_seq numbers the events in the order the relay published them, which is the order
+	-- event streams resume in. Outbox ids follow the order the events were written instead.
+	published_seq bigint NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_tb_outbox_pending ON tb_outbox (id) WHERE published_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tb_outbox_published_seq ON tb_outbox (published_seq);
+
+-- The relay lease: only the instance named in owner relays the outbox until expires_at, so
+-- events of an aggregate are published by one relay, in order.
+CREATE TABLE IF NOT EXISTS tb_outbox_lease (
+	name varchar(32) PRIMARY KEY,
+	owner varchar(64) NOT NULL DEFAULT '',
+	expires_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO tb_outbox_lease (name, owner, expires_at) VALUES ('relay', '', '1970-01-01 00:00:00') ON CONFLICT (name) DO NOTHING;
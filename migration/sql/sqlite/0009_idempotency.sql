@@ -0,0 +1,19 @@
+-- Keys are taken per scope, a hash of the client, method and path, so clients and routes can not
+-- collide on a key. A request in progress holds its key until locked_until, and only the request
+-- named in owner may extend, complete or release it.
+CREATE TABLE IF NOT EXISTS tb_idempotency_key (
+	scope varchar(64) NOT NULL,
+	idempotency_key varchar(255) NOT NULL,
+	owner varchar(64) NOT NULL,
+	request_hash varchar(64) NOT NULL,
+	status_code integer NOT NULL DEFAULT 0,
+	content_type varchar(255) NOT NULL DEFAULT '',
+	response_body text NOT NULL DEFAULT '',
+	completed_flag boolean NOT NULL DEFAULT false,
+	created_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	locked_until timestamp NOT NULL,
+	expires_at timestamp NOT NULL,
+	PRIMARY KEY (scope, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tb_idempotency_key_expires_at ON tb_idempotency_key (expires_at);
@@ -0,0 +1,3 @@
+-- SQLite has no pg_trgm: similarity() is registered on every connection by the repository
+-- driver and trigram matches scan the table, so there is no index to create.
+SELECT 1;
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/book-library/app/cache"
+	"github.com/rs/zerolog/log"
+)
+
+// setupCache builds the store for CacheDriver. It returns a nil store when caching is off and
+// the hook that closes the store on shutdown.
+func setupCache() (cache.Store, func()) {
+	switch CacheDriver {
+	case cache.DriverNone:
+		return nil, func() {}
+	case cache.DriverMemory:
+		return cache.NewLRU(CacheSize), func() {}
+	case cache.DriverRedis:
+		store := cache.NewRedis(cache.RedisConfig{
+			Addr:     RedisAddr,
+			Password: RedisPassword,
+			DB:       RedisDB,
+			Prefix:   "book-library:",
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := store.Ping(ctx); err != nil {
+			log.Fatal().Err(err).Msg("cannot reach redis on setupCache")
+		}
+
+		return store, func() {
+			if err := store.Close(); err != nil {
+				log.Error().Err(err).Msg("store.Close got an error on setupCache")
+			}
+		}
+	default:
+		log.Fatal().Msgf("unknown CACHE_DRIVER %q on setupCache", CacheDriver)
+		return nil, nil
+	}
+}
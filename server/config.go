@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/book-library/app/ratelimit"
 	"github.com/spf13/viper"
 )
 
@@ -23,9 +24,41 @@ var (
 	DbName                  string
 	DbPort                  string
 	AppPort                 int
+	AppEnv                  string
+	GrpcPort                int
+	TracingExporter         string
+	TracingServiceName      string
+	ReadinessTimeout        time.Duration
+	ShutdownDrainDelay      time.Duration
 	DbMaxOpenConnection     int
 	DbMaxIdleConnection     int
 	DbConnectionMaxLifeTime time.Duration
+	WebhookMaxAttempts      int
+	WebhookRetryBackoff     time.Duration
+	WebhookTimeout          time.Duration
+	WebhookPollInterval     time.Duration
+	WebhookBatchSize        int
+	OutboxPollInterval      time.Duration
+	OutboxBatchSize         int
+	OutboxLeaseTTL          time.Duration
+	EventStreamBufferSize   int
+	EventStreamPageSize     int
+	EventStreamPollInterval time.Duration
+	RateLimitRPS            float64
+	RateLimitBurst          int
+	RateLimitRoutes         string
+	RateLimitAPIKeys        string
+	RateLimitMaxBuckets     int
+	RateLimitTrustedProxies string
+	CacheDriver             string
+	CacheTTL                time.Duration
+	CacheSize               int
+	RedisAddr               string
+	RedisPassword           string
+	RedisDB                 int
+	IdempotencyTTL          time.Duration
+	IdempotencyLease        time.Duration
+	IdempotencyPurgePeriod  time.Duration
 )
 
 func SecretConfig() {
@@ -37,11 +70,84 @@ func SecretConfig() {
 	DbPort = viper.GetString("DB_PORT")
 
 	AppPort = viper.GetInt("APP_PORT")
+
+	viper.SetDefault("APP_ENV", "production")
+	AppEnv = viper.GetString("APP_ENV")
+
+	viper.SetDefault("GRPC_PORT", 9090)
+	GrpcPort = viper.GetInt("GRPC_PORT")
+
+	viper.SetDefault("TRACING_EXPORTER", "none")
+	viper.SetDefault("TRACING_SERVICE_NAME", "book-library")
+	TracingExporter = viper.GetString("TRACING_EXPORTER")
+	TracingServiceName = viper.GetString("TRACING_SERVICE_NAME")
+
+	viper.SetDefault("READINESS_TIMEOUT", 2)
+	viper.SetDefault("SHUTDOWN_DRAIN_DELAY", 5)
+	ReadinessTimeout = time.Second * time.Duration(viper.GetInt("READINESS_TIMEOUT"))
+	ShutdownDrainDelay = time.Second * time.Duration(viper.GetInt("SHUTDOWN_DRAIN_DELAY"))
 	DbMaxOpenConnection = viper.GetInt("DB_MAX_OPEN_CONNECTION")
 	DbMaxIdleConnection = viper.GetInt("DB_MAX_IDLE_CONNECTION")
 
 	viper.SetDefault("DB_CONNECTION_MAX_LIFE_TIME", time.Second*time.Duration(300))
 	DbConnectionMaxLifeTime = time.Second * time.Duration(viper.GetInt("DB_CONNECTION_MAX_LIFE_TIME"))
+
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 5)
+	viper.SetDefault("WEBHOOK_RETRY_BACKOFF", 1)
+	viper.SetDefault("WEBHOOK_TIMEOUT", 10)
+	WebhookMaxAttempts = viper.GetInt("WEBHOOK_MAX_ATTEMPTS")
+	WebhookRetryBackoff = time.Second * time.Duration(viper.GetInt("WEBHOOK_RETRY_BACKOFF"))
+	WebhookTimeout = time.Second * time.Duration(viper.GetInt("WEBHOOK_TIMEOUT"))
+
+	viper.SetDefault("WEBHOOK_POLL_INTERVAL", 1)
+	viper.SetDefault("WEBHOOK_BATCH_SIZE", 20)
+	WebhookPollInterval = time.Second * time.Duration(viper.GetInt("WEBHOOK_POLL_INTERVAL"))
+	WebhookBatchSize = viper.GetInt("WEBHOOK_BATCH_SIZE")
+
+	viper.SetDefault("OUTBOX_POLL_INTERVAL", 1)
+	viper.SetDefault("OUTBOX_BATCH_SIZE", 100)
+	OutboxPollInterval = time.Second * time.Duration(viper.GetInt("OUTBOX_POLL_INTERVAL"))
+	OutboxBatchSize = viper.GetInt("OUTBOX_BATCH_SIZE")
+
+	viper.SetDefault("OUTBOX_LEASE_TTL", 30)
+	OutboxLeaseTTL = time.Second * time.Duration(viper.GetInt("OUTBOX_LEASE_TTL"))
+
+	viper.SetDefault("EVENT_STREAM_BUFFER_SIZE", 64)
+	viper.SetDefault("EVENT_STREAM_PAGE_SIZE", 500)
+	viper.SetDefault("EVENT_STREAM_POLL_INTERVAL", 1)
+	EventStreamBufferSize = viper.GetInt("EVENT_STREAM_BUFFER_SIZE")
+	EventStreamPageSize = viper.GetInt("EVENT_STREAM_PAGE_SIZE")
+	EventStreamPollInterval = time.Second * time.Duration(viper.GetInt("EVENT_STREAM_POLL_INTERVAL"))
+
+	viper.SetDefault("RATE_LIMIT_RPS", 10)
+	viper.SetDefault("RATE_LIMIT_BURST", 20)
+	viper.SetDefault("RATE_LIMIT_ROUTES", "GET /healthz=0,GET /readyz=0,GET /metrics=0")
+	viper.SetDefault("RATE_LIMIT_MAX_BUCKETS", 100000)
+	viper.SetDefault("RATE_LIMIT_TRUSTED_PROXIES", ratelimit.DefaultTrustedProxies)
+	RateLimitRPS = viper.GetFloat64("RATE_LIMIT_RPS")
+	RateLimitBurst = viper.GetInt("RATE_LIMIT_BURST")
+	RateLimitRoutes = viper.GetString("RATE_LIMIT_ROUTES")
+	RateLimitAPIKeys = viper.GetString("RATE_LIMIT_API_KEYS")
+	RateLimitMaxBuckets = viper.GetInt("RATE_LIMIT_MAX_BUCKETS")
+	RateLimitTrustedProxies = viper.GetString("RATE_LIMIT_TRUSTED_PROXIES")
+
+	viper.SetDefault("CACHE_DRIVER", "memory")
+	viper.SetDefault("CACHE_TTL", 60)
+	viper.SetDefault("CACHE_SIZE", 10000)
+	viper.SetDefault("REDIS_ADDR", "localhost:6379")
+	CacheDriver = viper.GetString("CACHE_DRIVER")
+	CacheTTL = time.Second * time.Duration(viper.GetInt("CACHE_TTL"))
+	CacheSize = viper.GetInt("CACHE_SIZE")
+	RedisAddr = viper.GetString("REDIS_ADDR")
+	RedisPassword = viper.GetString("REDIS_PASSWORD")
+	RedisDB = viper.GetInt("REDIS_DB")
+
+	viper.SetDefault("IDEMPOTENCY_TTL", 86400)
+	viper.SetDefault("IDEMPOTENCY_LEASE", 60)
+	viper.SetDefault("IDEMPOTENCY_PURGE_INTERVAL", 3600)
+	IdempotencyTTL = time.Second * time.Duration(viper.GetInt("IDEMPOTENCY_TTL"))
+	IdempotencyLease = time.Second * time.Duration(viper.GetInt("IDEMPOTENCY_LEASE"))
+	IdempotencyPurgePeriod = time.Second * time.Duration(viper.GetInt("IDEMPOTENCY_PURGE_INTERVAL"))
 }
 
 func GetPostgresDSN() string {
@@ -54,6 +160,13 @@ func GetPostgresDSN() string {
 	})
 }
 
+// GetSQLiteDSN opens DB_NAME as the database file. Foreign keys are switched on for the cascades
+// of the schema, and transactions take the write lock when they begin so concurrent writers
+// wait for each other instead of failing.
+func GetSQLiteDSN() string {
+	return fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000&_txlock=immediate&_journal_mode=WAL", DbName)
+}
+
 func writePostgreDSN(dsn dsnConfig) string {
 	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable", dsn.host, dsn.user, dsn.password, dsn.db, dsn.port)
 }
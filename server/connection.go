@@ -2,11 +2,12 @@ package server
 
 import (
 	"database/sql"
-	"fmt"
 
+	"github.com/book-library/app/repository"
 	_ "github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	gormLogger "gorm.io/gorm/logger"
 )
@@ -16,10 +17,11 @@ func DBConnection() *gorm.DB {
 }
 
 func makeConnection() *gorm.DB {
-	dsn := GetPostgresDSN()
+	if DriverName == repository.DriverSQLite {
+		return makeSQLiteConnection()
+	}
 
-	fmt.Println(dsn)
-	fmt.Println(DriverName)
+	dsn := GetPostgresDSN()
 
 	db, err := sql.Open(DriverName, dsn)
 	if err != nil {
@@ -44,3 +46,25 @@ func makeConnection() *gorm.DB {
 
 	return dbGorm
 }
+
+// makeSQLiteConnection opens the SQLite file DB_NAME with the driver that adds the functions the
+// repositories use.
+func makeSQLiteConnection() *gorm.DB {
+	db, err := sql.Open(repository.SQLiteDriverName, GetSQLiteDSN())
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot open sqlite on makeSQLiteConnection")
+	}
+
+	db.SetMaxOpenConns(DbMaxOpenConnection)
+	db.SetMaxIdleConns(DbMaxIdleConnection)
+	db.SetConnMaxLifetime(DbConnectionMaxLifeTime)
+
+	dbGorm, err := gorm.Open(sqlite.New(sqlite.Config{Conn: db}), &gorm.Config{
+		Logger: gormLogger.Default.LogMode(gormLogger.Info),
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot gorm.Open on makeSQLiteConnection")
+	}
+
+	return dbGorm
+}
@@ -13,13 +13,28 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func startServerWithGracefulShutdown(r *chi.Mux) {
+// shutdownHooks are run by startServerWithGracefulShutdown. NotReady hooks run as soon as the
+// signal arrives; the server then keeps serving for ShutdownDrainDelay so load balancers see
+// the failing readiness probe before connections are refused. Drain hooks run when
+// server.Shutdown starts so long-lived connections such as event streams can end, because
+// Shutdown waits for every open request. Stop hooks run in order once the server has stopped.
+type shutdownHooks struct {
+	notReady []func()
+	drain    []func()
+	stop     []func()
+}
+
+func startServerWithGracefulShutdown(r *chi.Mux, hooks shutdownHooks) {
 	addr := fmt.Sprintf(":%d", AppPort)
 	server := &http.Server{
 		Addr:    addr,
 		Handler: r,
 	}
 
+	for _, hook := range hooks.drain {
+		server.RegisterOnShutdown(hook)
+	}
+
 	// Create server context
 	serverCtx, cancelServerCtx := context.WithCancel(context.Background())
 
@@ -29,6 +44,15 @@ func startServerWithGracefulShutdown(r *chi.Mux) {
 	go func() {
 		<-sig
 
+		for _, hook := range hooks.notReady {
+			hook()
+		}
+
+		if ShutdownDrainDelay > 0 {
+			log.Info().Msgf("draining traffic for %s before shutting down", ShutdownDrainDelay)
+			time.Sleep(ShutdownDrainDelay)
+		}
+
 		graceful_timeout := 10
 
 		shutDownCtx, cancel := context.WithTimeout(serverCtx, time.Duration(graceful_timeout)*time.Second)
@@ -46,6 +70,10 @@ func startServerWithGracefulShutdown(r *chi.Mux) {
 			log.Fatal().Err(err).Msgf("error on shutting down gracefully: %v", err)
 		}
 
+		for _, hook := range hooks.stop {
+			hook()
+		}
+
 		cancelServerCtx()
 	}()
 
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+)
+
+// startGRPCServer listens on GrpcPort in the background. The caller stops it through the
+// shutdown hooks of startServerWithGracefulShutdown.
+func startGRPCServer(server *grpc.Server) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", GrpcPort))
+	if err != nil {
+		log.Fatal().Err(err).Msgf("error on listening grpc port: %v", err)
+	}
+
+	go func() {
+		log.Info().Msgf("starting book-library grpc in port: %d", GrpcPort)
+
+		if err := server.Serve(listener); err != nil {
+			log.Fatal().Err(err).Msgf("error on starting up grpc server: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"github.com/book-library/app/metrics"
+	"github.com/book-library/app/repository"
+	"github.com/book-library/app/tracing"
+	"github.com/book-library/migration"
+	"github.com/rs/zerolog/log"
+)
+
+type repositories struct {
+	book        repository.BookLibraryRepositoryI
+	transaction repository.TransactionRepositoryI
+	author      repository.AuthorRepositoryI
+	category    repository.CategoryRepositoryI
+	publisher   repository.PublisherRepositoryI
+	series      repository.SeriesRepositoryI
+	work        repository.WorkRepositoryI
+	member      repository.MemberRepositoryI
+	review      repository.ReviewRepositoryI
+	collection  repository.CollectionRepositoryI
+	webhook     repository.WebhookRepositoryI
+	outbox      repository.OutboxRepositoryI
+	health      repository.HealthRepositoryI
+	idempotency repository.IdempotencyRepositoryI
+	suggest     repository.SuggestRepositoryI
+}
+
+// setupRepositories builds the repositories for DriverName. With the memory driver nothing is
+// connected or migrated and the data lives until the process exits.
+func setupRepositories() repositories {
+	if DriverName == repository.DriverMemory {
+		log.Warn().Msg("DRIVER_NAME is memory, data is not persisted")
+
+		store := repository.NewMemoryStore()
+		return repositories{
+			book:        repository.NewMemoryBookLibraryRepository(store),
+			transaction: repository.NewMemoryTransactionRepository(store),
+			author:      repository.NewMemoryAuthorRepository(store),
+			category:    repository.NewMemoryCategoryRepository(store),
+			publisher:   repository.NewMemoryPublisherRepository(store),
+			series:      repository.NewMemorySeriesRepository(store),
+			work:        repository.NewMemoryWorkRepository(store),
+			member:      repository.NewMemoryMemberRepository(store),
+			review:      repository.NewMemoryReviewRepository(store),
+			collection:  repository.NewMemoryCollectionRepository(store),
+			webhook:     repository.NewMemoryWebhookRepository(store),
+			outbox:      repository.NewMemoryOutboxRepository(store),
+			health:      repository.NewMemoryHealthRepository(),
+			idempotency: repository.NewMemoryIdempotencyRepository(store),
+			suggest:     repository.NewMemorySuggestRepository(store),
+		}
+	}
+
+	dbConn := DBConnection()
+	if err := dbConn.Use(tracing.GormPlugin{}); err != nil {
+		log.Fatal().Err(err).Msg("cannot register tracing plugin on setupRepositories")
+	}
+
+	if err := migration.Migrate(dbConn); err != nil {
+		log.Fatal().Err(err).Msg("cannot run migration on setupRepositories")
+	}
+
+	sqlDB, err := dbConn.DB()
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot get sql.DB on setupRepositories")
+	}
+	metrics.RegisterDBStats(sqlDB, DbName)
+
+	return repositories{
+		book:        repository.NewBookLibraryRepository(dbConn),
+		transaction: repository.NewTransactionRepository(dbConn),
+		author:      repository.NewAuthorRepository(dbConn),
+		category:    repository.NewCategoryRepository(dbConn),
+		publisher:   repository.NewPublisherRepository(dbConn),
+		series:      repository.NewSeriesRepository(dbConn),
+		work:        repository.NewWorkRepository(dbConn),
+		member:      repository.NewMemberRepository(dbConn),
+		review:      repository.NewReviewRepository(dbConn),
+		collection:  repository.NewCollectionRepository(dbConn),
+		webhook:     repository.NewWebhookRepository(dbConn),
+		outbox:      repository.NewOutboxRepository(dbConn),
+		health:      repository.NewHealthRepository(dbConn),
+		idempotency: repository.NewIdempotencyRepository(dbConn),
+		suggest:     repository.NewSuggestRepository(dbConn),
+	}
+}
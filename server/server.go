@@ -1,41 +1,155 @@
 package server
 
 import (
+	"context"
+
 	"github.com/book-library/app/delivery"
+	"github.com/book-library/app/delivery/rpc"
 	"github.com/book-library/app/http"
+	"github.com/book-library/app/metrics"
 	"github.com/book-library/app/repository"
 	"github.com/book-library/app/usecase"
 	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
 )
 
 func Start() {
 	SetConfig(".", ".env")
 
-	dbConn := DBConnection()
+	shutdownTracing := setupTracing()
 
 	// Repository
-	bookRepo := repository.NewBookLibraryRepository(dbConn)
-	transactionRepo := repository.NewTransactionRepository(dbConn)
-	authorRepo := repository.NewAuthorRepository(dbConn)
-	categoryRepo := repository.NewCategoryRepository(dbConn)
+	repos := setupRepositories()
+	bookRepo := repos.book
+	transactionRepo := repos.transaction
+	authorRepo := repos.author
+	categoryRepo := repos.category
+	publisherRepo := repos.publisher
+	seriesRepo := repos.series
+	workRepo := repos.work
+	memberRepo := repos.member
+	reviewRepo := repos.review
+	collectionRepo := repos.collection
+	webhookRepo := repos.webhook
+	outboxRepo := repos.outbox
+	healthRepo := repos.health
+	idempotencyRepo := repos.idempotency
+	suggestRepo := repos.suggest
+
+	cacheStore, closeCache := setupCache()
+	if cacheStore != nil {
+		repoCache := repository.NewCache(cacheStore, CacheTTL)
+		transactionRepo = repository.NewCachedTransactionRepository(transactionRepo, repoCache)
+		bookRepo = repository.NewCachedBookLibraryRepository(bookRepo, repoCache)
+		authorRepo = repository.NewCachedAuthorRepository(authorRepo, repoCache)
+		categoryRepo = repository.NewCachedCategoryRepository(categoryRepo, repoCache)
+	}
+
+	metrics.RegisterBusinessGauge("books_total", "Number of books in the catalog.", bookRepo.CountBooks)
 
 	// Usecase
-	bookUC := usecase.NewbookLibraryService(bookRepo, transactionRepo, authorRepo, categoryRepo)
-	authorUC := usecase.NewAuthorService(authorRepo, transactionRepo, bookRepo)
-	categoryUC := usecase.NewCategoryService(categoryRepo, transactionRepo, bookRepo)
+	webhookUC := usecase.NewWebhookService(webhookRepo, transactionRepo, usecase.WebhookConfig{
+		MaxAttempts:  WebhookMaxAttempts,
+		RetryBackoff: WebhookRetryBackoff,
+		Timeout:      WebhookTimeout,
+		PollInterval: WebhookPollInterval,
+		BatchSize:    WebhookBatchSize,
+	})
+	bookUC := usecase.NewbookLibraryService(bookRepo, transactionRepo, authorRepo, categoryRepo, publisherRepo, seriesRepo, workRepo, outboxRepo)
+	authorUC := usecase.NewAuthorService(authorRepo, transactionRepo, bookRepo, outboxRepo)
+	categoryUC := usecase.NewCategoryService(categoryRepo, transactionRepo, bookRepo, outboxRepo)
+	publisherUC := usecase.NewPublisherService(publisherRepo, transactionRepo, bookRepo)
+	seriesUC := usecase.NewSeriesService(seriesRepo, transactionRepo)
+	workUC := usecase.NewWorkService(workRepo, transactionRepo, bookRepo, outboxRepo)
+	memberUC := usecase.NewMemberService(memberRepo, transactionRepo, reviewRepo)
+	reviewUC := usecase.NewReviewService(reviewRepo, transactionRepo, bookRepo, memberRepo)
+	collectionUC := usecase.NewCollectionService(collectionRepo, transactionRepo, bookRepo, memberRepo)
+	healthUC := usecase.NewHealthService(healthRepo, ReadinessTimeout)
+	suggestUC := usecase.NewSuggestService(suggestRepo)
+	idempotencyUC := usecase.NewIdempotencyService(idempotencyRepo, usecase.IdempotencyConfig{
+		TTL:           IdempotencyTTL,
+		Lease:         IdempotencyLease,
+		PurgeInterval: IdempotencyPurgePeriod,
+	})
 
 	// Handler
 	bookHandler := delivery.NewBookHandler(bookUC)
 	authorHandler := delivery.NewAuthorHandler(authorUC)
 	categoryHandler := delivery.NewCategoryHandler(categoryUC)
+	publisherHandler := delivery.NewPublisherHandler(publisherUC)
+	seriesHandler := delivery.NewSeriesHandler(seriesUC)
+	workHandler := delivery.NewWorkHandler(workUC)
+	memberHandler := delivery.NewMemberHandler(memberUC)
+	reviewHandler := delivery.NewReviewHandler(reviewUC)
+	collectionHandler := delivery.NewCollectionHandler(collectionUC)
+	webhookHandler := delivery.NewWebhookHandler(webhookUC)
+	healthHandler := delivery.NewHealthHandler(healthUC)
+	suggestHandler := delivery.NewSuggestHandler(suggestUC)
+	graphQLHandler, err := delivery.NewGraphQLHandler(bookUC, authorUC, categoryUC, reviewUC, AppEnv == "development")
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot build graphql schema on Start")
+	}
+
+	// Outbox relay
+	eventStreamUC := usecase.NewEventStreamService(outboxRepo, usecase.EventStreamConfig{
+		BufferSize:   EventStreamBufferSize,
+		PageSize:     EventStreamPageSize,
+		PollInterval: EventStreamPollInterval,
+	})
+	outboxRelay := usecase.NewOutboxRelay(outboxRepo, usecase.OutboxConfig{
+		PollInterval: OutboxPollInterval,
+		BatchSize:    OutboxBatchSize,
+		LeaseTTL:     OutboxLeaseTTL,
+	}, usecase.NewLogSink(), usecase.NewWebhookSink(webhookUC))
+
+	relayCtx, stopRelay := context.WithCancel(log.Logger.WithContext(context.Background()))
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		outboxRelay.Run(relayCtx)
+	}()
+	webhookDone := make(chan struct{})
+	go func() {
+		defer close(webhookDone)
+		webhookUC.Run(relayCtx)
+	}()
+	go idempotencyUC.Run(relayCtx)
+
+	eventHandler := delivery.NewEventHandler(eventStreamUC)
+
+	// gRPC
+	grpcServer, grpcHealth := rpc.NewServer(bookUC, authorUC, categoryUC)
+	startGRPCServer(grpcServer)
 
 	r := chi.NewRouter()
-	Set(r)
+	limiter := newRateLimiter()
+	Set(r, limiter)
+	r.Use(delivery.IdempotencyMiddleware(idempotencyUC, limiter.ClientKey))
 
 	// Router
 	http.BookPath(r, bookHandler)
 	http.AuthorPath(r, authorHandler)
 	http.CategoryPath(r, categoryHandler)
+	http.PublisherPath(r, publisherHandler)
+	http.SeriesPath(r, seriesHandler)
+	http.WorkPath(r, workHandler)
+	http.MemberPath(r, memberHandler)
+	http.ReviewPath(r, reviewHandler)
+	http.CollectionPath(r, collectionHandler)
+	http.WebhookPath(r, webhookHandler)
+	http.EventPath(r, eventHandler)
+	http.SuggestPath(r, suggestHandler)
+	http.GraphQLPath(r, graphQLHandler)
+	http.MetricsPath(r)
+	http.HealthPath(r, healthHandler)
 
-	startServerWithGracefulShutdown(r)
+	startServerWithGracefulShutdown(r, shutdownHooks{
+		notReady: []func(){healthUC.StartDraining, grpcHealth.Shutdown},
+		drain:    []func(){eventStreamUC.Close},
+		stop: []func(){grpcServer.GracefulStop, func() {
+			stopRelay()
+			<-relayDone
+			<-webhookDone
+		}, closeCache, shutdownTracing},
+	})
 }
@@ -2,9 +2,14 @@ package server
 
 import (
 	"errors"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/book-library/app/metrics"
+	"github.com/book-library/app/ratelimit"
+	"github.com/book-library/app/tracing"
 	"github.com/book-library/logger"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -13,22 +18,43 @@ import (
 	"github.com/spf13/viper"
 )
 
-func Set(r *chi.Mux) {
+func Set(r *chi.Mux, limiter *ratelimit.Limiter) {
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(tracing.Middleware)
+	r.Use(metrics.Middleware)
+	r.Use(newRealIP())
+	r.Use(limiter.Middleware(r))
 	r.Use(middleware.Recoverer)
 	r.Use(logger.LoggingMiddleware)
 
 	corsOptions := cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedHeaders:   []string{"*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}
 	r.Use(cors.Handler(corsOptions))
 }
 
+func newRateLimiter() *ratelimit.Limiter {
+	routes, err := ratelimit.ParseRoutes(RateLimitRoutes)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid RATE_LIMIT_ROUTES")
+	}
+
+	return ratelimit.NewLimiter(ratelimit.Limit{Rate: RateLimitRPS, Burst: RateLimitBurst}, routes, strings.Split(RateLimitAPIKeys, ","), RateLimitMaxBuckets)
+}
+
+func newRealIP() func(http.Handler) http.Handler {
+	trusted, err := ratelimit.ParseNetworks(RateLimitTrustedProxies)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid RATE_LIMIT_TRUSTED_PROXIES")
+	}
+
+	return ratelimit.RealIP(trusted)
+}
+
 func SetConfig(dirpath string, filename string) {
 	filePath := filepath.Join(dirpath, filename)
 	fileExist := isFileExist(filePath)
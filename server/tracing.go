@@ -0,0 +1,30 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/book-library/app/tracing"
+	"github.com/rs/zerolog/log"
+)
+
+// setupTracing installs the global tracer provider for TracingExporter and returns the
+// hook that flushes pending spans on shutdown.
+func setupTracing() func() {
+	exporter, err := tracing.NewExporter(context.Background(), TracingExporter)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create tracing exporter on setupTracing")
+	}
+
+	provider := tracing.NewProvider(TracingServiceName, exporter)
+	tracing.Install(provider)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msg("provider.Shutdown got an error on setupTracing")
+		}
+	}
+}